@@ -14,11 +14,14 @@ flags=""
 [[ -n "$INPUT_TAG_GROUPS" ]] && flags="$flags--tag-groups $INPUT_TAG_GROUPS "
 [[ -n "$INPUT_TAG" ]] && flags="$flags--tag $INPUT_TAG "
 [[ -n "$INPUT_SKIP_TAGS" ]] && flags="$flags--skip-tags $INPUT_SKIP_TAGS "
+[[ -n "$INPUT_PROTECTED_TAGS" ]] && flags="$flags--protected-tags $INPUT_PROTECTED_TAGS "
 [[ -n "$INPUT_SKIP_DIRS" ]] && flags="$flags--skip-dirs $INPUT_SKIP_DIRS "
 [[ -n "$INPUT_SKIP_RESOURCE_TYPES" ]] && flags="$flags--skip-resource-types $INPUT_SKIP_RESOURCE_TYPES "
 [[ -n "$INPUT_CUSTOM_TAGS" ]] && flags="$flags--custom-tagging $INPUT_CUSTOM_TAGS "
 [[ -n "$INPUT_OUTPUT_FORMAT" ]] && flags="$flags--output $INPUT_OUTPUT_FORMAT "
 [[ -n "$INPUT_CONFIG_FILE" ]] && flags="$flags--config-file $INPUT_CONFIG_FILE "
+[[ -n "$INPUT_PROVIDER_SCHEMAS" ]] && flags="$flags--provider-schemas $INPUT_PROVIDER_SCHEMAS "
+[[ -n "$INPUT_TERRAGRUNT_TAGS_KEY" ]] && flags="$flags--terragrunt-tags-key $INPUT_TERRAGRUNT_TAGS_KEY "
 [[ -n "$INPUT_LOG_LEVEL" ]] && export LOG_LEVEL=$INPUT_LOG_LEVEL
 
 [[ -d ".yor_plugins" ]] && echo "Directory .yor_plugins exists, and will be overwritten by yor. Please rename this directory."
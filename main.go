@@ -11,6 +11,7 @@ import (
 	"github.com/bridgecrewio/yor/src/common/logger"
 	"github.com/bridgecrewio/yor/src/common/reports"
 	"github.com/bridgecrewio/yor/src/common/runner"
+	"github.com/bridgecrewio/yor/src/common/structure"
 	"github.com/bridgecrewio/yor/src/common/tagging"
 	"github.com/bridgecrewio/yor/src/common/tagging/tags"
 	"github.com/bridgecrewio/yor/src/common/tagging/utils"
@@ -31,6 +32,7 @@ func main() {
 			listTagsCommand(),
 			listTagGroupsCommand(),
 			tagCommand(),
+			migrateCommand(),
 		},
 	}
 	err := app.Run(os.Args)
@@ -80,6 +82,7 @@ func tagCommand() *cli.Command {
 	directoryArg := "directory"
 	tagArg := "tags"
 	skipTagsArg := "skip-tags"
+	protectedTagsArg := "protected-tags"
 	customTaggingArg := "custom-tagging"
 	skipDirsArg := "skip-dirs"
 	outputArg := "output"
@@ -92,6 +95,12 @@ func tagCommand() *cli.Command {
 	dryRunArgs := "dry-run"
 	validateModeArgs := "validate"
 	tagLocalModules := "tag-local-modules"
+	inspectModulesArgs := "inspect-modules"
+	cdkOutArgs := "cdk-out"
+	asgPropagateAtLaunchArgs := "asg-propagate-at-launch"
+	useDefaultTagsArgs := "use-default-tags"
+	providerSchemasArgs := "provider-schemas"
+	terragruntTagsKeyArgs := "terragrunt-tags-key"
 	tagPrefix := "tag-prefix"
 	noColor := "no-color"
 	useCodeowners := "use-code-owners"
@@ -103,25 +112,32 @@ func tagCommand() *cli.Command {
 		UseShortOptionHandling: true,
 		Action: func(c *cli.Context) error {
 			options := clioptions.TagOptions{
-				Directory:         c.String(directoryArg),
-				Tag:               c.StringSlice(tagArg),
-				SkipTags:          c.StringSlice(skipTagsArg),
-				CustomTagging:     c.StringSlice(customTaggingArg),
-				SkipDirs:          c.StringSlice(skipDirsArg),
-				Output:            c.String(outputArg),
-				OutputJSONFile:    c.String(outputJSONFileArg),
-				TagGroups:         c.StringSlice(tagGroupArg),
-				ConfigFile:        c.String(externalConfPath),
-				SkipResourceTypes: c.StringSlice(skipResourceTypesArg),
-				SkipResources:     c.StringSlice(skipResourcesArg),
-				Parsers:           c.StringSlice(parsersArgs),
-				DryRun:            c.Bool(dryRunArgs),
-				ValidateMode:      c.Bool(validateModeArgs),
-				TagLocalModules:   c.Bool(tagLocalModules),
-				TagPrefix:         c.String(tagPrefix),
-				NoColor:           c.Bool(noColor),
-				UseCodeOwners:     c.Bool(useCodeowners),
-				NonRecursive:      c.Bool(nonRecursiveArgs),
+				Directory:            c.String(directoryArg),
+				Tag:                  c.StringSlice(tagArg),
+				SkipTags:             c.StringSlice(skipTagsArg),
+				ProtectedTags:        c.StringSlice(protectedTagsArg),
+				CustomTagging:        c.StringSlice(customTaggingArg),
+				SkipDirs:             c.StringSlice(skipDirsArg),
+				Output:               c.String(outputArg),
+				OutputJSONFile:       c.String(outputJSONFileArg),
+				TagGroups:            c.StringSlice(tagGroupArg),
+				ConfigFile:           c.String(externalConfPath),
+				SkipResourceTypes:    c.StringSlice(skipResourceTypesArg),
+				SkipResources:        c.StringSlice(skipResourcesArg),
+				Parsers:              c.StringSlice(parsersArgs),
+				DryRun:               c.Bool(dryRunArgs),
+				ValidateMode:         c.Bool(validateModeArgs),
+				TagLocalModules:      c.Bool(tagLocalModules),
+				InspectModules:       c.Bool(inspectModulesArgs),
+				CdkOut:               c.Bool(cdkOutArgs),
+				ASGPropagateAtLaunch: c.Bool(asgPropagateAtLaunchArgs),
+				UseDefaultTags:       c.Bool(useDefaultTagsArgs),
+				ProviderSchemas:      c.String(providerSchemasArgs),
+				TerragruntTagsKey:    c.String(terragruntTagsKeyArgs),
+				TagPrefix:            c.String(tagPrefix),
+				NoColor:              c.Bool(noColor),
+				UseCodeOwners:        c.Bool(useCodeowners),
+				NonRecursive:         c.Bool(nonRecursiveArgs),
 			}
 
 			options.Validate()
@@ -150,6 +166,12 @@ func tagCommand() *cli.Command {
 				Value:       cli.NewStringSlice(),
 				DefaultText: "yor_trace",
 			},
+			&cli.StringSliceFlag{
+				Name:        protectedTagsArg,
+				Usage:       "tags (glob patterns) whose existing values yor never modifies, they are only added to resources which do not have them",
+				Value:       cli.NewStringSlice(),
+				DefaultText: "cost_center,billing_*",
+			},
 			&cli.StringFlag{
 				Name:        outputArg,
 				Aliases:     []string{"o"},
@@ -225,6 +247,41 @@ func tagCommand() *cli.Command {
 				Value:       false,
 				DefaultText: "false",
 			},
+			&cli.BoolFlag{
+				Name:        inspectModulesArgs,
+				Usage:       "Download remote Terraform modules and decide their tags input by the tags of their resources",
+				Value:       false,
+				DefaultText: "false",
+			},
+			&cli.BoolFlag{
+				Name:        cdkOutArgs,
+				Usage:       "Collect the tags of templates synthesized by the AWS CDK (cdk.out) into cdk-tags.json by construct path, instead of modifying the templates",
+				Value:       false,
+				DefaultText: "false",
+			},
+			&cli.BoolFlag{
+				Name:        asgPropagateAtLaunchArgs,
+				Usage:       "The propagate_at_launch value of the tag blocks added to Terraform auto scaling groups",
+				Value:       true,
+				DefaultText: "true",
+			},
+			&cli.BoolFlag{
+				Name:        useDefaultTagsArgs,
+				Usage:       "Write the simple tags to the default_tags of the Terraform AWS provider configurations, instead of to each resource",
+				Value:       false,
+				DefaultText: "false",
+			},
+			&cli.StringFlag{
+				Name:        providerSchemasArgs,
+				Usage:       "Path to snapshots of Terraform provider schemas (terraform providers schema -json), a file or a directory of JSON files, used to resolve taggable resources without downloading providers",
+				DefaultText: "",
+			},
+			&cli.StringFlag{
+				Name:        terragruntTagsKeyArgs,
+				Usage:       "The key of the inputs of terragrunt.hcl files which holds the tags",
+				Value:       "tags",
+				DefaultText: "tags",
+			},
 			&cli.StringFlag{
 				Name:        tagPrefix,
 				Usage:       "Add prefix to all the tags",
@@ -252,6 +309,131 @@ func tagCommand() *cli.Command {
 	}
 }
 
+func migrateCommand() *cli.Command {
+	directoryArg := "directory"
+	renameArg := "rename"
+	removeArg := "remove"
+	skipDirsArg := "skip-dirs"
+	outputArg := "output"
+	outputJSONFileArg := "output-json-file"
+	externalConfPath := "config-file"
+	skipResourceTypesArg := "skip-resource-types"
+	skipResourcesArg := "skip-resources"
+	parsersArgs := "parsers"
+	dryRunArgs := "dry-run"
+	noColor := "no-color"
+	nonRecursiveArgs := "non-recursive"
+	return &cli.Command{
+		Name:                   "migrate",
+		Usage:                  "rename and remove the existing tags across your directory",
+		HideHelpCommand:        true,
+		UseShortOptionHandling: true,
+		Action: func(c *cli.Context) error {
+			options := clioptions.TagOptions{
+				Directory:         c.String(directoryArg),
+				RenameTags:        c.StringSlice(renameArg),
+				RemoveTags:        c.StringSlice(removeArg),
+				SkipDirs:          c.StringSlice(skipDirsArg),
+				Output:            c.String(outputArg),
+				OutputJSONFile:    c.String(outputJSONFileArg),
+				ConfigFile:        c.String(externalConfPath),
+				SkipResourceTypes: c.StringSlice(skipResourceTypesArg),
+				SkipResources:     c.StringSlice(skipResourcesArg),
+				Parsers:           c.StringSlice(parsersArgs),
+				DryRun:            c.Bool(dryRunArgs),
+				NoColor:           c.Bool(noColor),
+				NonRecursive:      c.Bool(nonRecursiveArgs),
+			}
+
+			options.Validate()
+
+			colors := common.NoColorCheck(options.NoColor)
+			return migrate(&options, colors)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        directoryArg,
+				Aliases:     []string{"d"},
+				Usage:       "directory to migrate",
+				Required:    true,
+				DefaultText: "path/to/iac/root",
+			},
+			&cli.StringSliceFlag{
+				Name:        renameArg,
+				Usage:       "tags to rename, as old_key=new_key, their values are kept",
+				Value:       cli.NewStringSlice(),
+				DefaultText: "git_repo=source_repository",
+			},
+			&cli.StringSliceFlag{
+				Name:        removeArg,
+				Usage:       "tags to remove",
+				Value:       cli.NewStringSlice(),
+				DefaultText: "git_modifiers",
+			},
+			&cli.StringFlag{
+				Name:        outputArg,
+				Aliases:     []string{"o"},
+				Usage:       "set output format",
+				Value:       "cli",
+				DefaultText: "json",
+			},
+			&cli.StringFlag{
+				Name:        outputJSONFileArg,
+				Usage:       "json file path for output",
+				DefaultText: "result.json",
+			},
+			&cli.StringSliceFlag{
+				Name:        skipDirsArg,
+				Usage:       "configuration paths to skip",
+				Value:       cli.NewStringSlice(),
+				DefaultText: "path/to/skip,another/path/to/skip",
+			},
+			&cli.StringFlag{
+				Name:        externalConfPath,
+				Usage:       "configuration file path, whose tag_migrations section holds tags to rename and remove",
+				DefaultText: "/path/to/conf/file/ (.yml/.yaml extension)",
+			},
+			&cli.StringSliceFlag{
+				Name:        skipResourceTypesArg,
+				Usage:       "skip resource types for migration",
+				Value:       cli.NewStringSlice(),
+				DefaultText: "aws_rds_instance,AWS::S3::Bucket",
+			},
+			&cli.StringSliceFlag{
+				Name:        skipResourcesArg,
+				Usage:       "skip resources for migration",
+				Value:       cli.NewStringSlice(),
+				DefaultText: "aws_s3_bucket.test-bucket,EC2InstanceResource0",
+			},
+			&cli.StringSliceFlag{
+				Name:        parsersArgs,
+				Aliases:     []string{"i"},
+				Usage:       "IAC types to migrate, tags can be migrated in Terraform, CloudFormation and Serverless files",
+				Value:       cli.NewStringSlice("Terraform", "CloudFormation", "Serverless"),
+				DefaultText: "Terraform,CloudFormation,Serverless",
+			},
+			&cli.BoolFlag{
+				Name:        dryRunArgs,
+				Usage:       "report the migration without modifying the files",
+				Value:       false,
+				DefaultText: "false",
+			},
+			&cli.BoolFlag{
+				Name:        noColor,
+				Usage:       "remove colorized output",
+				Value:       false,
+				DefaultText: "false",
+			},
+			&cli.BoolFlag{
+				Name:        nonRecursiveArgs,
+				Usage:       "non recursive migration",
+				Value:       false,
+				DefaultText: "false",
+			},
+		},
+	}
+}
+
 func listTagGroups() error {
 	for _, tagGroup := range utils.GetAllTagGroupsNames() {
 		fmt.Println(tagGroup)
@@ -293,6 +475,29 @@ func tag(options *clioptions.TagOptions, colors *common.ColorStruct) error {
 	if options.ValidateMode && reportService.Changed() {
 		logger.Error("Changes needed and ValidateMode is true.")
 	}
+	if reportService.HasFailedTagConflicts() {
+		logger.Error("The tag groups proposed different values of tags whose conflict policy is fail.")
+	}
+	return nil
+}
+
+// migrate renames and removes the existing tags of the resources by the tag migrations, without adding the tags of the
+// tag groups
+func migrate(options *clioptions.TagOptions, colors *common.ColorStruct) error {
+	yorRunner := new(runner.Runner)
+	logger.Info(fmt.Sprintf("Setting up to migrate the tags of the directory %v\n", options.Directory))
+	err := yorRunner.Init(options)
+	if err != nil {
+		logger.Error(err.Error())
+	}
+	if structure.TagMigrationRules.IsEmpty() {
+		logger.Error("No tags to migrate, set them with --rename and --remove or in the tag_migrations section of the config file")
+	}
+	reportService, err := yorRunner.TagDirectory()
+	if err != nil {
+		logger.Error(err.Error())
+	}
+	printReport(reportService, options, colors)
 	return nil
 }
 
@@ -0,0 +1,40 @@
+package structure
+
+import (
+	"fmt"
+
+	"github.com/bridgecrewio/yor/src/common/structure"
+)
+
+type AnsibleBlock struct {
+	structure.Block
+	// ParametersKey is the key of the task which holds the parameters of the module, which is the module itself or args
+	ParametersKey string
+	// Provider is the cloud of the module, e.g. aws for amazon.aws.ec2_instance
+	Provider string
+}
+
+func (b *AnsibleBlock) GetResourceID() string {
+	return fmt.Sprintf("%s.%s", b.Type, b.Name)
+}
+
+func (b *AnsibleBlock) GetResourceName() string {
+	return b.Name
+}
+
+func (b *AnsibleBlock) GetTagsLines() structure.Lines {
+	return b.TagLines
+}
+
+func (b *AnsibleBlock) GetSeparator() string {
+	return ":"
+}
+
+// IsGCPBlock checks if the module is a google.cloud module, whose labels only accept a subset of the tag values
+func (b *AnsibleBlock) IsGCPBlock() bool {
+	return b.Provider == GCPProvider
+}
+
+func (b *AnsibleBlock) GetCloudProvider() string {
+	return b.Provider
+}
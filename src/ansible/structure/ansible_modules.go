@@ -0,0 +1,104 @@
+package structure
+
+import (
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common/structure"
+)
+
+const (
+	AWSProvider   = structure.AWSProvider
+	AzureProvider = structure.AzureProvider
+	GCPProvider   = structure.GCPProvider
+)
+
+// collectionToProvider maps the collections of cloud modules to their cloud
+var collectionToProvider = map[string]string{
+	"amazon.aws":         AWSProvider,
+	"community.aws":      AWSProvider,
+	"azure.azcollection": AzureProvider,
+	"google.cloud":       GCPProvider,
+}
+
+// ModuleToTagsParameter maps the fully qualified names of the cloud modules which create taggable resources to their
+// parameter which holds the tags
+var ModuleToTagsParameter = map[string]string{
+	"amazon.aws.cloudformation":                          "tags",
+	"amazon.aws.ec2_ami":                                 "tags",
+	"amazon.aws.ec2_eip":                                 "tags",
+	"amazon.aws.ec2_eni":                                 "tags",
+	"amazon.aws.ec2_instance":                            "tags",
+	"amazon.aws.ec2_key":                                 "tags",
+	"amazon.aws.ec2_security_group":                      "tags",
+	"amazon.aws.ec2_snapshot":                            "tags",
+	"amazon.aws.ec2_vol":                                 "tags",
+	"amazon.aws.ec2_vpc_igw":                             "tags",
+	"amazon.aws.ec2_vpc_nat_gateway":                     "tags",
+	"amazon.aws.ec2_vpc_net":                             "tags",
+	"amazon.aws.ec2_vpc_route_table":                     "tags",
+	"amazon.aws.ec2_vpc_subnet":                          "tags",
+	"amazon.aws.elb_application_lb":                      "tags",
+	"amazon.aws.iam_role":                                "tags",
+	"amazon.aws.kms_key":                                 "tags",
+	"amazon.aws.lambda":                                  "tags",
+	"amazon.aws.rds_cluster":                             "tags",
+	"amazon.aws.rds_instance":                            "tags",
+	"amazon.aws.s3_bucket":                               "tags",
+	"community.aws.dynamodb_table":                       "tags",
+	"community.aws.efs":                                  "tags",
+	"community.aws.eks_cluster":                          "tags",
+	"community.aws.elb_network_lb":                       "tags",
+	"community.aws.sns_topic":                            "tags",
+	"community.aws.sqs_queue":                            "tags",
+	"azure.azcollection.azure_rm_aks":                    "tags",
+	"azure.azcollection.azure_rm_appserviceplan":         "tags",
+	"azure.azcollection.azure_rm_containerregistry":      "tags",
+	"azure.azcollection.azure_rm_cosmosdbaccount":        "tags",
+	"azure.azcollection.azure_rm_keyvault":               "tags",
+	"azure.azcollection.azure_rm_loadbalancer":           "tags",
+	"azure.azcollection.azure_rm_manageddisk":            "tags",
+	"azure.azcollection.azure_rm_networkinterface":       "tags",
+	"azure.azcollection.azure_rm_publicipaddress":        "tags",
+	"azure.azcollection.azure_rm_resourcegroup":          "tags",
+	"azure.azcollection.azure_rm_securitygroup":          "tags",
+	"azure.azcollection.azure_rm_sqlserver":              "tags",
+	"azure.azcollection.azure_rm_storageaccount":         "tags",
+	"azure.azcollection.azure_rm_virtualmachine":         "tags",
+	"azure.azcollection.azure_rm_virtualmachinescaleset": "tags",
+	"azure.azcollection.azure_rm_virtualnetwork":         "tags",
+	"azure.azcollection.azure_rm_webapp":                 "tags",
+	"google.cloud.gcp_bigquery_dataset":                  "labels",
+	"google.cloud.gcp_compute_disk":                      "labels",
+	"google.cloud.gcp_compute_image":                     "labels",
+	"google.cloud.gcp_compute_instance":                  "labels",
+	"google.cloud.gcp_compute_snapshot":                  "labels",
+	"google.cloud.gcp_container_cluster":                 "resource_labels",
+	"google.cloud.gcp_filestore_instance":                "labels",
+	"google.cloud.gcp_pubsub_subscription":               "labels",
+	"google.cloud.gcp_pubsub_topic":                      "labels",
+	"google.cloud.gcp_redis_instance":                    "labels",
+	"google.cloud.gcp_spanner_instance":                  "labels",
+	"google.cloud.gcp_storage_bucket":                    "labels",
+}
+
+// shortNameToModule maps the short names of the cloud modules, which older playbooks use, to their fully qualified
+// names, e.g. ec2_instance to amazon.aws.ec2_instance
+var shortNameToModule = func() map[string]string {
+	shortNames := make(map[string]string, len(ModuleToTagsParameter))
+	for module := range ModuleToTagsParameter {
+		shortNames[module[strings.LastIndex(module, ".")+1:]] = module
+	}
+	return shortNames
+}()
+
+// GetCloudModule returns the fully qualified name of the cloud module the task key refers to, and the cloud of the
+// module. It returns an empty name for keys which are not cloud modules.
+func GetCloudModule(key string) (string, string) {
+	module := key
+	if _, ok := ModuleToTagsParameter[module]; !ok {
+		if module, ok = shortNameToModule[key]; !ok {
+			return "", ""
+		}
+	}
+	return module, collectionToProvider[module[:strings.LastIndex(module, ".")]]
+}
@@ -0,0 +1,293 @@
+package structure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common"
+	"github.com/bridgecrewio/yor/src/common/logger"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/bridgecrewio/yor/src/common/utils"
+	yamlUtils "github.com/bridgecrewio/yor/src/common/yaml"
+	"gopkg.in/yaml.v2"
+)
+
+const NameAttributeName = "name"
+const ArgsAttributeName = "args"
+
+// the keys of a play which hold its task lists
+var playTasksKeys = []string{"pre_tasks", "tasks", "post_tasks", "handlers"}
+
+// the keys of a block task which hold its nested task lists
+var blockTasksKeys = []string{"block", "rescue", "always"}
+
+// the keys which only plays have
+var playKeys = []string{"hosts", "import_playbook", "ansible.builtin.import_playbook"}
+
+type AnsibleParser struct {
+	skippedByCommentList []string
+}
+
+func (p *AnsibleParser) Name() string {
+	return "Ansible"
+}
+
+func (p *AnsibleParser) Init(_ string, _ map[string]string) {}
+
+func (p *AnsibleParser) Close() {}
+
+func (p *AnsibleParser) GetSkippedDirs() []string {
+	return []string{}
+}
+
+func (p *AnsibleParser) GetSupportedFileExtensions() []string {
+	return []string{common.YamlFileType.Extension, common.YmlFileType.Extension}
+}
+
+func (p *AnsibleParser) GetSkipResourcesByComment() []string {
+	return p.skippedByCommentList
+}
+
+// ValidFile checks the file is a playbook or a task list, which is a list of plays or tasks
+func (p *AnsibleParser) ValidFile(filePath string) bool {
+	items, err := readItems(filePath)
+	if err != nil || len(items) == 0 {
+		return false
+	}
+	for _, item := range items {
+		itemMap, ok := item.(map[interface{}]interface{})
+		if !ok || !isPlay(itemMap) && !isTask(itemMap) {
+			return false
+		}
+	}
+	return true
+}
+
+func readItems(filePath string) ([]interface{}, error) {
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var items []interface{}
+	if err = yaml.Unmarshal(src, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func isPlay(item map[interface{}]interface{}) bool {
+	for _, key := range playKeys {
+		if _, ok := item[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isTask(item map[interface{}]interface{}) bool {
+	if _, ok := item[NameAttributeName]; ok {
+		return true
+	}
+	for _, key := range blockTasksKeys {
+		if _, ok := item[key]; ok {
+			return true
+		}
+	}
+	module, _ := getTaskModule(item)
+	return module != ""
+}
+
+// getTaskModule returns the cloud module of the task and the key the task refers to it by
+func getTaskModule(task map[interface{}]interface{}) (string, string) {
+	for key := range task {
+		keyStr, ok := key.(string)
+		if !ok {
+			continue
+		}
+		if module, _ := GetCloudModule(keyStr); module != "" {
+			return module, keyStr
+		}
+	}
+	return "", ""
+}
+
+// ParseFile returns a block for every task of the file which calls a cloud module, including the tasks nested in
+// blocks. The name of the task is the name of the resource.
+func (p *AnsibleParser) ParseFile(filePath string) ([]structure.IBlock, error) {
+	items, err := readItems(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file %s because %s", filePath, err)
+	}
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s because %s", filePath, err)
+	}
+	fileLines := utils.GetLinesFromBytes(src)
+	itemsLines := yamlUtils.FindSequenceItemsLinesYAML(fileLines, structure.Lines{Start: 1, End: len(fileLines)})
+	if len(itemsLines) != len(items) {
+		return nil, fmt.Errorf("failed to find the lines of the plays and tasks of %s", filePath)
+	}
+	parsedBlocks := make([]structure.IBlock, 0)
+	for i, item := range items {
+		itemMap, ok := item.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		if !isPlay(itemMap) {
+			parsedBlocks = append(parsedBlocks, p.parseTask(filePath, fileLines, itemMap, itemsLines[i])...)
+			continue
+		}
+		for _, key := range playTasksKeys {
+			parsedBlocks = append(parsedBlocks, p.parseTasksList(filePath, fileLines, itemMap, key, itemsLines[i])...)
+		}
+	}
+	return parsedBlocks, nil
+}
+
+// parseTasksList returns the blocks of the tasks in the list of the given key of a play or a block task
+func (p *AnsibleParser) parseTasksList(filePath string, fileLines []string, parent map[interface{}]interface{}, key string, parentLines structure.Lines) []structure.IBlock {
+	tasks, ok := parent[key].([]interface{})
+	if !ok || len(tasks) == 0 {
+		return nil
+	}
+	keyLines, ok := yamlUtils.FindMapPathLinesYAML(fileLines, parentLines, []string{key})
+	if !ok {
+		return nil
+	}
+	tasksLines := yamlUtils.FindSequenceItemsLinesYAML(fileLines, structure.Lines{Start: keyLines.Start + 1, End: keyLines.End})
+	if len(tasksLines) != len(tasks) {
+		logger.Warning(fmt.Sprintf("Failed to find the lines of the tasks of %s in %s, skipping them", key, filePath))
+		return nil
+	}
+	parsedBlocks := make([]structure.IBlock, 0)
+	for i, task := range tasks {
+		if taskMap, ok := task.(map[interface{}]interface{}); ok {
+			parsedBlocks = append(parsedBlocks, p.parseTask(filePath, fileLines, taskMap, tasksLines[i])...)
+		}
+	}
+	return parsedBlocks
+}
+
+func (p *AnsibleParser) parseTask(filePath string, fileLines []string, task map[interface{}]interface{}, taskLines structure.Lines) []structure.IBlock {
+	parsedBlocks := make([]structure.IBlock, 0)
+	for _, key := range blockTasksKeys {
+		parsedBlocks = append(parsedBlocks, p.parseTasksList(filePath, fileLines, task, key, taskLines)...)
+	}
+	module, moduleKey := getTaskModule(task)
+	if module == "" {
+		return parsedBlocks
+	}
+	_, provider := GetCloudModule(module)
+	tagsAttributeName := ModuleToTagsParameter[module]
+	parametersKey := moduleKey
+	if task[moduleKey] == nil && task[ArgsAttributeName] != nil {
+		parametersKey = ArgsAttributeName
+	}
+	name := fmt.Sprintf("%v", task[NameAttributeName])
+	if task[NameAttributeName] == nil {
+		name = moduleKey
+	}
+
+	// the tags can only be edited if they are written as a mapping, and not e.g. as a template of a variable
+	isTaggable := true
+	var existingTags []tags.ITag
+	switch parameters := task[parametersKey].(type) {
+	case nil:
+	case map[interface{}]interface{}:
+		if tagsValue, ok := parameters[tagsAttributeName]; ok {
+			existingTagsMap, isMap := yamlUtils.ReadMapPathYAML(parameters, []string{tagsAttributeName})
+			if !isMap {
+				logger.Info(fmt.Sprintf("Not tagging task %v of %v, its %v are not a mapping: %v", name, filePath, tagsAttributeName, tagsValue))
+				isTaggable = false
+			}
+			for _, key := range sortedKeys(existingTagsMap) {
+				existingTags = append(existingTags, &tags.Tag{Key: key, Value: existingTagsMap[key]})
+			}
+		}
+	default:
+		logger.Info(fmt.Sprintf("Not tagging task %v of %v, its parameters are not a mapping", name, filePath))
+		isTaggable = false
+	}
+	tagsLines, _ := yamlUtils.FindMapPathLinesYAML(fileLines, taskLines, []string{parametersKey, tagsAttributeName})
+
+	ansibleBlock := &AnsibleBlock{
+		Block: structure.Block{
+			FilePath:          filePath,
+			ExitingTags:       existingTags,
+			RawBlock:          task,
+			IsTaggable:        isTaggable,
+			TagsAttributeName: tagsAttributeName,
+			Lines:             taskLines,
+			TagLines:          tagsLines,
+			Name:              name,
+			Type:              module,
+		},
+		ParametersKey: parametersKey,
+		Provider:      provider,
+	}
+	// skip comments are placed on the line before the task
+	if taskLines.Start > 1 && strings.ToUpper(strings.ReplaceAll(fileLines[taskLines.Start-2], " ", "")) == "#YOR:SKIP" {
+		p.skippedByCommentList = append(p.skippedByCommentList, ansibleBlock.GetResourceID())
+	}
+	return append(parsedBlocks, ansibleBlock)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (p *AnsibleParser) WriteFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(readFilePath), "temp.*.yaml")
+	defer func() {
+		_ = os.Remove(tempFile.Name())
+	}()
+	if err != nil {
+		return err
+	}
+	err = p.writeToFile(readFilePath, blocks, tempFile.Name())
+	if err != nil {
+		return err
+	}
+	tempBlocks, err := p.ParseFile(tempFile.Name())
+	if err != nil || len(tempBlocks) != len(blocks) {
+		return fmt.Errorf("editing file %v resulted in a malformed playbook, please open a github issue with the relevant details", readFilePath)
+	}
+	return p.writeToFile(readFilePath, blocks, writeFilePath)
+}
+
+func (p *AnsibleParser) writeToFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	// #nosec G304
+	src, err := os.ReadFile(readFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s because %s", readFilePath, err)
+	}
+	fileLines := utils.GetLinesFromBytes(src)
+	edits := make([]yamlUtils.MapTagsEdit, 0)
+	for _, block := range blocks {
+		ansibleBlock, ok := block.(*AnsibleBlock)
+		if !ok || !ansibleBlock.IsBlockTaggable() {
+			continue
+		}
+		diff := ansibleBlock.CalculateTagsDiff()
+		edits = append(edits, yamlUtils.MapTagsEdit{
+			Scope:   ansibleBlock.GetLines(),
+			Path:    []string{ansibleBlock.ParametersKey, ansibleBlock.GetTagsAttributeName()},
+			Added:   diff.Added,
+			Updated: diff.Updated,
+		})
+	}
+	fileLines = yamlUtils.ApplyMapTagsEdits(fileLines, edits)
+
+	return os.WriteFile(writeFilePath, []byte(strings.Join(fileLines, "\n")), 0600)
+}
@@ -0,0 +1,103 @@
+package structure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+const resourcesDir = "../../../tests/ansible/resources"
+
+func TestAnsibleParser_ValidFile(t *testing.T) {
+	p := AnsibleParser{}
+	p.Init(resourcesDir, nil)
+	assert.True(t, p.ValidFile(filepath.Join(resourcesDir, "playbook.yml")))
+	assert.True(t, p.ValidFile(filepath.Join(resourcesDir, "roles", "web", "tasks", "main.yml")))
+	assert.False(t, p.ValidFile("../../../tests/kubernetes/resources/deployment.yaml"))
+}
+
+func TestGetCloudModule(t *testing.T) {
+	module, provider := GetCloudModule("ec2_instance")
+	assert.Equal(t, "amazon.aws.ec2_instance", module)
+	assert.Equal(t, AWSProvider, provider)
+	module, provider = GetCloudModule("google.cloud.gcp_container_cluster")
+	assert.Equal(t, "google.cloud.gcp_container_cluster", module)
+	assert.Equal(t, GCPProvider, provider)
+	module, _ = GetCloudModule("ansible.builtin.debug")
+	assert.Equal(t, "", module)
+}
+
+func TestAnsibleParser_ParseFile(t *testing.T) {
+	p := AnsibleParser{}
+	p.Init(resourcesDir, nil)
+	blocks, err := p.ParseFile(filepath.Join(resourcesDir, "playbook.yml"))
+	if err != nil {
+		t.Errorf("ParseFile() error = %v", err)
+		return
+	}
+	expectedBlocks := []struct {
+		id         string
+		lines      structure.Lines
+		tagsLines  structure.Lines
+		taggable   bool
+		tagsLength int
+	}{
+		{id: "amazon.aws.ec2_instance.Launch web server", lines: structure.Lines{Start: 15, End: 22}, tagsLines: structure.Lines{Start: 20, End: 22}, taggable: true, tagsLength: 2},
+		{id: "amazon.aws.s3_bucket.Create assets bucket", lines: structure.Lines{Start: 26, End: 29}, tagsLines: structure.Lines{Start: -1, End: -1}, taggable: true},
+		{id: "amazon.aws.s3_bucket.Create logs bucket", lines: structure.Lines{Start: 31, End: 34}, tagsLines: structure.Lines{Start: 34, End: 34}, taggable: true},
+		{id: "azure.azcollection.azure_rm_resourcegroup.Create resource group", lines: structure.Lines{Start: 40, End: 44}, tagsLines: structure.Lines{Start: 44, End: 44}, taggable: true, tagsLength: 1},
+		{id: "google.cloud.gcp_compute_disk.Create data disk", lines: structure.Lines{Start: 46, End: 52}, tagsLines: structure.Lines{Start: 51, End: 52}, taggable: true, tagsLength: 1},
+		{id: "amazon.aws.ec2_security_group.Create security group", lines: structure.Lines{Start: 54, End: 58}, tagsLines: structure.Lines{Start: 58, End: 58}, taggable: false},
+	}
+	assert.Len(t, blocks, len(expectedBlocks))
+	for i, expected := range expectedBlocks {
+		block := blocks[i].(*AnsibleBlock)
+		assert.Equal(t, expected.id, block.GetResourceID())
+		assert.Equal(t, expected.lines, block.GetLines(), expected.id)
+		assert.Equal(t, expected.tagsLines, block.GetTagsLines(), expected.id)
+		assert.Equal(t, expected.taggable, block.IsBlockTaggable(), expected.id)
+		assert.Len(t, block.GetExistingTags(), expected.tagsLength, expected.id)
+	}
+	assert.Equal(t, "Launch web server", blocks[0].GetResourceName())
+	assert.True(t, blocks[4].IsGCPBlock())
+	assert.Equal(t, []string{"amazon.aws.s3_bucket.Create logs bucket"}, p.GetSkipResourcesByComment())
+}
+
+func TestAnsibleParser_WriteFile(t *testing.T) {
+	newTags := func() []tags.ITag {
+		return []tags.ITag{
+			&tags.Tag{Key: "yor_trace", Value: "7f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0"},
+			&tags.Tag{Key: "git_repo", Value: "infra"},
+			&tags.Tag{Key: "env", Value: "staging"},
+		}
+	}
+	for _, file := range []string{"playbook", filepath.Join("roles", "web", "tasks", "main")} {
+		t.Run(file, func(t *testing.T) {
+			p := AnsibleParser{}
+			p.Init(resourcesDir, nil)
+			filePath := filepath.Join(resourcesDir, file+".yml")
+			blocks, err := p.ParseFile(filePath)
+			if err != nil {
+				t.Errorf("ParseFile() error = %v", err)
+				return
+			}
+			for _, block := range blocks {
+				block.AddNewTags(newTags())
+			}
+			f, _ := os.CreateTemp(resourcesDir, "playbook.*.yml")
+			defer func() { _ = os.Remove(f.Name()) }()
+			if err = p.WriteFile(filePath, blocks, f.Name()); err != nil {
+				t.Errorf("WriteFile() error = %v", err)
+				return
+			}
+
+			expected, _ := os.ReadFile(filepath.Join(resourcesDir, file+".expected.yml"))
+			actual, _ := os.ReadFile(f.Name())
+			assert.Equal(t, string(expected), string(actual))
+		})
+	}
+}
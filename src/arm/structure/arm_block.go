@@ -0,0 +1,28 @@
+package structure
+
+import (
+	"github.com/bridgecrewio/yor/src/common/structure"
+)
+
+type ArmBlock struct {
+	structure.Block
+	// Path is the location of the resource in the template, e.g. resources[1].properties.template.resources[0]
+	Path       string
+	APIVersion string
+}
+
+func (b *ArmBlock) GetResourceID() string {
+	return b.Name
+}
+
+func (b *ArmBlock) GetResourceName() string {
+	return b.Name
+}
+
+func (b *ArmBlock) GetTagsLines() structure.Lines {
+	return b.TagLines
+}
+
+func (b *ArmBlock) GetSeparator() string {
+	return ":"
+}
@@ -0,0 +1,346 @@
+package structure
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common"
+	"github.com/bridgecrewio/yor/src/common/json"
+	"github.com/bridgecrewio/yor/src/common/logger"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/bridgecrewio/yor/src/common/utils"
+)
+
+const TagsAttributeName = "tags"
+const ResourcesAttributeName = "resources"
+const DeploymentResourceType = "Microsoft.Resources/deployments"
+
+// all the ARM deployment template schemas (resource group, subscription, management group and tenant) end with this
+const deploymentTemplateSchemaSuffix = "deploymenttemplate.json#"
+
+// the key of the scope which starts right after it. Scopes which start inside a string are ARM expressions.
+var scopeKeyRegex = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"\s*:\s*("?)$`)
+
+type ArmParser struct {
+	rootDir              string
+	skippedByCommentList []string
+}
+
+// armResource is a resource found in a template, with the brackets of its scopes in the text of the file
+type armResource struct {
+	path           string
+	name           string
+	resourceType   string
+	apiVersion     string
+	scope          json.BracketPair
+	tagsScope      *json.BracketPair
+	tagsExpression bool
+	existingTags   []tags.ITag
+}
+
+type childScope struct {
+	key      string
+	inString bool
+	scope    json.BracketPair
+}
+
+func (p *ArmParser) Name() string {
+	return "ARM"
+}
+
+func (p *ArmParser) Init(rootDir string, _ map[string]string) {
+	p.rootDir = rootDir
+}
+
+func (p *ArmParser) Close() {}
+
+func (p *ArmParser) GetSkippedDirs() []string {
+	return []string{}
+}
+
+func (p *ArmParser) GetSupportedFileExtensions() []string {
+	return []string{common.JSONFileType.Extension}
+}
+
+func (p *ArmParser) GetSkipResourcesByComment() []string {
+	return p.skippedByCommentList
+}
+
+// ValidFile checks the file is an ARM deployment template by its $schema
+func (p *ArmParser) ValidFile(filePath string) bool {
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Error reading file %s, skipping: %v", filePath, err))
+		return false
+	}
+	var template struct {
+		Schema string `json:"$schema"`
+	}
+	if err = stdjson.Unmarshal(src, &template); err != nil {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(template.Schema), deploymentTemplateSchemaSuffix)
+}
+
+func (p *ArmParser) ParseFile(filePath string) ([]structure.IBlock, error) {
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s because %s", filePath, err)
+	}
+	if !stdjson.Valid(src) {
+		return nil, fmt.Errorf("failed to parse file %s, it is not a valid json", filePath)
+	}
+	parsedBlocks := make([]structure.IBlock, 0)
+	for _, resource := range scanARMResources(string(src)) {
+		tagsLines := structure.Lines{Start: -1, End: -1}
+		if resource.tagsScope != nil {
+			tagsLines = structure.Lines{Start: resource.tagsScope.Open.Line, End: resource.tagsScope.Close.Line}
+		}
+		armBlock := &ArmBlock{
+			Block: structure.Block{
+				FilePath:          filePath,
+				ExitingTags:       resource.existingTags,
+				RawBlock:          resource,
+				IsTaggable:        resource.tagsScope != nil || utils.IsTaggableAzureResourceType(resource.resourceType),
+				TagsAttributeName: TagsAttributeName,
+				Lines:             structure.Lines{Start: resource.scope.Open.Line, End: resource.scope.Close.Line},
+				TagLines:          tagsLines,
+				Name:              resource.name,
+				Type:              resource.resourceType,
+			},
+			Path:       resource.path,
+			APIVersion: resource.apiVersion,
+		}
+		parsedBlocks = append(parsedBlocks, armBlock)
+	}
+
+	return parsedBlocks, nil
+}
+
+// scanARMResources finds the resources of the template, their child resources and the resources of templates of
+// nested deployments
+func scanARMResources(src string) []*armResource {
+	pairsByOpenIndex := json.GetBracketsPairs(json.MapBracketsInString(src))
+	sortedPairs := make([]json.BracketPair, 0, len(pairsByOpenIndex))
+	for _, pair := range pairsByOpenIndex {
+		sortedPairs = append(sortedPairs, pair)
+	}
+	sort.Slice(sortedPairs, func(i, j int) bool {
+		return sortedPairs[i].Open.CharIndex < sortedPairs[j].Open.CharIndex
+	})
+	for _, pair := range sortedPairs {
+		if pair.Open.Shape == json.CurlyBrackets {
+			return scanResourcesOf(src, sortedPairs, pair, "", "")
+		}
+	}
+	return nil
+}
+
+// scanResourcesOf returns the resources in the `resources` property of a template or a resource. The resources are an
+// array, or an object keyed by symbolic names in templates with languageVersion 2.0
+func scanResourcesOf(src string, sortedPairs []json.BracketPair, parent json.BracketPair, pathPrefix string, parentType string) []*armResource {
+	var resources []*armResource
+	for _, child := range getChildScopes(src, sortedPairs, parent) {
+		if child.key != ResourcesAttributeName || child.inString {
+			continue
+		}
+		for i, element := range getChildScopes(src, sortedPairs, child.scope) {
+			if element.scope.Open.Shape != json.CurlyBrackets || element.inString {
+				continue
+			}
+			path := fmt.Sprintf("%s%s[%d]", pathPrefix, ResourcesAttributeName, i)
+			if child.scope.Open.Shape == json.CurlyBrackets {
+				path = fmt.Sprintf("%s%s.%s", pathPrefix, ResourcesAttributeName, element.key)
+			}
+			resources = append(resources, scanResource(src, sortedPairs, element, path, parentType)...)
+		}
+	}
+	return resources
+}
+
+func scanResource(src string, sortedPairs []json.BracketPair, element childScope, path string, parentType string) []*armResource {
+	var rawResource struct {
+		Type       string      `json:"type"`
+		Name       string      `json:"name"`
+		APIVersion string      `json:"apiVersion"`
+		Tags       interface{} `json:"tags"`
+	}
+	if err := stdjson.Unmarshal([]byte(src[element.scope.Open.CharIndex:element.scope.Close.CharIndex+1]), &rawResource); err != nil || rawResource.Type == "" {
+		return nil
+	}
+	resource := &armResource{
+		path:         path,
+		name:         rawResource.Name,
+		resourceType: rawResource.Type,
+		apiVersion:   rawResource.APIVersion,
+		scope:        element.scope,
+	}
+	if element.key != "" {
+		// templates with languageVersion 2.0 declare resources by their symbolic names
+		resource.name = element.key
+	}
+	// child resources may declare their type relative to the type of their parent, e.g. `blobServices`
+	if parentType != "" && !strings.Contains(strings.Split(resource.resourceType, "/")[0], ".") {
+		resource.resourceType = parentType + "/" + resource.resourceType
+	}
+	switch tagsValue := rawResource.Tags.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(tagsValue))
+		for key := range tagsValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, ok := tagsValue[key].(string)
+			if !ok {
+				valueBytes, _ := stdjson.Marshal(tagsValue[key])
+				value = string(valueBytes)
+			}
+			resource.existingTags = append(resource.existingTags, &tags.Tag{Key: key, Value: value})
+		}
+	case string:
+		resource.tagsExpression = true
+	}
+
+	resources := []*armResource{resource}
+	for _, child := range getChildScopes(src, sortedPairs, element.scope) {
+		switch {
+		case child.key == TagsAttributeName && (child.inString == resource.tagsExpression):
+			tagsScope := child.scope
+			resource.tagsScope = &tagsScope
+		case child.key == ResourcesAttributeName && !child.inString:
+			resources = append(resources, scanResourcesOf(src, sortedPairs, element.scope, path+".", resource.resourceType)...)
+		case child.key == "properties" && !child.inString && resource.resourceType == DeploymentResourceType:
+			for _, property := range getChildScopes(src, sortedPairs, child.scope) {
+				if property.key == "template" && !property.inString {
+					resources = append(resources, scanResourcesOf(src, sortedPairs, property.scope, path+".properties.template.", "")...)
+				}
+			}
+		}
+	}
+	return resources
+}
+
+// getChildScopes returns the brackets which are directly in the given scope, along with their keys
+func getChildScopes(src string, sortedPairs []json.BracketPair, parent json.BracketPair) []childScope {
+	var children []childScope
+	previousEnd := parent.Open.CharIndex + 1
+	for _, pair := range sortedPairs {
+		if pair.Open.CharIndex < previousEnd {
+			continue
+		}
+		if pair.Open.CharIndex >= parent.Close.CharIndex {
+			break
+		}
+		child := childScope{scope: pair}
+		if match := scopeKeyRegex.FindStringSubmatch(src[previousEnd:pair.Open.CharIndex]); match != nil {
+			child.key = match[1]
+			child.inString = match[2] != ""
+		} else {
+			child.inString = strings.HasSuffix(strings.TrimSpace(src[previousEnd:pair.Open.CharIndex]), `"`)
+		}
+		children = append(children, child)
+		previousEnd = pair.Close.CharIndex + 1
+	}
+	return children
+}
+
+func (p *ArmParser) WriteFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(readFilePath), "temp.*.json")
+	defer func() {
+		_ = os.Remove(tempFile.Name())
+	}()
+	if err != nil {
+		return err
+	}
+	err = p.writeToFile(readFilePath, blocks, tempFile.Name())
+	if err != nil {
+		return err
+	}
+	tempBlocks, err := p.ParseFile(tempFile.Name())
+	if err != nil || len(tempBlocks) != len(blocks) {
+		return fmt.Errorf("editing file %v resulted in a malformed template, please open a github issue with the relevant details", readFilePath)
+	}
+	return p.writeToFile(readFilePath, blocks, writeFilePath)
+}
+
+func (p *ArmParser) writeToFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	// #nosec G304
+	src, err := os.ReadFile(readFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s because %s", readFilePath, err)
+	}
+	srcStr := string(src)
+	resourcesByPath := make(map[string]*armResource)
+	for _, resource := range scanARMResources(srcStr) {
+		resourcesByPath[resource.path] = resource
+	}
+
+	type edit struct {
+		start int
+		end   int
+		text  string
+	}
+	var edits []edit
+	for _, block := range blocks {
+		armBlock := block.(*ArmBlock)
+		if !armBlock.IsBlockTaggable() {
+			continue
+		}
+		diff := armBlock.CalculateTagsDiff()
+		if len(diff.Added) == 0 && len(diff.Updated) == 0 {
+			continue
+		}
+		resource, ok := resourcesByPath[armBlock.Path]
+		if !ok {
+			logger.Warning(fmt.Sprintf("failed to find resource %v in %v", armBlock.GetResourceID(), readFilePath))
+			continue
+		}
+		switch {
+		case resource.tagsScope != nil && resource.tagsExpression:
+			// the tags are an expression such as [parameters('tags')], merge it with the new tags
+			start := resource.tagsScope.Open.CharIndex
+			end := resource.tagsScope.Close.CharIndex + 1
+			expression := fmt.Sprintf("[union(%s, %s)]", srcStr[start+1:end-1], getCreateObjectExpression(diff.Added))
+			edits = append(edits, edit{start: start - 1, end: end + 1, text: json.MarshalJSONString(expression)})
+		case resource.tagsScope != nil:
+			start := resource.tagsScope.Open.CharIndex
+			end := resource.tagsScope.Close.CharIndex + 1
+			edits = append(edits, edit{start: start, end: end, text: json.AddTagsToMapStr(srcStr[start:end], diff)})
+		default:
+			insertAt, text := json.GetMapPropertyAddition(srcStr, resource.scope, TagsAttributeName, diff.Added)
+			edits = append(edits, edit{start: insertAt, end: insertAt, text: text})
+		}
+	}
+
+	// apply the edits from the end of the file, so the offsets of the next edits are not affected
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start > edits[j].start
+	})
+	for _, e := range edits {
+		srcStr = srcStr[:e.start] + e.text + srcStr[e.end:]
+	}
+
+	return os.WriteFile(writeFilePath, []byte(srcStr), 0600)
+}
+
+// getCreateObjectExpression returns an ARM createObject() call which creates the given tags
+func getCreateObjectExpression(added []tags.ITag) string {
+	args := make([]string, 0, len(added)*2)
+	for _, tag := range added {
+		args = append(args, quoteARMString(tag.GetKey()), quoteARMString(tag.GetValue()))
+	}
+	return fmt.Sprintf("createObject(%s)", strings.Join(args, ", "))
+}
+
+func quoteARMString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,95 @@
+package structure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArmParser_ValidFile(t *testing.T) {
+	directory := "../../../tests/arm/resources"
+	p := ArmParser{}
+	p.Init(directory, nil)
+	assert.True(t, p.ValidFile(filepath.Join(directory, "azuredeploy.json")))
+	assert.False(t, p.ValidFile(filepath.Join(directory, "azuredeploy.parameters.json")))
+}
+
+func TestArmParser_ParseFile(t *testing.T) {
+	directory := "../../../tests/arm/resources"
+	p := ArmParser{}
+	p.Init(directory, nil)
+	blocks, err := p.ParseFile(filepath.Join(directory, "azuredeploy.json"))
+	if err != nil {
+		t.Errorf("ParseFile() error = %v", err)
+		return
+	}
+	var paths []string
+	blocksByName := map[string]*ArmBlock{}
+	for _, block := range blocks {
+		armBlock := block.(*ArmBlock)
+		paths = append(paths, armBlock.Path)
+		blocksByName[armBlock.GetResourceID()] = armBlock
+	}
+	assert.Equal(t, []string{
+		"resources[0]",
+		"resources[0].resources[0]",
+		"resources[1]",
+		"resources[2]",
+		"resources[3]",
+		"resources[3].properties.template.resources[0]",
+	}, paths)
+
+	storage := blocksByName["yorstorage"]
+	assert.Equal(t, "Microsoft.Storage/storageAccounts", storage.GetResourceType())
+	assert.Equal(t, structure.Lines{Start: 15, End: 38}, storage.GetLines())
+	assert.Equal(t, structure.Lines{Start: 24, End: 27}, storage.GetTagsLines())
+	assert.Equal(t, []tags.ITag{
+		&tags.Tag{Key: "env", Value: "dev"},
+		&tags.Tag{Key: "team", Value: "platform"},
+	}, storage.GetExistingTags())
+
+	blobServices := blocksByName["default"]
+	assert.Equal(t, "Microsoft.Storage/storageAccounts/blobServices", blobServices.GetResourceType())
+	assert.False(t, blobServices.IsBlockTaggable())
+
+	assert.True(t, blocksByName["yor-vnet"].IsBlockTaggable())
+	assert.Equal(t, structure.Lines{Start: -1, End: -1}, blocksByName["yor-vnet"].GetTagsLines())
+	assert.True(t, blocksByName["yor-ip"].IsBlockTaggable())
+}
+
+func TestArmParser_WriteFile(t *testing.T) {
+	directory := "../../../tests/arm/resources"
+	p := ArmParser{}
+	p.Init(directory, nil)
+	filePath := filepath.Join(directory, "azuredeploy.json")
+	blocks, err := p.ParseFile(filePath)
+	if err != nil {
+		t.Errorf("ParseFile() error = %v", err)
+		return
+	}
+	for _, block := range blocks {
+		if !block.IsBlockTaggable() {
+			continue
+		}
+		block.AddNewTags([]tags.ITag{
+			&tags.Tag{Key: "env", Value: "prod"},
+			&tags.Tag{Key: "yor_trace", Value: "0c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"},
+			&tags.Tag{Key: "git_file", Value: "azuredeploy.json"},
+		})
+	}
+	f, _ := os.CreateTemp(directory, "azuredeploy.*.json")
+	defer func() { _ = os.Remove(f.Name()) }()
+	err = p.WriteFile(filePath, blocks, f.Name())
+	if err != nil {
+		t.Errorf("WriteFile() error = %v", err)
+		return
+	}
+
+	expected, _ := os.ReadFile(filepath.Join(directory, "azuredeploy_expected.json"))
+	actual, _ := os.ReadFile(f.Name())
+	assert.Equal(t, string(expected), string(actual))
+}
@@ -0,0 +1,26 @@
+package structure
+
+import (
+	"github.com/bridgecrewio/yor/src/common/structure"
+)
+
+type BicepBlock struct {
+	structure.Block
+	APIVersion string
+}
+
+func (b *BicepBlock) GetResourceID() string {
+	return b.Name
+}
+
+func (b *BicepBlock) GetResourceName() string {
+	return b.Name
+}
+
+func (b *BicepBlock) GetTagsLines() structure.Lines {
+	return b.TagLines
+}
+
+func (b *BicepBlock) GetSeparator() string {
+	return ":"
+}
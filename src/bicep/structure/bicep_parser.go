@@ -0,0 +1,327 @@
+package structure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common"
+	"github.com/bridgecrewio/yor/src/common/logger"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/bridgecrewio/yor/src/common/utils"
+)
+
+const TagsAttributeName = "tags"
+const defaultIndent = "  "
+
+type BicepParser struct {
+	rootDir              string
+	skippedByCommentList []string
+}
+
+// bicepEdit replaces the text between start and end
+type bicepEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+func (p *BicepParser) Name() string {
+	return "Bicep"
+}
+
+func (p *BicepParser) Init(rootDir string, _ map[string]string) {
+	p.rootDir = rootDir
+}
+
+func (p *BicepParser) Close() {}
+
+func (p *BicepParser) GetSkippedDirs() []string {
+	return []string{}
+}
+
+func (p *BicepParser) GetSupportedFileExtensions() []string {
+	return []string{common.BicepFileType.Extension}
+}
+
+func (p *BicepParser) GetSkipResourcesByComment() []string {
+	return p.skippedByCommentList
+}
+
+func (p *BicepParser) ValidFile(_ string) bool {
+	return true
+}
+
+func (p *BicepParser) ParseFile(filePath string) ([]structure.IBlock, error) {
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s because %s", filePath, err)
+	}
+	srcStr := string(src)
+	parsedBlocks := make([]structure.IBlock, 0)
+	skipAll := false
+	for _, resource := range scanBicepResources(srcStr) {
+		var existingTags []tags.ITag
+		tagsLines := structure.Lines{Start: -1, End: -1}
+		if resource.tags != nil {
+			existingTags = getExistingTags(resource.tags)
+			tagsLines = structure.Lines{Start: getLineNumber(srcStr, resource.tags.keyStart), End: getLineNumber(srcStr, resource.tags.valueEnd)}
+		}
+		bicepBlock := &BicepBlock{
+			Block: structure.Block{
+				FilePath:          filePath,
+				ExitingTags:       existingTags,
+				RawBlock:          resource,
+				IsTaggable:        !resource.existing && (resource.tags != nil || utils.IsTaggableAzureResourceType(resource.resourceType)),
+				TagsAttributeName: TagsAttributeName,
+				Lines:             structure.Lines{Start: getLineNumber(srcStr, resource.start), End: getLineNumber(srcStr, resource.end)},
+				TagLines:          tagsLines,
+				Name:              resource.name,
+				Type:              resource.resourceType,
+			},
+			APIVersion: resource.apiVersion,
+		}
+
+		for _, comment := range getLeadingComments(srcStr, resource.start) {
+			comment = strings.ToUpper(strings.ReplaceAll(comment, " ", ""))
+			if comment == "//YOR:SKIPALL" {
+				skipAll = true
+			}
+			if comment == "//YOR:SKIP" {
+				p.skippedByCommentList = append(p.skippedByCommentList, bicepBlock.GetResourceID())
+			}
+		}
+		if skipAll {
+			p.skippedByCommentList = append(p.skippedByCommentList, bicepBlock.GetResourceID())
+		}
+		parsedBlocks = append(parsedBlocks, bicepBlock)
+	}
+
+	return parsedBlocks, nil
+}
+
+// getExistingTags returns the tags which are set by object literals, a later object overrides the keys of the previous
+// ones like union() does
+func getExistingTags(resourceTags *bicepTags) []tags.ITag {
+	var existingTags []tags.ITag
+	indexByKey := make(map[string]int)
+	for _, object := range resourceTags.objects {
+		for _, entry := range object.entries {
+			tag := &tags.Tag{Key: entry.key, Value: entry.value}
+			if index, ok := indexByKey[entry.key]; ok {
+				existingTags[index] = tag
+				continue
+			}
+			indexByKey[entry.key] = len(existingTags)
+			existingTags = append(existingTags, tag)
+		}
+	}
+	return existingTags
+}
+
+// getLeadingComments returns the comment lines right above the given offset, skipping decorators
+func getLeadingComments(src string, offset int) []string {
+	var comments []string
+	lines := strings.Split(src[:offset], "\n")
+	for i := len(lines) - 2; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case strings.HasPrefix(line, "//"):
+			comments = append(comments, line)
+		case strings.HasPrefix(line, "@"):
+			continue
+		default:
+			return comments
+		}
+	}
+	return comments
+}
+
+func getLineNumber(src string, offset int) int {
+	return strings.Count(src[:offset], "\n") + 1
+}
+
+func (p *BicepParser) WriteFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(readFilePath), "temp.*.bicep")
+	defer func() {
+		_ = os.Remove(tempFile.Name())
+	}()
+	if err != nil {
+		return err
+	}
+	err = p.writeToFile(readFilePath, blocks, tempFile.Name())
+	if err != nil {
+		return err
+	}
+	tempBlocks, err := p.ParseFile(tempFile.Name())
+	if err != nil || len(tempBlocks) != len(blocks) {
+		return fmt.Errorf("editing file %v resulted in a malformed bicep file, please open a github issue with the relevant details", readFilePath)
+	}
+	return p.writeToFile(readFilePath, blocks, writeFilePath)
+}
+
+func (p *BicepParser) writeToFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	// #nosec G304
+	src, err := os.ReadFile(readFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s because %s", readFilePath, err)
+	}
+	srcStr := string(src)
+	resourcesByName := make(map[string]*bicepResource)
+	for _, resource := range scanBicepResources(srcStr) {
+		resourcesByName[resource.name] = resource
+	}
+	indent := detectIndent(srcStr, scanBicepResources(srcStr))
+
+	var edits []bicepEdit
+	for _, block := range blocks {
+		if !block.IsBlockTaggable() {
+			continue
+		}
+		resource, ok := resourcesByName[block.GetResourceID()]
+		if !ok {
+			logger.Warning(fmt.Sprintf("failed to find resource %v in %v", block.GetResourceID(), readFilePath))
+			continue
+		}
+		edits = append(edits, getResourceEdits(srcStr, resource, block.CalculateTagsDiff(), indent)...)
+	}
+
+	// apply the edits from the end of the file, so the offsets of the next edits are not affected
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start > edits[j].start
+	})
+	for _, edit := range edits {
+		srcStr = srcStr[:edit.start] + edit.text + srcStr[edit.end:]
+	}
+
+	return os.WriteFile(writeFilePath, []byte(srcStr), 0600)
+}
+
+// getResourceEdits updates the existing tags where they are set, and adds the new tags to the last object literal of the
+// tags. If there is no such object, the tags expression is wrapped with union()
+func getResourceEdits(src string, resource *bicepResource, diff *structure.TagDiff, indent string) []bicepEdit {
+	var edits []bicepEdit
+	if resource.tags != nil {
+		for _, tagDiff := range diff.Updated {
+			// update the last occurrence of the key, which is the one in effect
+			var lastEntry *bicepEntry
+			for _, object := range resource.tags.objects {
+				for _, entry := range object.entries {
+					if entry.key == tagDiff.Key {
+						lastEntry = entry
+					}
+				}
+			}
+			if lastEntry != nil {
+				edits = append(edits, bicepEdit{start: lastEntry.valueStart, end: lastEntry.valueEnd, text: quoteBicepString(tagDiff.NewValue)})
+			}
+		}
+	}
+	if len(diff.Added) == 0 {
+		return edits
+	}
+
+	switch {
+	case resource.tags == nil:
+		// add the tags as the last property of the resource
+		bodyIndent := getLineIndent(src, resource.start) + indent
+		if entries := parseBicepObject(src, resource.bodyOpen, resource.bodyClose).entries; len(entries) > 0 {
+			bodyIndent = getLineIndent(src, entries[0].keyStart)
+		}
+		closeLineStart := strings.LastIndexByte(src[:resource.bodyClose], '\n') + 1
+		if strings.TrimSpace(src[closeLineStart:resource.bodyClose]) != "" || closeLineStart <= resource.bodyOpen {
+			// the closing brace is not in a line of its own
+			text := "\n" + bodyIndent + TagsAttributeName + ": " + formatBicepObject(diff.Added, bodyIndent, indent) + "\n" + getLineIndent(src, resource.start)
+			edits = append(edits, bicepEdit{start: resource.bodyClose, end: resource.bodyClose, text: text})
+		} else {
+			text := bodyIndent + TagsAttributeName + ": " + formatBicepObject(diff.Added, bodyIndent, indent) + "\n"
+			edits = append(edits, bicepEdit{start: closeLineStart, end: closeLineStart, text: text})
+		}
+	case len(resource.tags.objects) > 0:
+		edits = append(edits, getObjectAdditionEdit(src, resource.tags.objects[len(resource.tags.objects)-1], diff.Added, indent))
+	case resource.tags.isUnion:
+		tagsIndent := getLineIndent(src, resource.tags.keyStart)
+		text := ", " + formatBicepObject(diff.Added, tagsIndent, indent)
+		edits = append(edits, bicepEdit{start: resource.tags.unionClose, end: resource.tags.unionClose, text: text})
+	default:
+		tagsIndent := getLineIndent(src, resource.tags.keyStart)
+		expression := src[resource.tags.valueStart:resource.tags.valueEnd]
+		text := "union(" + expression + ", " + formatBicepObject(diff.Added, tagsIndent, indent) + ")"
+		edits = append(edits, bicepEdit{start: resource.tags.valueStart, end: resource.tags.valueEnd, text: text})
+	}
+	return edits
+}
+
+// getObjectAdditionEdit appends entries to an object literal, keeping its single-line or multi-line layout
+func getObjectAdditionEdit(src string, object *bicepObject, added []tags.ITag, indent string) bicepEdit {
+	objectStr := src[object.open : object.close+1]
+	if !strings.Contains(objectStr, "\n") {
+		if len(object.entries) == 0 {
+			// expand an empty object to a multi-line one
+			return bicepEdit{start: object.open, end: object.close + 1, text: formatBicepObject(added, getLineIndent(src, object.open), indent)}
+		}
+		entries := make([]string, 0, len(added))
+		for _, tag := range added {
+			entries = append(entries, formatBicepEntry(tag))
+		}
+		lastEntryEnd := object.entries[len(object.entries)-1].valueEnd
+		return bicepEdit{start: lastEntryEnd, end: lastEntryEnd, text: ", " + strings.Join(entries, ", ")}
+	}
+
+	entryIndent := getLineIndent(src, object.close) + indent
+	if len(object.entries) > 0 {
+		entryIndent = getLineIndent(src, object.entries[0].keyStart)
+	}
+	var sb strings.Builder
+	for _, tag := range added {
+		sb.WriteString(entryIndent + formatBicepEntry(tag) + "\n")
+	}
+	closeLineStart := strings.LastIndexByte(src[:object.close], '\n') + 1
+	if strings.TrimSpace(src[closeLineStart:object.close]) != "" {
+		// the closing brace follows the last entry
+		insertAt := object.entries[len(object.entries)-1].valueEnd
+		return bicepEdit{start: insertAt, end: insertAt, text: "\n" + strings.TrimSuffix(sb.String(), "\n")}
+	}
+	return bicepEdit{start: closeLineStart, end: closeLineStart, text: sb.String()}
+}
+
+// formatBicepObject returns a multi-line object literal of the tags, where lineIndent is the indentation of the line
+// in which the object starts
+func formatBicepObject(tagsToAdd []tags.ITag, lineIndent string, indent string) string {
+	var sb strings.Builder
+	sb.WriteString("{\n")
+	for _, tag := range tagsToAdd {
+		sb.WriteString(lineIndent + indent + formatBicepEntry(tag) + "\n")
+	}
+	sb.WriteString(lineIndent + "}")
+	return sb.String()
+}
+
+func formatBicepEntry(tag tags.ITag) string {
+	return formatBicepKey(tag.GetKey()) + ": " + quoteBicepString(tag.GetValue())
+}
+
+func getLineIndent(src string, offset int) string {
+	lineStart := strings.LastIndexByte(src[:offset], '\n') + 1
+	line := src[lineStart:]
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// detectIndent returns the indentation unit of the file, based on the indentation of the first resource's properties
+func detectIndent(src string, resources []*bicepResource) string {
+	for _, resource := range resources {
+		entries := parseBicepObject(src, resource.bodyOpen, resource.bodyClose).entries
+		if len(entries) == 0 || getLineNumber(src, entries[0].keyStart) == getLineNumber(src, resource.bodyOpen) {
+			continue
+		}
+		resourceIndent := getLineIndent(src, resource.start)
+		if entryIndent := getLineIndent(src, entries[0].keyStart); len(entryIndent) > len(resourceIndent) {
+			return entryIndent[len(resourceIndent):]
+		}
+	}
+	return defaultIndent
+}
@@ -0,0 +1,110 @@
+package structure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBicepParser_ParseFile(t *testing.T) {
+	directory := "../../../tests/bicep/resources"
+	p := BicepParser{}
+	p.Init(directory, nil)
+	blocks, err := p.ParseFile(filepath.Join(directory, "main.bicep"))
+	if err != nil {
+		t.Errorf("ParseFile() error = %v", err)
+		return
+	}
+	blocksByName := map[string]*BicepBlock{}
+	var names []string
+	for _, block := range blocks {
+		blocksByName[block.GetResourceID()] = block.(*BicepBlock)
+		names = append(names, block.GetResourceID())
+	}
+	assert.Equal(t, []string{"storageAccount", "blobService", "vnet", "subnet", "plan", "site", "logs", "identity"}, names)
+
+	t.Run("resource with object literal tags", func(t *testing.T) {
+		storageAccount := blocksByName["storageAccount"]
+		assert.Equal(t, "Microsoft.Storage/storageAccounts", storageAccount.GetResourceType())
+		assert.Equal(t, "2022-09-01", storageAccount.APIVersion)
+		assert.Equal(t, structure.Lines{Start: 7, End: 22}, storageAccount.GetLines())
+		assert.Equal(t, structure.Lines{Start: 14, End: 17}, storageAccount.GetTagsLines())
+		assert.Equal(t, []tags.ITag{
+			&tags.Tag{Key: "env", Value: "dev"},
+			&tags.Tag{Key: "cost-center", Value: "1234"},
+		}, storageAccount.GetExistingTags())
+	})
+
+	t.Run("resource with union tags", func(t *testing.T) {
+		vnet := blocksByName["vnet"]
+		assert.True(t, vnet.IsBlockTaggable())
+		assert.Equal(t, structure.Lines{Start: 27, End: 29}, vnet.GetTagsLines())
+		assert.Equal(t, []tags.ITag{&tags.Tag{Key: "env", Value: "dev"}}, vnet.GetExistingTags())
+	})
+
+	t.Run("untaggable resources", func(t *testing.T) {
+		assert.False(t, blocksByName["blobService"].IsBlockTaggable())
+		assert.False(t, blocksByName["subnet"].IsBlockTaggable())
+		assert.False(t, blocksByName["logs"].IsBlockTaggable())
+		assert.True(t, blocksByName["site"].IsBlockTaggable())
+		assert.Nil(t, blocksByName["plan"].GetExistingTags())
+	})
+
+	t.Run("skip comment", func(t *testing.T) {
+		assert.Equal(t, []string{"identity"}, p.GetSkipResourcesByComment())
+	})
+}
+
+func TestBicepParser_WriteFile(t *testing.T) {
+	directory := "../../../tests/bicep/resources"
+	p := BicepParser{}
+	p.Init(directory, nil)
+	filePath := filepath.Join(directory, "main.bicep")
+	blocks, err := p.ParseFile(filePath)
+	if err != nil {
+		t.Errorf("ParseFile() error = %v", err)
+		return
+	}
+	for _, block := range blocks {
+		if !block.IsBlockTaggable() || block.GetResourceID() == "identity" {
+			continue
+		}
+		block.AddNewTags([]tags.ITag{
+			&tags.Tag{Key: "env", Value: "prod"},
+			&tags.Tag{Key: "yor_trace", Value: "4b5c6d7e-8f90-4a1b-9c2d-3e4f5a6b7c8d"},
+			&tags.Tag{Key: "git_file", Value: "main.bicep"},
+		})
+	}
+	f, _ := os.CreateTemp(directory, "main.*.bicep")
+	defer func() { _ = os.Remove(f.Name()) }()
+	err = p.WriteFile(filePath, blocks, f.Name())
+	if err != nil {
+		t.Errorf("WriteFile() error = %v", err)
+		return
+	}
+
+	expected, _ := os.ReadFile(filepath.Join(directory, "main_expected.bicep"))
+	actual, _ := os.ReadFile(f.Name())
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestBicepSyntax(t *testing.T) {
+	t.Run("quote strings", func(t *testing.T) {
+		assert.Equal(t, `'it\'s \${x}'`, quoteBicepString("it's ${x}"))
+		assert.Equal(t, "it's ${x}", unquoteBicepString(`'it\'s \${x}'`))
+	})
+
+	t.Run("format keys", func(t *testing.T) {
+		assert.Equal(t, "yor_trace", formatBicepKey("yor_trace"))
+		assert.Equal(t, "'git-org'", formatBicepKey("git-org"))
+	})
+
+	t.Run("strings with interpolations", func(t *testing.T) {
+		src := `'${format('{0}', 'a')}-b' rest`
+		assert.Equal(t, len(src)-len(" rest"), skipString(src, 0))
+	})
+}
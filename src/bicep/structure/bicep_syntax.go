@@ -0,0 +1,417 @@
+package structure
+
+import (
+	"strings"
+)
+
+// bicepResource is a resource declaration found in the text of a bicep file, with the offsets needed to edit it
+type bicepResource struct {
+	name         string
+	resourceType string
+	apiVersion   string
+	existing     bool
+	start        int // offset of the `resource` keyword
+	end          int // offset of the last character of the declaration
+	bodyOpen     int
+	bodyClose    int
+	tags         *bicepTags
+}
+
+// bicepTags is the value of the tags property of a resource. It is either an object literal, a call to union() which
+// merges objects, or any other expression, such as a reference to a parameter or a variable
+type bicepTags struct {
+	keyStart   int
+	valueStart int
+	valueEnd   int
+	isUnion    bool
+	unionClose int
+	objects    []*bicepObject // object literals which make up the tags, in the order they are merged
+}
+
+type bicepObject struct {
+	open    int
+	close   int
+	entries []*bicepEntry
+}
+
+type bicepEntry struct {
+	key        string
+	keyStart   int
+	valueStart int
+	valueEnd   int
+	value      string
+}
+
+// scanBicepResources finds all the resource declarations in the file, including resources nested in other resources
+func scanBicepResources(src string) []*bicepResource {
+	resources := make([]*bicepResource, 0)
+	for i := 0; i < len(src); {
+		switch {
+		case isCommentStart(src, i):
+			i = skipComment(src, i)
+		case src[i] == '\'':
+			i = skipString(src, i)
+		case isIdentifierStart(src[i]):
+			word, end := readIdentifier(src, i)
+			if word == "resource" && isStatementStart(src, i) {
+				if resource := parseResourceDeclaration(src, i, end); resource != nil {
+					resources = append(resources, resource)
+					// continue inside the body to find nested resources
+					i = resource.bodyOpen + 1
+					continue
+				}
+			}
+			i = end
+		default:
+			i++
+		}
+	}
+	return resources
+}
+
+// parseResourceDeclaration parses `resource <name> '<type>@<version>' [existing] = [if (...)] [for ...:] {...}`
+func parseResourceDeclaration(src string, start int, i int) *bicepResource {
+	resource := &bicepResource{start: start}
+	i = skipTrivia(src, i)
+	if i >= len(src) || !isIdentifierStart(src[i]) {
+		return nil
+	}
+	resource.name, i = readIdentifier(src, i)
+	i = skipTrivia(src, i)
+	if i >= len(src) || src[i] != '\'' {
+		return nil
+	}
+	typeEnd := skipString(src, i)
+	typeWithVersion := unquoteBicepString(src[i:typeEnd])
+	resource.resourceType, resource.apiVersion, _ = strings.Cut(typeWithVersion, "@")
+	i = skipTrivia(src, typeEnd)
+	if word, end := readIdentifier(src, i); word == "existing" {
+		resource.existing = true
+		i = skipTrivia(src, end)
+	}
+	if i >= len(src) || src[i] != '=' {
+		return nil
+	}
+	i++
+	isLoop := false
+	for i < len(src) && src[i] != '{' {
+		switch {
+		case isCommentStart(src, i):
+			i = skipComment(src, i)
+		case src[i] == '\'':
+			i = skipString(src, i)
+		case src[i] == '(':
+			i = matchBracket(src, i) + 1
+		case src[i] == '[':
+			isLoop = true
+			i++
+		default:
+			i++
+		}
+	}
+	if i >= len(src) {
+		return nil
+	}
+	resource.bodyOpen = i
+	resource.bodyClose = matchBracket(src, i)
+	if resource.bodyClose >= len(src) {
+		return nil
+	}
+	resource.end = resource.bodyClose
+	if isLoop {
+		if loopEnd := skipTrivia(src, resource.bodyClose+1); loopEnd < len(src) && src[loopEnd] == ']' {
+			resource.end = loopEnd
+		}
+	}
+	for _, entry := range parseBicepObject(src, resource.bodyOpen, resource.bodyClose).entries {
+		if entry.key == TagsAttributeName {
+			resource.tags = parseBicepTags(src, entry)
+		}
+	}
+	return resource
+}
+
+func parseBicepTags(src string, entry *bicepEntry) *bicepTags {
+	tags := &bicepTags{keyStart: entry.keyStart, valueStart: entry.valueStart, valueEnd: entry.valueEnd}
+	value := src[entry.valueStart:entry.valueEnd]
+	switch {
+	case strings.HasPrefix(value, "{"):
+		tags.objects = append(tags.objects, parseBicepObject(src, entry.valueStart, matchBracket(src, entry.valueStart)))
+	case strings.HasPrefix(value, "union"):
+		open := skipTrivia(src, entry.valueStart+len("union"))
+		if open >= entry.valueEnd || src[open] != '(' {
+			break
+		}
+		tags.isUnion = true
+		tags.unionClose = matchBracket(src, open)
+		// the arguments of union() which are object literals
+		for i := open + 1; i < tags.unionClose; {
+			i = skipTrivia(src, i)
+			if i >= tags.unionClose {
+				break
+			}
+			if src[i] == '{' {
+				objectClose := matchBracket(src, i)
+				tags.objects = append(tags.objects, parseBicepObject(src, i, objectClose))
+				i = objectClose + 1
+				continue
+			}
+			i = skipExpression(src, i, ",)")
+			if i < tags.unionClose && src[i] == ',' {
+				i++
+			}
+		}
+	}
+	return tags
+}
+
+// parseBicepObject parses the properties of the object between the given braces. Statements which are not properties,
+// such as nested resource declarations, are skipped
+func parseBicepObject(src string, open int, closeBrace int) *bicepObject {
+	object := &bicepObject{open: open, close: closeBrace}
+	for i := open + 1; i < closeBrace; {
+		i = skipTrivia(src, i)
+		if i >= closeBrace {
+			break
+		}
+		if src[i] == ',' {
+			i++
+			continue
+		}
+		keyStart := i
+		key := ""
+		switch {
+		case src[i] == '\'':
+			end := skipString(src, i)
+			key = unquoteBicepString(src[i:end])
+			i = end
+		case isIdentifierStart(src[i]):
+			key, i = readIdentifier(src, i)
+		}
+		afterKey := skipInlineSpaces(src, i)
+		if key == "" || afterKey >= closeBrace || src[afterKey] != ':' {
+			// not a property, skip the rest of the statement
+			i = skipExpression(src, i, "\n")
+			continue
+		}
+		valueStart := skipInlineSpaces(src, afterKey+1)
+		valueEnd := skipExpression(src, valueStart, ",\n}")
+		if valueEnd > closeBrace {
+			valueEnd = closeBrace
+		}
+		valueEnd = trimExpressionEnd(src, valueStart, valueEnd)
+		object.entries = append(object.entries, &bicepEntry{
+			key:        key,
+			keyStart:   keyStart,
+			valueStart: valueStart,
+			valueEnd:   valueEnd,
+			value:      getBicepValue(src[valueStart:valueEnd]),
+		})
+		i = valueEnd
+	}
+	return object
+}
+
+// getBicepValue returns the value of a string literal, or the expression itself for any other value
+func getBicepValue(expression string) string {
+	if strings.HasPrefix(expression, "'") && skipString(expression, 0) == len(expression) && !strings.Contains(expression, "${") {
+		return unquoteBicepString(expression)
+	}
+	return expression
+}
+
+// skipExpression returns the offset of the first of the stop characters which is not nested in brackets, a string or
+// a comment
+func skipExpression(src string, i int, stopChars string) int {
+	for i < len(src) {
+		switch {
+		case strings.IndexByte(stopChars, src[i]) != -1:
+			return i
+		case isCommentStart(src, i):
+			if strings.HasPrefix(src[i:], "//") && strings.Contains(stopChars, "\n") {
+				return i
+			}
+			i = skipComment(src, i)
+		case src[i] == '\'':
+			i = skipString(src, i)
+		case src[i] == '{' || src[i] == '(' || src[i] == '[':
+			i = matchBracket(src, i) + 1
+		default:
+			i++
+		}
+	}
+	return i
+}
+
+// trimExpressionEnd removes trailing whitespace and comments from the expression between start and end
+func trimExpressionEnd(src string, start int, end int) int {
+	lastEnd := start
+	for i := start; i < end; {
+		switch {
+		case isCommentStart(src, i):
+			i = skipComment(src, i)
+		case src[i] == ' ' || src[i] == '\t' || src[i] == '\r' || src[i] == '\n':
+			i++
+		case src[i] == '\'':
+			i = skipString(src, i)
+			lastEnd = i
+		case src[i] == '{' || src[i] == '(' || src[i] == '[':
+			i = matchBracket(src, i) + 1
+			lastEnd = i
+		default:
+			i++
+			lastEnd = i
+		}
+	}
+	if lastEnd > end {
+		return end
+	}
+	return lastEnd
+}
+
+// matchBracket returns the offset of the bracket which closes the bracket at the given offset
+func matchBracket(src string, open int) int {
+	closing := map[byte]byte{'{': '}', '(': ')', '[': ']'}[src[open]]
+	for i := open + 1; i < len(src); {
+		switch {
+		case src[i] == closing:
+			return i
+		case isCommentStart(src, i):
+			i = skipComment(src, i)
+		case src[i] == '\'':
+			i = skipString(src, i)
+		case src[i] == '{' || src[i] == '(' || src[i] == '[':
+			i = matchBracket(src, i) + 1
+		default:
+			i++
+		}
+	}
+	return len(src)
+}
+
+// skipString returns the offset after the string which starts at the given offset, including multi-line strings and
+// interpolations which contain strings themselves
+func skipString(src string, i int) int {
+	if strings.HasPrefix(src[i:], "'''") {
+		end := strings.Index(src[i+3:], "'''")
+		if end == -1 {
+			return len(src)
+		}
+		return i + 3 + end + 3
+	}
+	for i++; i < len(src); i++ {
+		switch {
+		case src[i] == '\\':
+			i++
+		case src[i] == '\'':
+			return i + 1
+		case strings.HasPrefix(src[i:], "${"):
+			i = matchBracket(src, i+1)
+		}
+	}
+	return len(src)
+}
+
+func isCommentStart(src string, i int) bool {
+	return strings.HasPrefix(src[i:], "//") || strings.HasPrefix(src[i:], "/*")
+}
+
+func skipComment(src string, i int) int {
+	if strings.HasPrefix(src[i:], "//") {
+		end := strings.IndexByte(src[i:], '\n')
+		if end == -1 {
+			return len(src)
+		}
+		return i + end
+	}
+	end := strings.Index(src[i+2:], "*/")
+	if end == -1 {
+		return len(src)
+	}
+	return i + 2 + end + 2
+}
+
+// skipTrivia skips whitespace, newlines and comments
+func skipTrivia(src string, i int) int {
+	for i < len(src) {
+		switch {
+		case isCommentStart(src, i):
+			i = skipComment(src, i)
+		case src[i] == ' ' || src[i] == '\t' || src[i] == '\r' || src[i] == '\n':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+func skipInlineSpaces(src string, i int) int {
+	for i < len(src) && (src[i] == ' ' || src[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentifierChar(c byte) bool {
+	return isIdentifierStart(c) || (c >= '0' && c <= '9')
+}
+
+func readIdentifier(src string, i int) (string, int) {
+	start := i
+	for i < len(src) && isIdentifierChar(src[i]) {
+		i++
+	}
+	return src[start:i], i
+}
+
+// isStatementStart checks that only whitespace precedes the given offset in its line
+func isStatementStart(src string, i int) bool {
+	lineStart := strings.LastIndexByte(src[:i], '\n') + 1
+	return strings.TrimSpace(src[lineStart:i]) == ""
+}
+
+func unquoteBicepString(literal string) string {
+	if strings.HasPrefix(literal, "'''") {
+		return strings.TrimSuffix(strings.TrimPrefix(literal, "'''"), "'''")
+	}
+	literal = strings.TrimSuffix(strings.TrimPrefix(literal, "'"), "'")
+	var sb strings.Builder
+	for i := 0; i < len(literal); i++ {
+		if literal[i] == '\\' && i+1 < len(literal) {
+			i++
+			switch literal[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(literal[i])
+			}
+			continue
+		}
+		sb.WriteByte(literal[i])
+	}
+	return sb.String()
+}
+
+func quoteBicepString(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "${", `\${`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return "'" + replacer.Replace(value) + "'"
+}
+
+// formatBicepKey quotes the key of a property unless it is a valid identifier
+func formatBicepKey(key string) string {
+	if key != "" && isIdentifierStart(key[0]) {
+		if identifier, end := readIdentifier(key, 0); end == len(key) && identifier == key {
+			return key
+		}
+	}
+	return quoteBicepString(key)
+}
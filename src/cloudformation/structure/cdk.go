@@ -0,0 +1,144 @@
+package structure
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common/logger"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+)
+
+const CdkPathMetadataKey = "aws:cdk:path"
+const CdkTagsFileName = "cdk-tags.json"
+const cdkManifestFileName = "manifest.json"
+const cdkTemplateSuffix = ".template.json"
+
+// cdkMetadataResourceType is the type of the analytics resource the CDK adds to every stack, it is not taggable and
+// cannot be parsed by goformation
+const cdkMetadataResourceType = "AWS::CDK::Metadata"
+
+// isCdkTemplate checks if the file is a template synthesized by the AWS CDK, i.e. a template in a cloud assembly
+// directory (cdk.out) which holds a manifest.json
+func isCdkTemplate(filePath string) bool {
+	if !strings.HasSuffix(filePath, cdkTemplateSuffix) {
+		return false
+	}
+	return isCloudAssemblyDir(filepath.Dir(filePath))
+}
+
+func isCloudAssemblyDir(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, cdkManifestFileName))
+	return err == nil && !info.IsDir()
+}
+
+// getCdkAppDir returns the directory of the CDK app which synthesized the template, which is the parent of the root
+// cloud assembly. Templates of stages are synthesized to nested assemblies, e.g. cdk.out/assembly-Prod
+func getCdkAppDir(templatePath string) string {
+	assemblyDir := filepath.Dir(templatePath)
+	for {
+		parentDir := filepath.Dir(assemblyDir)
+		if parentDir == assemblyDir || !isCloudAssemblyDir(parentDir) {
+			break
+		}
+		assemblyDir = parentDir
+	}
+	return filepath.Dir(assemblyDir)
+}
+
+// readCdkTemplate reads a synthesized template without its CDK metadata resources, and maps the logical IDs of its
+// resources to their construct paths
+func readCdkTemplate(filePath string) ([]byte, map[string]string, error) {
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	var template map[string]interface{}
+	if err = stdjson.Unmarshal(src, &template); err != nil {
+		return nil, nil, err
+	}
+	constructPaths := make(map[string]string)
+	resources, _ := template[ResourcesStartToken].(map[string]interface{})
+	for logicalID, resource := range resources {
+		resourceMap, ok := resource.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if resourceMap["Type"] == cdkMetadataResourceType {
+			delete(resources, logicalID)
+			continue
+		}
+		if metadata, ok := resourceMap["Metadata"].(map[string]interface{}); ok {
+			if constructPath, ok := metadata[CdkPathMetadataKey].(string); ok {
+				constructPaths[logicalID] = constructPath
+			}
+		}
+	}
+	data, err := stdjson.Marshal(template)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, constructPaths, nil
+}
+
+// addCdkTags collects the tags yor manages for the blocks of a synthesized template by their construct paths. The
+// collected tags include the existing trace tag, so the CDK app keeps applying it after the next synth.
+func (p *CloudformationParser) addCdkTags(templatePath string, blocks []structure.IBlock) {
+	appDir := getCdkAppDir(templatePath)
+	p.cdkTagsLock.Lock()
+	defer p.cdkTagsLock.Unlock()
+	if _, ok := p.cdkTags[appDir]; !ok {
+		p.cdkTags[appDir] = make(map[string]map[string]string)
+	}
+	for _, block := range blocks {
+		cfnBlock, ok := block.(*CloudformationBlock)
+		if !ok || !cfnBlock.IsBlockTaggable() || cfnBlock.ConstructPath == "" || len(cfnBlock.GetNewTags()) == 0 {
+			continue
+		}
+		newTagKeys := make(map[string]struct{})
+		for _, tag := range cfnBlock.GetNewTags() {
+			newTagKeys[tag.GetKey()] = struct{}{}
+		}
+		constructTags := make(map[string]string)
+		for _, tag := range cfnBlock.MergeTags() {
+			if _, ok := newTagKeys[tag.GetKey()]; ok || tags.IsTagKeyMatch(tag, tags.YorTraceTagKey) {
+				constructTags[tag.GetKey()] = tag.GetValue()
+			}
+		}
+		p.cdkTags[appDir][cfnBlock.ConstructPath] = constructTags
+	}
+}
+
+// writeCdkTags writes the collected tags to the cdk-tags.json file of each CDK app. Construct paths which were not
+// tagged in this run keep their entries from the existing file.
+func (p *CloudformationParser) writeCdkTags() {
+	for appDir, constructsTags := range p.cdkTags {
+		if len(constructsTags) == 0 {
+			continue
+		}
+		tagsFilePath := filepath.Join(appDir, CdkTagsFileName)
+		allConstructsTags := make(map[string]map[string]string)
+		// #nosec G304
+		if src, err := os.ReadFile(tagsFilePath); err == nil {
+			if err = stdjson.Unmarshal(src, &allConstructsTags); err != nil {
+				logger.Warning(fmt.Sprintf("failed to parse %s, overwriting it: %v", tagsFilePath, err))
+				allConstructsTags = make(map[string]map[string]string)
+			}
+		}
+		for constructPath, constructTags := range constructsTags {
+			allConstructsTags[constructPath] = constructTags
+		}
+		data, err := stdjson.MarshalIndent(allConstructsTags, "", "  ")
+		if err != nil {
+			logger.Warning(fmt.Sprintf("failed to marshal the tags of %s: %v", tagsFilePath, err))
+			continue
+		}
+		if err = os.WriteFile(tagsFilePath, append(data, '\n'), 0600); err != nil {
+			logger.Warning(fmt.Sprintf("failed to write %s: %v", tagsFilePath, err))
+		}
+	}
+}
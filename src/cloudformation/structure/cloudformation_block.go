@@ -3,6 +3,7 @@ package structure
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	goformationTags "github.com/awslabs/goformation/v5/cloudformation/tags"
 	"github.com/bridgecrewio/yor/src/common/logger"
@@ -11,6 +12,8 @@ import (
 
 type CloudformationBlock struct {
 	structure.Block
+	// ConstructPath is the path of the construct which defined the resource, for templates synthesized by the AWS CDK
+	ConstructPath string
 }
 
 func (b *CloudformationBlock) GetFramework() string {
@@ -23,12 +26,22 @@ func (b *CloudformationBlock) UpdateTags() {
 	}
 
 	mergedTags := b.MergeTags()
-	cfnMergedTags := make([]goformationTags.Tag, 0)
-	for _, t := range mergedTags {
-		cfnMergedTags = append(cfnMergedTags, goformationTags.Tag{
-			Key:   t.GetKey(),
-			Value: t.GetValue(),
-		})
+	var cfnMergedTags interface{}
+	if b.HasMapTags() {
+		samMergedTags := make(map[string]string)
+		for _, t := range mergedTags {
+			samMergedTags[t.GetKey()] = t.GetValue()
+		}
+		cfnMergedTags = samMergedTags
+	} else {
+		listMergedTags := make([]goformationTags.Tag, 0)
+		for _, t := range mergedTags {
+			listMergedTags = append(listMergedTags, goformationTags.Tag{
+				Key:   t.GetKey(),
+				Value: t.GetValue(),
+			})
+		}
+		cfnMergedTags = listMergedTags
 	}
 
 	blockBytes, _ := json.Marshal(b.RawBlock)
@@ -43,6 +56,21 @@ func (b *CloudformationBlock) UpdateTags() {
 	b.RawBlock = blockAsMap
 }
 
+// MigrateTags removes and renames the existing tags of the resource. The resources of templates synthesized by the AWS
+// CDK are not migrated, as the templates are overwritten on the next synth.
+func (b *CloudformationBlock) MigrateTags(migrations *structure.TagMigrations) {
+	if b.ConstructPath != "" {
+		return
+	}
+	b.ApplyTagMigrations(migrations, b.ExitingTags)
+}
+
+// HasMapTags returns true for AWS SAM resources, which take their tags as a key-value map instead of a list of
+// Key/Value pairs
+func (b *CloudformationBlock) HasMapTags() bool {
+	return strings.HasPrefix(b.Type, SAMResourceTypePrefix)
+}
+
 func (b *CloudformationBlock) GetTagsLines() structure.Lines {
 	return b.TagLines
 }
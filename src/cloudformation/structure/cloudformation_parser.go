@@ -8,6 +8,8 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -30,11 +32,18 @@ type CloudformationParser struct {
 	*types.YamlParser
 	*types.JSONParser
 	skippedByCommentList []string
+	// cdkOut makes the parser collect the tags of templates synthesized by the AWS CDK into cdk-tags.json, keyed by
+	// construct path, instead of modifying the templates
+	cdkOut      bool
+	cdkTags     map[string]map[string]map[string]string
+	cdkTagsLock sync.Mutex
 }
 
 const TagsAttributeName = "Tags"
 const ResourcesStartToken = "Resources"
 const EnvVarsPath = "Resources/*/Properties/Environment/Variables/*"
+const SAMResourceTypePrefix = "AWS::Serverless::"
+const SAMTransformPrefix = "AWS::Serverless"
 
 var goformationLock sync.Mutex
 
@@ -42,16 +51,23 @@ func (p *CloudformationParser) Name() string {
 	return "CloudFormation"
 }
 
-func (p *CloudformationParser) Init(rootDir string, _ map[string]string) {
+func (p *CloudformationParser) Init(rootDir string, args map[string]string) {
 	p.YamlParser = &types.YamlParser{
 		RootDir: rootDir,
 	}
 	p.JSONParser = &types.JSONParser{
 		RootDir: rootDir,
 	}
+	p.cdkTags = make(map[string]map[string]map[string]string)
+	if argCdkOut, ok := args["cdk-out"]; ok {
+		p.cdkOut, _ = strconv.ParseBool(argCdkOut)
+	}
 }
 
-func (p CloudformationParser) Close() {
+func (p *CloudformationParser) Close() {
+	if p.cdkOut {
+		p.writeCdkTags()
+	}
 }
 
 func (p *CloudformationParser) GetSkippedDirs() []string {
@@ -62,8 +78,12 @@ func (p *CloudformationParser) GetSupportedFileExtensions() []string {
 	return []string{common.YamlFileType.Extension, common.YmlFileType.Extension, common.CFTFileType.Extension, common.JSONFileType.Extension}
 }
 
-// ValidFile Validate file has AWSTemplateFormatVersion
+// ValidFile Validate file has AWSTemplateFormatVersion or the AWS SAM transform, or is synthesized by the AWS CDK when
+// running in CDK mode
 func (p *CloudformationParser) ValidFile(filePath string) bool {
+	if p.cdkOut && isCdkTemplate(filePath) {
+		return true
+	}
 	// #nosec G304
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -94,10 +114,47 @@ func (p *CloudformationParser) ValidFile(filePath string) bool {
 		return false
 	}
 	_, hasHeader := result["AWSTemplateFormatVersion"]
-	return hasHeader
+	return hasHeader || isSAMTransform(result["Transform"])
+}
+
+// isSAMTransform checks if the Transform section of a template, a string or a list of strings, includes the SAM transform
+func isSAMTransform(transform interface{}) bool {
+	switch t := transform.(type) {
+	case string:
+		return strings.HasPrefix(t, SAMTransformPrefix)
+	case []interface{}:
+		for _, item := range t {
+			if isSAMTransform(item) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
-func goformationParse(file string) (*cloudformation.Template, error) {
+// hasNonSAMTransform checks if the template has transforms other than the SAM transform, such as AWS::Include or custom
+// macros, whose input its resources are
+func hasNonSAMTransform(transform *cloudformation.Transform) bool {
+	if transform == nil {
+		return false
+	}
+	var transforms []string
+	if transform.String != nil {
+		transforms = append(transforms, *transform.String)
+	}
+	if transform.StringArray != nil {
+		transforms = append(transforms, *transform.StringArray...)
+	}
+	for _, t := range transforms {
+		if !isSAMTransform(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// goformationParse parses the template file, or the given JSON template data of the file if it is not nil
+func goformationParse(file string, data []byte) (*cloudformation.Template, error) {
 	var template *cloudformation.Template
 	var err error
 	defer func() {
@@ -107,16 +164,30 @@ func goformationParse(file string) (*cloudformation.Template, error) {
 		}
 	}()
 
-	template, err = goformation.OpenWithOptions(file, &intrinsics.ProcessorOptions{
+	options := &intrinsics.ProcessorOptions{
 		StringifyPaths: []string{EnvVarsPath},
-	})
+	}
+	if data != nil {
+		template, err = goformation.ParseJSONWithOptions(data, options)
+	} else {
+		template, err = goformation.OpenWithOptions(file, options)
+	}
 	return template, err
 }
 
 func (p *CloudformationParser) ParseFile(filePath string) ([]structure.IBlock, error) {
 	var skipResourcesByComment []string
+	var templateData []byte
+	var constructPaths map[string]string
+	if p.cdkOut && isCdkTemplate(filePath) {
+		var err error
+		templateData, constructPaths, err = readCdkTemplate(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cdk template %s because %s", filePath, err)
+		}
+	}
 	goformationLock.Lock()
-	template, err := goformationParse(filePath)
+	template, err := goformationParse(filePath, templateData)
 	goformationLock.Unlock()
 	if err != nil || template == nil {
 		logger.Warning(fmt.Sprintf("There was an error processing the cloudformation template %v: %s", filePath, err))
@@ -126,8 +197,8 @@ func (p *CloudformationParser) ParseFile(filePath string) ([]structure.IBlock, e
 		return nil, err
 	}
 
-	if template.Transform != nil {
-		logger.Info(fmt.Sprintf("Skipping CFN template %s as SAM templates are not yet supported", filePath))
+	if hasNonSAMTransform(template.Transform) {
+		logger.Info(fmt.Sprintf("Skipping CFN template %s as its transforms other than AWS SAM are not supported", filePath))
 		return nil, nil
 	}
 
@@ -178,6 +249,7 @@ func (p *CloudformationParser) ParseFile(filePath string) ([]structure.IBlock, e
 					Name:              resourceName,
 					Type:              resourceType,
 				},
+				ConstructPath: constructPaths[resourceName],
 			}
 			parsedBlocks = append(parsedBlocks, cfnBlock)
 		}
@@ -197,7 +269,17 @@ func (p *CloudformationParser) extractTagsAndLines(filePath string, lines *struc
 
 func (p *CloudformationParser) GetExistingTags(tagsValue reflect.Value) []tags.ITag {
 	existingTags := make([]goformationTags.Tag, 0)
-	if tagsValue.Kind() == reflect.Slice {
+	switch tagsValue.Kind() {
+	case reflect.Map:
+		// AWS SAM resources hold their tags as a key-value map
+		iter := tagsValue.MapRange()
+		for iter.Next() {
+			existingTags = append(existingTags, goformationTags.Tag{Key: fmt.Sprint(iter.Key().Interface()), Value: fmt.Sprint(iter.Value().Interface())})
+		}
+		sort.Slice(existingTags, func(i, j int) bool {
+			return existingTags[i].Key < existingTags[j].Key
+		})
+	case reflect.Slice:
 		//nolint:ineffassign
 		ok := true
 		existingTags, ok = tagsValue.Interface().([]goformationTags.Tag)
@@ -225,6 +307,11 @@ func (p *CloudformationParser) GetExistingTags(tagsValue reflect.Value) []tags.I
 }
 
 func (p *CloudformationParser) WriteFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	if p.cdkOut && isCdkTemplate(readFilePath) {
+		// synthesized templates are overwritten on the next synth, the tags are applied by the CDK app instead
+		p.addCdkTags(readFilePath, blocks)
+		return nil
+	}
 	for _, block := range blocks {
 		block := block.(*CloudformationBlock)
 		block.UpdateTags()
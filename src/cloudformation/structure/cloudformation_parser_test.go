@@ -103,6 +103,44 @@ func TestCloudformationParser_ParseFile(t *testing.T) {
 		assert.Equal(t, "isSpecial", existingTag.GetKey())
 		assert.Equal(t, "true", existingTag.GetValue())
 	})
+
+	t.Run("parse SAM template", func(t *testing.T) {
+		directory := "../../../tests/cloudformation/resources/sam"
+		cfnParser := CloudformationParser{}
+		cfnParser.Init(directory, nil)
+		assert.True(t, cfnParser.ValidFile(directory+"/sam.yaml"))
+		cfnBlocks, err := cfnParser.ParseFile(directory + "/sam.yaml")
+		if err != nil {
+			t.Errorf("ParseFile() error = %v", err)
+			return
+		}
+		assert.Equal(t, 4, len(cfnBlocks))
+		blocksByName := map[string]structure.IBlock{}
+		for _, block := range cfnBlocks {
+			blocksByName[block.GetResourceID()] = block
+		}
+		function := blocksByName["HelloFunction"]
+		assert.True(t, function.IsBlockTaggable())
+		assert.True(t, structure.HasMapTags(function))
+		assert.Equal(t, structure.Lines{Start: 4, End: 12}, function.GetLines())
+		assert.Equal(t, structure.Lines{Start: 10, End: 12}, function.GetTagsLines())
+		assert.Equal(t, []tags.ITag{
+			&tags.Tag{Key: "env", Value: "dev"},
+			&tags.Tag{Key: "team", Value: "serverless"},
+		}, function.GetExistingTags())
+		assert.True(t, blocksByName["HelloApi"].IsBlockTaggable())
+		assert.True(t, blocksByName["HelloTable"].IsBlockTaggable())
+		assert.False(t, structure.HasMapTags(blocksByName["HelloBucket"]))
+	})
+
+	t.Run("skip templates with transforms other than SAM", func(t *testing.T) {
+		directory := "../../../tests/cloudformation/resources/sam"
+		cfnParser := CloudformationParser{}
+		cfnParser.Init(directory, nil)
+		cfnBlocks, err := cfnParser.ParseFile(directory + "/include.yaml")
+		assert.Nil(t, err)
+		assert.Empty(t, cfnBlocks)
+	})
 }
 
 func compareLines(t *testing.T, expected map[string]*structure.Lines, actual map[string]*structure.Lines) {
@@ -238,4 +276,102 @@ func TestWriteCFN(t *testing.T) {
 		writeCFNTestHelper(t, directory, "cfn", "yaml")
 	})
 
+	t.Run("test SAM yaml writing", func(t *testing.T) {
+		directory := "../../../tests/cloudformation/resources/sam"
+		writeCFNTestHelper(t, directory, "sam", "yaml")
+	})
+
+	t.Run("test SAM json writing", func(t *testing.T) {
+		directory := "../../../tests/cloudformation/resources/sam"
+		writeCFNTestHelper(t, directory, "sam", "json")
+	})
+
+}
+
+func copyCdkOutTestHelper(t *testing.T) string {
+	appDir := t.TempDir()
+	sourceDir := "../../../tests/cloudformation/resources/cdk/cdk.out"
+	assemblyDir := filepath.Join(appDir, "cdk.out")
+	if err := os.Mkdir(assemblyDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	for _, fileName := range []string{"cdk.out", "manifest.json", "YorStack.template.json"} {
+		content, err := os.ReadFile(filepath.Join(sourceDir, fileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(filepath.Join(assemblyDir, fileName), content, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return appDir
+}
+
+func TestCloudformationParser_CdkOut(t *testing.T) {
+	t.Run("cdk templates are only valid in cdk mode", func(t *testing.T) {
+		templatePath := "../../../tests/cloudformation/resources/cdk/cdk.out/YorStack.template.json"
+		cfnParser := CloudformationParser{}
+		cfnParser.Init("../../../tests/cloudformation/resources/cdk", nil)
+		assert.False(t, cfnParser.ValidFile(templatePath))
+		cdkParser := CloudformationParser{}
+		cdkParser.Init("../../../tests/cloudformation/resources/cdk", map[string]string{"cdk-out": "true"})
+		assert.True(t, cdkParser.ValidFile(templatePath))
+		assert.False(t, cdkParser.ValidFile("../../../tests/cloudformation/resources/cdk/cdk.out/manifest.json"))
+	})
+
+	t.Run("parse cdk template", func(t *testing.T) {
+		directory := "../../../tests/cloudformation/resources/cdk"
+		cfnParser := CloudformationParser{}
+		cfnParser.Init(directory, map[string]string{"cdk-out": "true"})
+		cfnBlocks, err := cfnParser.ParseFile(directory + "/cdk.out/YorStack.template.json")
+		if err != nil {
+			t.Errorf("ParseFile() error = %v", err)
+			return
+		}
+		constructPaths := map[string]string{}
+		for _, block := range cfnBlocks {
+			constructPaths[block.GetResourceID()] = block.(*CloudformationBlock).ConstructPath
+		}
+		assert.Equal(t, map[string]string{
+			"Bucket83908E77": "YorStack/Bucket/Resource",
+			"QueueB9A21DBE":  "YorStack/Queue/Resource",
+		}, constructPaths)
+	})
+
+	t.Run("write cdk tags by construct path", func(t *testing.T) {
+		appDir := copyCdkOutTestHelper(t)
+		templatePath := filepath.Join(appDir, "cdk.out", "YorStack.template.json")
+		tagsFilePath := filepath.Join(appDir, CdkTagsFileName)
+		err := os.WriteFile(tagsFilePath, []byte(`{"YorStack/Topic/Resource": {"yor_trace": "5b1c8f9e-2d3a-4e6f-9a7b-8c0d1e2f3a4b"}}`), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		originalTemplate, _ := os.ReadFile(templatePath)
+
+		cfnParser := CloudformationParser{}
+		cfnParser.Init(appDir, map[string]string{"cdk-out": "true"})
+		cfnBlocks, err := cfnParser.ParseFile(templatePath)
+		if err != nil {
+			t.Errorf("ParseFile() error = %v", err)
+			return
+		}
+		for _, block := range cfnBlocks {
+			block.AddNewTags([]tags.ITag{
+				&tags.Tag{Key: "yor_trace", Value: "e2b2a9f4-1b41-4f5c-8d6e-7a8b9c0d1e2f"},
+				&tags.Tag{Key: "team", Value: "data"},
+			})
+		}
+		err = cfnParser.WriteFile(templatePath, cfnBlocks, templatePath)
+		if err != nil {
+			t.Errorf("WriteFile() error = %v", err)
+			return
+		}
+		cfnParser.Close()
+
+		actualTemplate, _ := os.ReadFile(templatePath)
+		assert.Equal(t, string(originalTemplate), string(actualTemplate))
+		expected, _ := os.ReadFile("../../../tests/cloudformation/resources/cdk/cdk-tags_expected.json")
+		actual, _ := os.ReadFile(tagsFilePath)
+		assert.Equal(t, string(expected), string(actual))
+	})
 }
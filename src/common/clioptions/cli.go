@@ -15,25 +15,34 @@ import (
 var allowedOutputTypes = []string{"cli", "json"}
 
 type TagOptions struct {
-	Directory         string
-	Tag               []string
-	SkipTags          []string
-	CustomTagging     []string
-	SkipDirs          []string
-	Output            string `validate:"output"`
-	OutputJSONFile    string
-	TagGroups         []string `validate:"tagGroupNames"`
-	ConfigFile        string   `validate:"config-file"`
-	SkipResourceTypes []string
-	SkipResources     []string
-	Parsers           []string
-	DryRun            bool
-	ValidateMode      bool
-	TagLocalModules   bool
-	TagPrefix         string
-	NoColor           bool
-	UseCodeOwners     bool
-	NonRecursive      bool
+	Directory            string
+	Tag                  []string
+	SkipTags             []string
+	ProtectedTags        []string
+	RenameTags           []string
+	RemoveTags           []string
+	CustomTagging        []string
+	SkipDirs             []string
+	Output               string `validate:"output"`
+	OutputJSONFile       string
+	TagGroups            []string `validate:"tagGroupNames"`
+	ConfigFile           string   `validate:"config-file"`
+	SkipResourceTypes    []string
+	SkipResources        []string
+	Parsers              []string
+	DryRun               bool
+	ValidateMode         bool
+	TagLocalModules      bool
+	InspectModules       bool
+	CdkOut               bool
+	ASGPropagateAtLaunch bool
+	UseDefaultTags       bool
+	ProviderSchemas      string
+	TerragruntTagsKey    string
+	TagPrefix            string
+	NoColor              bool
+	UseCodeOwners        bool
+	NonRecursive         bool
 }
 
 type ListTagsOptions struct {
@@ -47,6 +56,9 @@ func (o *TagOptions) Validate() {
 
 	o.Tag = utils.SplitStringByComma(o.Tag)
 	o.SkipTags = utils.SplitStringByComma(o.SkipTags)
+	o.ProtectedTags = utils.SplitStringByComma(o.ProtectedTags)
+	o.RenameTags = utils.SplitStringByComma(o.RenameTags)
+	o.RemoveTags = utils.SplitStringByComma(o.RemoveTags)
 	o.CustomTagging = utils.SplitStringByComma(o.CustomTagging)
 	o.SkipDirs = utils.SplitStringByComma(o.SkipDirs)
 	o.TagGroups = utils.SplitStringByComma(o.TagGroups)
@@ -10,3 +10,6 @@ var YmlFileType = FileType{Extension: ".yml", FileFormat: "yml"}
 var JSONFileType = FileType{Extension: ".json", FileFormat: "json"}
 var CFTFileType = FileType{Extension: ".template", FileFormat: "template"}
 var TfFileType = FileType{Extension: ".tf", FileFormat: "tf"}
+var TfJSONFileType = FileType{Extension: ".tf.json", FileFormat: "json"}
+var BicepFileType = FileType{Extension: ".bicep", FileFormat: "bicep"}
+var HclFileType = FileType{Extension: ".hcl", FileFormat: "hcl"}
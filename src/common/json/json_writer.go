@@ -31,7 +31,7 @@ func WriteJSONFile(readFilePath string, blocks []structure.IBlock, writeFilePath
 	for _, resourceBlock := range blocks {
 		if resourceBlock.IsBlockTaggable() {
 			tagsDiff := resourceBlock.CalculateTagsDiff()
-			if len(tagsDiff.Added) == 0 && len(tagsDiff.Updated) == 0 {
+			if tagsDiff.IsEmpty() {
 				// if resource was not changed during the run, continue
 				continue
 			}
@@ -82,8 +82,24 @@ func AddTagsToResourceStr(fullOriginStr string, resourceBlock structure.IBlock,
 		// extract the tags' brackets scope and get the origin str for them
 		tagBrackets := FindScopeInJSON(fullOriginStr, tagsAttributeName, fileBracketsPairs, &structure.Lines{Start: resourceBrackets.Open.Line, End: resourceBrackets.Close.Line})
 		tagsStr := fullOriginStr[tagBrackets.Open.CharIndex : tagBrackets.Close.CharIndex+1]
+		tagsStr = MigrateTagsStr(tagsStr, diff)
+		if structure.HasMapTags(resourceBlock) && strings.HasPrefix(tagsStr, "{") {
+			tagsStartRelativeToResource := tagBrackets.Open.CharIndex - resourceBrackets.Open.CharIndex
+			tagsEndRelativeToResource := tagBrackets.Close.CharIndex - resourceBrackets.Open.CharIndex
+			return resourceStr[:tagsStartRelativeToResource] + AddTagsToMapStr(tagsStr, diff) + resourceStr[tagsEndRelativeToResource+1:]
+		}
 		tagsLinesList := strings.Split(tagsStr, "\n")
 		UpdateExistingTags(tagsLinesList, diff.Updated)
+		tagsStartRelativeToResource := tagBrackets.Open.CharIndex - resourceBrackets.Open.CharIndex
+		tagsEndRelativeToResource := tagBrackets.Close.CharIndex - resourceBrackets.Open.CharIndex
+		if len(diff.Added) == 0 {
+			return resourceStr[:tagsStartRelativeToResource] + strings.Join(tagsLinesList, "\n") + resourceStr[tagsEndRelativeToResource+1:]
+		}
+		if !strings.Contains(tagsStr, "{") {
+			// all the existing tags were removed, so there is no tag to take the indentation from
+			addedTagsStr, _ := json.Marshal(diff.Added)
+			return resourceStr[:tagsStartRelativeToResource] + string(addedTagsStr) + resourceStr[tagsEndRelativeToResource+1:]
+		}
 
 		//	now find the indentation of the first tags entry by searching an indent between "[" and first "{". If there is a newline, restart the indent.
 		tagBlockIndent := findIndent(tagsStr, '{', 0) // find the indent of each tag block " { "
@@ -138,8 +154,6 @@ func AddTagsToResourceStr(fullOriginStr string, resourceBlock structure.IBlock,
 				tagsLinesList[len(tagsLinesList)-1]
 
 		}
-		tagsStartRelativeToResource := tagBrackets.Open.CharIndex - resourceBrackets.Open.CharIndex
-		tagsEndRelativeToResource := tagBrackets.Close.CharIndex - resourceBrackets.Open.CharIndex
 
 		// set the resource string with the updated and indented tags
 		resourceStr = resourceStr[:tagsStartRelativeToResource] + finalTagsStr + resourceStr[tagsEndRelativeToResource+1:]
@@ -182,6 +196,8 @@ func AddTagsToResourceStr(fullOriginStr string, resourceBlock structure.IBlock,
 			if i > 0 {
 				iterator[identifiersToAdd[i]] = make(map[string]interface{})
 				iterator = iterator[identifiersToAdd[i]].(map[string]interface{})
+			} else if structure.HasMapTags(resourceBlock) {
+				iterator[identifiersToAdd[i]] = mapTags(diff.Added)
 			} else {
 				iterator[identifiersToAdd[i]] = diff.Added
 			}
@@ -225,6 +241,199 @@ func AddTagsToResourceStr(fullOriginStr string, resourceBlock structure.IBlock,
 	return resourceStr
 }
 
+// mapTags marshals tags as a key-value map, keeping the order of the tags
+type mapTags []tags.ITag
+
+func (m mapTags) MarshalJSON() ([]byte, error) {
+	entries := make([]string, 0, len(m))
+	for _, tag := range m {
+		entries = append(entries, getMapEntryStr(tag))
+	}
+	return []byte("{" + strings.Join(entries, ",") + "}"), nil
+}
+
+func getMapEntryStr(tag tags.ITag) string {
+	keyStr, _ := json.Marshal(tag.GetKey())
+	valueStr, _ := json.Marshal(tag.GetValue())
+	return string(keyStr) + ": " + string(valueStr)
+}
+
+// jsonStringPattern matches a JSON string, including its escaped quotes
+const jsonStringPattern = `"(?:[^"\\]|\\.)*"`
+
+// AddTagsToMapStr updates and appends tags to a key-value map of tags, such as the Tags property of AWS SAM resources
+func AddTagsToMapStr(tagsStr string, diff *structure.TagDiff) string {
+	for _, tag := range diff.Updated {
+		keyStr, _ := json.Marshal(tag.Key)
+		valueStr, _ := json.Marshal(tag.NewValue)
+		tr := regexp.MustCompile(regexp.QuoteMeta(string(keyStr)) + `\s*:\s*` + jsonStringPattern)
+		tagsStr = tr.ReplaceAllLiteralString(tagsStr, string(keyStr)+": "+string(valueStr))
+	}
+	if len(diff.Added) == 0 {
+		return tagsStr
+	}
+
+	closeIndex := strings.LastIndex(tagsStr, "}")
+	hasEntries := strings.Contains(tagsStr, "\"")
+	entries := make([]string, 0, len(diff.Added))
+	for _, tag := range diff.Added {
+		entries = append(entries, getMapEntryStr(tag))
+	}
+	if !strings.Contains(tagsStr, "\n") {
+		// all the tags are in a single line
+		prefix := strings.TrimRight(tagsStr[:closeIndex], " ")
+		if hasEntries {
+			prefix += ", "
+		}
+		return prefix + strings.Join(entries, ", ") + tagsStr[closeIndex:]
+	}
+	closeIndent := findIndent(tagsStr, '}', strings.LastIndex(tagsStr, "\n"))
+	entryIndent := closeIndent + "  "
+	if hasEntries {
+		entryIndent = findIndent(tagsStr, '"', 0)
+	}
+	prefix := strings.TrimRight(tagsStr[:closeIndex], " \t\n")
+	if hasEntries {
+		prefix += ","
+	}
+	return prefix + "\n" + entryIndent + strings.Join(entries, ",\n"+entryIndent) + "\n" + closeIndent + tagsStr[closeIndex:]
+}
+
+// jsonElement is a top-level element of the string of a JSON object or array. Entries of objects have a key and a value,
+// the indices are of the string.
+type jsonElement struct {
+	Start      int
+	End        int
+	Key        string
+	ValueStart int
+}
+
+// getJSONElements returns the top-level elements of the string of a JSON object or array
+func getJSONElements(str string) []jsonElement {
+	var elements []jsonElement
+	isObject := strings.HasPrefix(str, "{")
+	i := 1
+	for i < len(str) {
+		if utils.IsCharWhitespace(str[i]) || str[i] == ',' {
+			i++
+			continue
+		}
+		if str[i] == '}' || str[i] == ']' {
+			break
+		}
+		element := jsonElement{Start: i, ValueStart: i}
+		if isObject {
+			keyEnd := findJSONValueEnd(str, i)
+			_ = json.Unmarshal([]byte(str[i:keyEnd+1]), &element.Key)
+			element.ValueStart = keyEnd + 1
+			for element.ValueStart < len(str) && (utils.IsCharWhitespace(str[element.ValueStart]) || str[element.ValueStart] == ':') {
+				element.ValueStart++
+			}
+		}
+		element.End = findJSONValueEnd(str, element.ValueStart)
+		elements = append(elements, element)
+		i = element.End + 1
+	}
+	return elements
+}
+
+// findJSONValueEnd returns the index of the last char of the JSON value which starts at the start index of the string
+func findJSONValueEnd(str string, start int) int {
+	depth := 0
+	for i := start; i < len(str); i++ {
+		switch str[i] {
+		case '"':
+			for i++; i < len(str) && str[i] != '"'; i++ {
+				if str[i] == '\\' {
+					i++
+				}
+			}
+			if depth == 0 {
+				return i
+			}
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+			if depth < 0 {
+				return i - 1
+			}
+		case ',':
+			if depth == 0 {
+				return i - 1
+			}
+		}
+	}
+	return len(str) - 1
+}
+
+// MigrateTagsStr removes and renames the tags of the diff in the string of the tags of a resource, which are a list of
+// Key/Value objects or a key-value map. Renamed tags keep their values as they are written.
+func MigrateTagsStr(tagsStr string, diff *structure.TagDiff) string {
+	if len(diff.Removed) == 0 && len(diff.Renamed) == 0 {
+		return tagsStr
+	}
+	removedKeys := make(map[string]bool)
+	for _, tag := range diff.Removed {
+		removedKeys[tag.GetKey()] = true
+	}
+	renamedKeys := make(map[string]string)
+	for _, rename := range diff.Renamed {
+		renamedKeys[rename.PrevKey] = rename.NewKey
+	}
+	elements := getJSONElements(tagsStr)
+	// the elements are edited from the last one, so the indices of the previous elements do not change
+	for i := len(elements) - 1; i >= 0; i-- {
+		element := elements[i]
+		keyStart, keyEnd, key := element.Start, element.ValueStart, element.Key
+		if strings.HasPrefix(tagsStr, "[") {
+			keyStart, keyEnd, key = -1, -1, ""
+			elementStr := tagsStr[element.Start : element.End+1]
+			for _, entry := range getJSONElements(elementStr) {
+				if entry.Key == "Key" {
+					keyStart, keyEnd = element.Start+entry.ValueStart, element.Start+entry.End+1
+					_ = json.Unmarshal([]byte(tagsStr[keyStart:keyEnd]), &key)
+				}
+			}
+		}
+		if keyStart < 0 {
+			continue
+		}
+		if removedKeys[key] {
+			tagsStr = removeJSONElement(tagsStr, element)
+			continue
+		}
+		if newKey, ok := renamedKeys[key]; ok {
+			newKeyStr, _ := json.Marshal(newKey)
+			if strings.HasPrefix(tagsStr, "{") {
+				keyEnd = findJSONValueEnd(tagsStr, keyStart) + 1
+			}
+			tagsStr = tagsStr[:keyStart] + string(newKeyStr) + tagsStr[keyEnd:]
+		}
+	}
+	return tagsStr
+}
+
+// removeJSONElement removes the element from the string of its JSON object or array, with the comma which separates it
+// from the previous element, or else from the next one
+func removeJSONElement(str string, element jsonElement) string {
+	start, end := element.Start, element.End+1
+	before := strings.TrimRight(str[:start], " \t\n\r")
+	after := strings.TrimLeft(str[end:], " \t\n\r")
+	switch {
+	case strings.HasSuffix(before, ","):
+		start = len(before) - 1
+	case strings.HasPrefix(after, ","):
+		end = len(str) - len(strings.TrimLeft(after[1:], " \t\n\r"))
+	default:
+		start = len(before)
+	}
+	return str[:start] + str[end:]
+}
+
 func UpdateExistingTags(tagsLinesList []string, diff []*tags.TagDiff) {
 	currentValueLine := -1
 	valueToSet := ""
@@ -470,3 +679,40 @@ func findJSONKeyIndex(str string, key string) int {
 
 	return indexPair[0]
 }
+
+// GetMapPropertyAddition returns the offset and the text of a new property holding the given tags as a key-value map,
+// added as the last property of the object in the given scope
+func GetMapPropertyAddition(src string, objectScope BracketPair, propertyName string, added []tags.ITag) (int, string) {
+	objectStr := src[objectScope.Open.CharIndex : objectScope.Close.CharIndex+1]
+	insertAt := objectScope.Open.CharIndex + len(strings.TrimRight(objectStr[:len(objectStr)-1], " \t\r\n"))
+	entries := make([]string, 0, len(added))
+	for _, tag := range added {
+		entries = append(entries, MarshalJSONString(tag.GetKey())+": "+MarshalJSONString(tag.GetValue()))
+	}
+	if !strings.Contains(objectStr, "\n") {
+		return insertAt, fmt.Sprintf(`, "%s": {%s}`, propertyName, strings.Join(entries, ", "))
+	}
+	closeIndent := getLineIndent(src, objectScope.Close.CharIndex)
+	propertyIndent := getLineIndent(src, objectScope.Open.CharIndex+strings.Index(objectStr, `"`))
+	indent := "  "
+	if len(propertyIndent) > len(closeIndent) {
+		indent = propertyIndent[len(closeIndent):]
+	}
+	text := fmt.Sprintf(",\n%s\"%s\": {\n%s\n%s}", propertyIndent, propertyName, propertyIndent+indent+strings.Join(entries, ",\n"+propertyIndent+indent), propertyIndent)
+	return insertAt, text
+}
+
+// MarshalJSONString returns the given value as a JSON string, without escaping HTML characters
+func MarshalJSONString(value string) string {
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+func getLineIndent(src string, offset int) string {
+	lineStart := strings.LastIndexByte(src[:offset], '\n') + 1
+	line := src[lineStart:]
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
@@ -75,6 +75,13 @@ func TestTagWriting(t *testing.T) {
 		assert.Equal(t, `    "Value": "ReverseCorrect",`, tagLinesList[2])
 		assert.Equal(t, `    "Value": "DirectCorrect"`, tagLinesList[7])
 	})
+
+	t.Run("Test AddTagsToMapStr with escaped quotes", func(t *testing.T) {
+		tagsStr := AddTagsToMapStr(`{"owner": "say \"hi\"", "env": "dev"}`, &structure.TagDiff{
+			Updated: []*tags.TagDiff{{Key: "owner", PrevValue: `say "hi"`, NewValue: "platform"}},
+		})
+		assert.Equal(t, `{"owner": "platform", "env": "dev"}`, tagsStr)
+	})
 }
 
 func TestGetBracketsPairs(t *testing.T) {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 
 	"github.com/bridgecrewio/yor/src/common"
 	"github.com/bridgecrewio/yor/src/common/logger"
@@ -17,9 +18,10 @@ type ReportService struct {
 }
 
 type ReportSummary struct {
-	Scanned          int `json:"scanned"`
-	NewResources     int `json:"newResources"`
-	UpdatedResources int `json:"updatedResources"`
+	Scanned           int `json:"scanned"`
+	NewResources      int `json:"newResources"`
+	UpdatedResources  int `json:"updatedResources"`
+	MigratedResources int `json:"migratedResources,omitempty"`
 }
 
 type TagRecord struct {
@@ -31,10 +33,81 @@ type TagRecord struct {
 	YorTraceID   string `json:"yorTraceId"`
 }
 
+// UntaggableModuleRecord is a module call which could not be tagged, and the reason
+type UntaggableModuleRecord struct {
+	File     string `json:"file"`
+	ModuleID string `json:"moduleId"`
+	Source   string `json:"source"`
+	Reason   string `json:"reason"`
+}
+
+// TagViolationRecord is a tag which violated the tag constraints of the cloud provider of its resource, and whether it
+// was sanitized or dropped
+type TagViolationRecord struct {
+	File       string `json:"file"`
+	ResourceID string `json:"resourceId"`
+	Provider   string `json:"provider"`
+	TagKey     string `json:"key"`
+	TagValue   string `json:"value"`
+	Violation  string `json:"violation"`
+	Resolution string `json:"resolution"`
+}
+
+// TagProposalRecord is a value a tag group proposed for a tag
+type TagProposalRecord struct {
+	Group string `json:"group"`
+	Value string `json:"value"`
+}
+
+// TagConflictRecord is a tag which the tag groups proposed different values for, and how the conflict was resolved by
+// the conflict policy of the tag
+type TagConflictRecord struct {
+	File          string              `json:"file"`
+	ResourceID    string              `json:"resourceId"`
+	TagKey        string              `json:"key"`
+	ExistingValue string              `json:"existingValue,omitempty"`
+	Proposals     []TagProposalRecord `json:"proposals"`
+	Policy        string              `json:"policy,omitempty"`
+	Resolution    string              `json:"resolution"`
+	Failed        bool                `json:"failed"`
+}
+
+// BlockedTagUpdateRecord is an update of a protected tag of a resource which was not applied
+type BlockedTagUpdateRecord struct {
+	File          string `json:"file"`
+	ResourceID    string `json:"resourceId"`
+	TagKey        string `json:"key"`
+	ExistingValue string `json:"existingValue"`
+	BlockedValue  string `json:"blockedValue"`
+}
+
+// RemovedTagRecord is an existing tag of a resource which was removed by the tag migrations
+type RemovedTagRecord struct {
+	File       string `json:"file"`
+	ResourceID string `json:"resourceId"`
+	TagKey     string `json:"key"`
+	Value      string `json:"value"`
+}
+
+// RenamedTagRecord is an existing tag of a resource whose key was renamed by the tag migrations, keeping its value
+type RenamedTagRecord struct {
+	File       string `json:"file"`
+	ResourceID string `json:"resourceId"`
+	OldKey     string `json:"oldKey"`
+	NewKey     string `json:"newKey"`
+	Value      string `json:"value"`
+}
+
 type Report struct {
-	Summary             ReportSummary `json:"summary"`
-	NewResourceTags     []TagRecord   `json:"newResourceTags"`
-	UpdatedResourceTags []TagRecord   `json:"updatedResourceTags"`
+	Summary             ReportSummary            `json:"summary"`
+	NewResourceTags     []TagRecord              `json:"newResourceTags"`
+	UpdatedResourceTags []TagRecord              `json:"updatedResourceTags"`
+	UntaggableModules   []UntaggableModuleRecord `json:"untaggableModules,omitempty"`
+	TagViolations       []TagViolationRecord     `json:"tagViolations,omitempty"`
+	TagConflicts        []TagConflictRecord      `json:"tagConflicts,omitempty"`
+	BlockedTagUpdates   []BlockedTagUpdateRecord `json:"blockedTagUpdates,omitempty"`
+	RemovedResourceTags []RemovedTagRecord       `json:"removedResourceTags,omitempty"`
+	RenamedResourceTags []RenamedTagRecord       `json:"renamedResourceTags,omitempty"`
 }
 
 func (r *Report) AsJSONBytes() ([]byte, error) {
@@ -55,8 +128,19 @@ func (r *ReportService) Changed() bool {
 	changesAccumulator := TagChangeAccumulatorInstance
 	newCount := len(changesAccumulator.NewBlockTraces)
 	updatedCount := len(changesAccumulator.UpdatedBlockTraces)
+	migratedCount := len(changesAccumulator.MigratedBlocks)
+
+	return newCount > 0 || updatedCount > 0 || migratedCount > 0
+}
 
-	return newCount > 0 || updatedCount > 0
+// HasFailedTagConflicts returns true if the tag groups proposed different values of a tag whose conflict policy is fail
+func (r *ReportService) HasFailedTagConflicts() bool {
+	for _, conflict := range TagChangeAccumulatorInstance.TagConflicts {
+		if conflict.Failed {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *ReportService) GetReport() *Report {
@@ -66,13 +150,15 @@ func (r *ReportService) GetReport() *Report {
 func (r *ReportService) CreateReport() *Report {
 	changesAccumulator := TagChangeAccumulatorInstance
 	r.report.Summary = ReportSummary{
-		Scanned:          len(changesAccumulator.ScannedBlocks),
-		NewResources:     len(changesAccumulator.NewBlockTraces),
-		UpdatedResources: len(changesAccumulator.UpdatedBlockTraces),
+		Scanned:           len(changesAccumulator.ScannedBlocks),
+		NewResources:      len(changesAccumulator.NewBlockTraces),
+		UpdatedResources:  len(changesAccumulator.UpdatedBlockTraces),
+		MigratedResources: len(changesAccumulator.MigratedBlocks),
 	}
 	r.report.NewResourceTags = []TagRecord{}
 	for _, block := range changesAccumulator.NewBlockTraces {
-		for _, tag := range block.GetNewTags() {
+		// new tags of renamed keys may equal the renamed tags
+		for _, tag := range block.CalculateTagsDiff().Added {
 			r.report.NewResourceTags = append(r.report.NewResourceTags, TagRecord{
 				File:         block.GetFilePath(),
 				ResourceID:   block.GetResourceID(),
@@ -115,6 +201,68 @@ func (r *ReportService) CreateReport() *Report {
 			})
 		}
 	}
+	r.report.UntaggableModules = []UntaggableModuleRecord{}
+	for _, module := range changesAccumulator.UntaggableModules {
+		r.report.UntaggableModules = append(r.report.UntaggableModules, UntaggableModuleRecord{
+			File:     module.GetFilePath(),
+			ModuleID: module.GetResourceID(),
+			Source:   module.GetModuleSource(),
+			Reason:   module.GetUntaggableReason(),
+		})
+	}
+	sort.SliceStable(r.report.UntaggableModules, func(i, j int) bool {
+		if r.report.UntaggableModules[i].File != r.report.UntaggableModules[j].File {
+			return r.report.UntaggableModules[i].File < r.report.UntaggableModules[j].File
+		}
+		return r.report.UntaggableModules[i].ModuleID < r.report.UntaggableModules[j].ModuleID
+	})
+	r.report.TagViolations = append([]TagViolationRecord{}, changesAccumulator.TagViolations...)
+	sort.SliceStable(r.report.TagViolations, func(i, j int) bool {
+		if r.report.TagViolations[i].File != r.report.TagViolations[j].File {
+			return r.report.TagViolations[i].File < r.report.TagViolations[j].File
+		}
+		return r.report.TagViolations[i].ResourceID < r.report.TagViolations[j].ResourceID
+	})
+	r.report.TagConflicts = append([]TagConflictRecord{}, changesAccumulator.TagConflicts...)
+	sort.SliceStable(r.report.TagConflicts, func(i, j int) bool {
+		if r.report.TagConflicts[i].File != r.report.TagConflicts[j].File {
+			return r.report.TagConflicts[i].File < r.report.TagConflicts[j].File
+		}
+		if r.report.TagConflicts[i].ResourceID != r.report.TagConflicts[j].ResourceID {
+			return r.report.TagConflicts[i].ResourceID < r.report.TagConflicts[j].ResourceID
+		}
+		return r.report.TagConflicts[i].TagKey < r.report.TagConflicts[j].TagKey
+	})
+	r.report.BlockedTagUpdates = append([]BlockedTagUpdateRecord{}, changesAccumulator.BlockedTagUpdates...)
+	sort.SliceStable(r.report.BlockedTagUpdates, func(i, j int) bool {
+		if r.report.BlockedTagUpdates[i].File != r.report.BlockedTagUpdates[j].File {
+			return r.report.BlockedTagUpdates[i].File < r.report.BlockedTagUpdates[j].File
+		}
+		if r.report.BlockedTagUpdates[i].ResourceID != r.report.BlockedTagUpdates[j].ResourceID {
+			return r.report.BlockedTagUpdates[i].ResourceID < r.report.BlockedTagUpdates[j].ResourceID
+		}
+		return r.report.BlockedTagUpdates[i].TagKey < r.report.BlockedTagUpdates[j].TagKey
+	})
+	r.report.RemovedResourceTags = append([]RemovedTagRecord{}, changesAccumulator.RemovedTags...)
+	sort.SliceStable(r.report.RemovedResourceTags, func(i, j int) bool {
+		if r.report.RemovedResourceTags[i].File != r.report.RemovedResourceTags[j].File {
+			return r.report.RemovedResourceTags[i].File < r.report.RemovedResourceTags[j].File
+		}
+		if r.report.RemovedResourceTags[i].ResourceID != r.report.RemovedResourceTags[j].ResourceID {
+			return r.report.RemovedResourceTags[i].ResourceID < r.report.RemovedResourceTags[j].ResourceID
+		}
+		return r.report.RemovedResourceTags[i].TagKey < r.report.RemovedResourceTags[j].TagKey
+	})
+	r.report.RenamedResourceTags = append([]RenamedTagRecord{}, changesAccumulator.RenamedTags...)
+	sort.SliceStable(r.report.RenamedResourceTags, func(i, j int) bool {
+		if r.report.RenamedResourceTags[i].File != r.report.RenamedResourceTags[j].File {
+			return r.report.RenamedResourceTags[i].File < r.report.RenamedResourceTags[j].File
+		}
+		if r.report.RenamedResourceTags[i].ResourceID != r.report.RenamedResourceTags[j].ResourceID {
+			return r.report.RenamedResourceTags[i].ResourceID < r.report.RenamedResourceTags[j].ResourceID
+		}
+		return r.report.RenamedResourceTags[i].OldKey < r.report.RenamedResourceTags[j].OldKey
+	})
 	return &r.report
 }
 
@@ -125,12 +273,21 @@ func (r *ReportService) CreateReport() *Report {
 // Updated Resources: <int>
 // <New Resources Table> as generated by printNewResourcesToStdout, if not empty
 // <Updated Resources Table> as generated by printUpdatedResourcesToStdout, if not empty
+// <Untaggable Modules Table> as generated by printUntaggableModulesToStdout, if not empty
+// <Tag Violations Table> as generated by printTagViolationsToStdout, if not empty
+// <Tag Conflicts Table> as generated by printTagConflictsToStdout, if not empty
+// <Blocked Updates Table> as generated by printBlockedTagUpdatesToStdout, if not empty
+// <Removed Tags Table> as generated by printRemovedTagsToStdout, if not empty
+// <Renamed Tags Table> as generated by printRenamedTagsToStdout, if not empty
 func (r *ReportService) PrintToStdout(colors *common.ColorStruct) {
 	PrintBanner(colors)
 	fmt.Println(colors.Reset, "Yor Findings Summary")
 	fmt.Println(colors.Reset, "Scanned Resources:\t", colors.Blue, r.report.Summary.Scanned)
 	fmt.Println(colors.Reset, "New Resources Traced: \t", colors.Yellow, r.report.Summary.NewResources)
 	fmt.Println(colors.Reset, "Updated Resources:\t", colors.Green, r.report.Summary.UpdatedResources)
+	if r.report.Summary.MigratedResources > 0 {
+		fmt.Println(colors.Reset, "Migrated Resources:\t", colors.Purple, r.report.Summary.MigratedResources)
+	}
 	fmt.Println()
 	if r.report.Summary.NewResources > 0 {
 		r.printNewResourcesToStdout(colors)
@@ -139,6 +296,30 @@ func (r *ReportService) PrintToStdout(colors *common.ColorStruct) {
 	if r.report.Summary.UpdatedResources > 0 {
 		r.printUpdatedResourcesToStdout(colors)
 	}
+	if len(r.report.UntaggableModules) > 0 {
+		fmt.Println()
+		r.printUntaggableModulesToStdout(colors)
+	}
+	if len(r.report.TagViolations) > 0 {
+		fmt.Println()
+		r.printTagViolationsToStdout(colors)
+	}
+	if len(r.report.TagConflicts) > 0 {
+		fmt.Println()
+		r.printTagConflictsToStdout(colors)
+	}
+	if len(r.report.BlockedTagUpdates) > 0 {
+		fmt.Println()
+		r.printBlockedTagUpdatesToStdout(colors)
+	}
+	if len(r.report.RemovedResourceTags) > 0 {
+		fmt.Println()
+		r.printRemovedTagsToStdout(colors)
+	}
+	if len(r.report.RenamedResourceTags) > 0 {
+		fmt.Println()
+		r.printRenamedTagsToStdout(colors)
+	}
 }
 
 func PrintBanner(colors *common.ColorStruct) {
@@ -192,6 +373,88 @@ func (r *ReportService) printNewResourcesToStdout(colors *common.ColorStruct) {
 	table.Render()
 }
 
+func (r *ReportService) printUntaggableModulesToStdout(colors *common.ColorStruct) {
+	fmt.Print(colors.Purple, fmt.Sprintf("Untaggable Modules (%v):\n", len(r.report.UntaggableModules)), colors.Reset)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"File", "Module", "Source", "Reason"})
+	table.SetRowLine(true)
+	table.SetRowSeparator("-")
+	for _, mr := range r.report.UntaggableModules {
+		table.Append([]string{mr.File, mr.ModuleID, mr.Source, mr.Reason})
+	}
+	table.SetAutoMergeCellsByColumnIndex([]int{0})
+	table.Render()
+}
+
+func (r *ReportService) printTagViolationsToStdout(colors *common.ColorStruct) {
+	fmt.Print(colors.Yellow, fmt.Sprintf("Tag Violations (%v):\n", len(r.report.TagViolations)), colors.Reset)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"File", "Resource", "Provider", "Tag Key", "Tag Value", "Violation", "Resolution"})
+	table.SetRowLine(true)
+	table.SetRowSeparator("-")
+	for _, vr := range r.report.TagViolations {
+		table.Append([]string{vr.File, vr.ResourceID, vr.Provider, vr.TagKey, vr.TagValue, vr.Violation, vr.Resolution})
+	}
+	table.SetAutoMergeCellsByColumnIndex([]int{0, 1})
+	table.Render()
+}
+
+func (r *ReportService) printTagConflictsToStdout(colors *common.ColorStruct) {
+	fmt.Print(colors.Yellow, fmt.Sprintf("Tag Conflicts (%v):\n", len(r.report.TagConflicts)), colors.Reset)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"File", "Resource", "Tag Key", "Existing Value", "Proposed Values", "Policy", "Resolution"})
+	table.SetRowLine(true)
+	table.SetRowSeparator("-")
+	for _, cr := range r.report.TagConflicts {
+		var proposals []string
+		for _, proposal := range cr.Proposals {
+			proposals = append(proposals, fmt.Sprintf("%v: %v", proposal.Group, proposal.Value))
+		}
+		table.Append([]string{cr.File, cr.ResourceID, cr.TagKey, cr.ExistingValue, strings.Join(proposals, "\n"), cr.Policy, cr.Resolution})
+	}
+	table.SetAutoMergeCellsByColumnIndex([]int{0, 1})
+	table.Render()
+}
+
+func (r *ReportService) printBlockedTagUpdatesToStdout(colors *common.ColorStruct) {
+	fmt.Print(colors.Yellow, fmt.Sprintf("Blocked Updates of Protected Tags (%v):\n", len(r.report.BlockedTagUpdates)), colors.Reset)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"File", "Resource", "Tag Key", "Existing Value", "Blocked Value"})
+	table.SetRowLine(true)
+	table.SetRowSeparator("-")
+	for _, br := range r.report.BlockedTagUpdates {
+		table.Append([]string{br.File, br.ResourceID, br.TagKey, br.ExistingValue, br.BlockedValue})
+	}
+	table.SetAutoMergeCellsByColumnIndex([]int{0, 1})
+	table.Render()
+}
+
+func (r *ReportService) printRemovedTagsToStdout(colors *common.ColorStruct) {
+	fmt.Print(colors.Purple, fmt.Sprintf("Removed Tags (%v):\n", len(r.report.RemovedResourceTags)), colors.Reset)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"File", "Resource", "Tag Key", "Tag Value"})
+	table.SetRowLine(true)
+	table.SetRowSeparator("-")
+	for _, rr := range r.report.RemovedResourceTags {
+		table.Append([]string{rr.File, rr.ResourceID, rr.TagKey, rr.Value})
+	}
+	table.SetAutoMergeCellsByColumnIndex([]int{0, 1})
+	table.Render()
+}
+
+func (r *ReportService) printRenamedTagsToStdout(colors *common.ColorStruct) {
+	fmt.Print(colors.Purple, fmt.Sprintf("Renamed Tags (%v):\n", len(r.report.RenamedResourceTags)), colors.Reset)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"File", "Resource", "Old Key", "New Key", "Tag Value"})
+	table.SetRowLine(true)
+	table.SetRowSeparator("-")
+	for _, rr := range r.report.RenamedResourceTags {
+		table.Append([]string{rr.File, rr.ResourceID, rr.OldKey, rr.NewKey, rr.Value})
+	}
+	table.SetAutoMergeCellsByColumnIndex([]int{0, 1})
+	table.Render()
+}
+
 func (r *ReportService) PrintJSONToFile(file string) {
 	jr, err := r.report.AsJSONBytes()
 	if err != nil {
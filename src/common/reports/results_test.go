@@ -189,6 +189,142 @@ func TestResultsGeneration(t *testing.T) {
 	})
 }
 
+func TestUntaggableModulesReport(t *testing.T) {
+	defer func(accumulator *TagChangeAccumulator) {
+		TagChangeAccumulatorInstance = accumulator
+	}(TagChangeAccumulatorInstance)
+	TagChangeAccumulatorInstance = &TagChangeAccumulator{}
+	for _, module := range []struct {
+		name   string
+		source string
+		reason string
+	}{
+		{"vpc", "terraform-aws-modules/vpc/aws", ""},
+		{"release", "terraform-module/release/helm", tfStructure.ModuleUnsupportedProviderReason},
+		{"bucket", "git::https://example.com/terraform-aws-bucket.git", tfStructure.ModuleNotDownloadedReason},
+	} {
+		TagChangeAccumulatorInstance.AccumulateChanges(&tfStructure.TerraformBlock{
+			Block:            structure.Block{FilePath: "/main.tf", IsTaggable: module.reason == ""},
+			HclSyntaxBlock:   &hclsyntax.Block{Type: "module", Labels: []string{module.name}},
+			ModuleSource:     module.source,
+			UntaggableReason: module.reason,
+		})
+	}
+
+	reportService := &ReportService{}
+	report := reportService.CreateReport()
+	assert.Equal(t, []UntaggableModuleRecord{
+		{File: "/main.tf", ModuleID: "bucket", Source: "git::https://example.com/terraform-aws-bucket.git", Reason: tfStructure.ModuleNotDownloadedReason},
+		{File: "/main.tf", ModuleID: "release", Source: "terraform-module/release/helm", Reason: tfStructure.ModuleUnsupportedProviderReason},
+	}, report.UntaggableModules)
+
+	output := utils.CaptureOutput(func() {
+		reportService.PrintToStdout(common.NoColorCheck(true))
+	})
+	assert.Contains(t, output, "Untaggable Modules (2):")
+	matched, _ := regexp.Match("[|\\s]+FILE[|\\s]+MODULE[|\\s]+SOURCE[|\\s]+REASON[|\\s]+", []byte(output))
+	assert.True(t, matched)
+}
+
+func TestTagViolationsReport(t *testing.T) {
+	defer func(accumulator *TagChangeAccumulator) {
+		TagChangeAccumulatorInstance = accumulator
+	}(TagChangeAccumulatorInstance)
+	TagChangeAccumulatorInstance = &TagChangeAccumulator{}
+	block := &tfStructure.TerraformBlock{
+		Block: structure.Block{
+			FilePath:   "/main.tf",
+			IsTaggable: true,
+			NewTags:    []tags.ITag{&tags.Tag{Key: "owner", Value: "_platform_"}},
+		},
+		HclSyntaxBlock: &hclsyntax.Block{Type: "resource", Labels: []string{"aws_s3_bucket", "data"}},
+	}
+	block.AddTagViolation(structure.TagViolation{Provider: "aws", Key: "owner", Value: "<platform>", Violation: "value has invalid characters", Resolution: "sanitized to owner=_platform_"})
+	block.AddTagViolation(structure.TagViolation{Provider: "aws", Key: "aws:team", Value: "data", Violation: "key prefix aws: is reserved", Resolution: "dropped"})
+	TagChangeAccumulatorInstance.AccumulateChanges(block)
+
+	reportService := &ReportService{}
+	report := reportService.CreateReport()
+	assert.Equal(t, []TagViolationRecord{
+		{File: "/main.tf", ResourceID: "aws_s3_bucket.data", Provider: "aws", TagKey: "owner", TagValue: "<platform>", Violation: "value has invalid characters", Resolution: "sanitized to owner=_platform_"},
+		{File: "/main.tf", ResourceID: "aws_s3_bucket.data", Provider: "aws", TagKey: "aws:team", TagValue: "data", Violation: "key prefix aws: is reserved", Resolution: "dropped"},
+	}, report.TagViolations)
+
+	output := utils.CaptureOutput(func() {
+		reportService.PrintToStdout(common.NoColorCheck(true))
+	})
+	assert.Contains(t, output, "Tag Violations (2):")
+	matched, _ := regexp.Match("[|\\s]+FILE[|\\s]+RESOURCE[|\\s]+PROVIDER[|\\s]+TAG KEY[|\\s]+TAG VALUE[|\\s]+VIOLATION[|\\s]+RESOLUTION[|\\s]+", []byte(output))
+	assert.True(t, matched)
+}
+
+func TestTagConflictsReport(t *testing.T) {
+	defer func(accumulator *TagChangeAccumulator) {
+		TagChangeAccumulatorInstance = accumulator
+	}(TagChangeAccumulatorInstance)
+	TagChangeAccumulatorInstance = &TagChangeAccumulator{}
+	simpleEnv := &tags.Tag{Key: "env", Value: "prod"}
+	externalEnv := &tags.Tag{Key: "env", Value: "staging"}
+	block := &tfStructure.TerraformBlock{
+		Block: structure.Block{
+			FilePath:    "/main.tf",
+			IsTaggable:  true,
+			ExitingTags: []tags.ITag{&tags.Tag{Key: "env", Value: "dev"}},
+			NewTags:     []tags.ITag{simpleEnv, externalEnv},
+		},
+		HclSyntaxBlock: &hclsyntax.Block{Type: "resource", Labels: []string{"aws_s3_bucket", "data"}},
+	}
+	_ = block.ResolveTagConflicts([]structure.TagProposal{{Group: "simple", Tag: simpleEnv}, {Group: "external", Tag: externalEnv}})
+	TagChangeAccumulatorInstance.AccumulateChanges(block)
+
+	reportService := &ReportService{}
+	report := reportService.CreateReport()
+	assert.Equal(t, []TagConflictRecord{
+		{File: "/main.tf", ResourceID: "aws_s3_bucket.data", TagKey: "env", ExistingValue: "dev", Proposals: []TagProposalRecord{{Group: "simple", Value: "prod"}, {Group: "external", Value: "staging"}}, Resolution: "value of external"},
+	}, report.TagConflicts)
+	assert.False(t, reportService.HasFailedTagConflicts())
+
+	output := utils.CaptureOutput(func() {
+		reportService.PrintToStdout(common.NoColorCheck(true))
+	})
+	assert.Contains(t, output, "Tag Conflicts (1):")
+	matched, _ := regexp.Match("[|\\s]+FILE[|\\s]+RESOURCE[|\\s]+TAG KEY[|\\s]+EXISTING VALUE[|\\s]+PROPOSED VALUES[|\\s]+POLICY[|\\s]+RESOLUTION[|\\s]+", []byte(output))
+	assert.True(t, matched)
+	assert.Contains(t, output, "external: staging")
+}
+
+func TestBlockedTagUpdatesReport(t *testing.T) {
+	defer func(accumulator *TagChangeAccumulator, patterns []string) {
+		TagChangeAccumulatorInstance = accumulator
+		structure.ProtectedTagPatterns = patterns
+	}(TagChangeAccumulatorInstance, structure.ProtectedTagPatterns)
+	TagChangeAccumulatorInstance = &TagChangeAccumulator{}
+	structure.ProtectedTagPatterns = []string{"cost_center"}
+	block := &tfStructure.TerraformBlock{
+		Block: structure.Block{
+			FilePath:    "/main.tf",
+			IsTaggable:  true,
+			ExitingTags: []tags.ITag{&tags.Tag{Key: "cost_center", Value: "1234"}},
+		},
+		HclSyntaxBlock: &hclsyntax.Block{Type: "resource", Labels: []string{"aws_s3_bucket", "data"}},
+	}
+	block.AddNewTags([]tags.ITag{&tags.Tag{Key: "cost_center", Value: "5678"}})
+	TagChangeAccumulatorInstance.AccumulateChanges(block)
+
+	reportService := &ReportService{}
+	report := reportService.CreateReport()
+	assert.Equal(t, []BlockedTagUpdateRecord{
+		{File: "/main.tf", ResourceID: "aws_s3_bucket.data", TagKey: "cost_center", ExistingValue: "1234", BlockedValue: "5678"},
+	}, report.BlockedTagUpdates)
+
+	output := utils.CaptureOutput(func() {
+		reportService.PrintToStdout(common.NoColorCheck(true))
+	})
+	assert.Contains(t, output, "Blocked Updates of Protected Tags (1):")
+	matched, _ := regexp.Match("[|\\s]+FILE[|\\s]+RESOURCE[|\\s]+TAG KEY[|\\s]+EXISTING VALUE[|\\s]+BLOCKED VALUE[|\\s]+", []byte(output))
+	assert.True(t, matched)
+}
+
 func setupAccumulator() *TagChangeAccumulator {
 	accumulator := TagChangeAccumulatorInstance
 	accumulator.AccumulateChanges(&tfStructure.TerraformBlock{
@@ -10,6 +10,21 @@ type TagChangeAccumulator struct {
 	ScannedBlocks      []structure.IBlock
 	NewBlockTraces     []structure.IBlock
 	UpdatedBlockTraces []structure.IBlock
+	UntaggableModules  []IUntaggableModule
+	TagViolations      []TagViolationRecord
+	TagConflicts       []TagConflictRecord
+	BlockedTagUpdates  []BlockedTagUpdateRecord
+	// MigratedBlocks are the blocks whose existing tags were removed or renamed
+	MigratedBlocks []structure.IBlock
+	RemovedTags    []RemovedTagRecord
+	RenamedTags    []RenamedTagRecord
+}
+
+// IUntaggableModule is a module call block which reports why it could not be tagged
+type IUntaggableModule interface {
+	structure.IBlock
+	GetModuleSource() string
+	GetUntaggableReason() string
 }
 
 var TagChangeAccumulatorInstance *TagChangeAccumulator
@@ -26,7 +41,71 @@ func (a *TagChangeAccumulator) AccumulateChanges(block structure.IBlock) {
 	accumulatorLock.Lock()
 	defer accumulatorLock.Unlock()
 	a.ScannedBlocks = append(a.ScannedBlocks, block)
+	if module, ok := block.(IUntaggableModule); ok && module.GetUntaggableReason() != "" {
+		a.UntaggableModules = append(a.UntaggableModules, module)
+	}
+	if violationsBlock, ok := block.(structure.ITagViolationsBlock); ok {
+		for _, violation := range violationsBlock.GetTagViolations() {
+			a.TagViolations = append(a.TagViolations, TagViolationRecord{
+				File:       block.GetFilePath(),
+				ResourceID: block.GetResourceID(),
+				Provider:   violation.Provider,
+				TagKey:     violation.Key,
+				TagValue:   violation.Value,
+				Violation:  violation.Violation,
+				Resolution: violation.Resolution,
+			})
+		}
+	}
+	if conflictsBlock, ok := block.(structure.ITagConflictsBlock); ok {
+		for _, conflict := range conflictsBlock.GetTagConflicts() {
+			record := TagConflictRecord{
+				File:          block.GetFilePath(),
+				ResourceID:    block.GetResourceID(),
+				TagKey:        conflict.Key,
+				ExistingValue: conflict.ExistingValue,
+				Policy:        conflict.Policy,
+				Resolution:    conflict.Resolution,
+				Failed:        conflict.Failed,
+			}
+			for _, proposal := range conflict.Proposals {
+				record.Proposals = append(record.Proposals, TagProposalRecord{Group: proposal.Group, Value: proposal.Tag.GetValue()})
+			}
+			a.TagConflicts = append(a.TagConflicts, record)
+		}
+	}
+	if protectedTagsBlock, ok := block.(structure.IProtectedTagsBlock); ok {
+		for _, blockedUpdate := range protectedTagsBlock.GetBlockedTagUpdates() {
+			a.BlockedTagUpdates = append(a.BlockedTagUpdates, BlockedTagUpdateRecord{
+				File:          block.GetFilePath(),
+				ResourceID:    block.GetResourceID(),
+				TagKey:        blockedUpdate.Key,
+				ExistingValue: blockedUpdate.PrevValue,
+				BlockedValue:  blockedUpdate.NewValue,
+			})
+		}
+	}
 	diff := block.CalculateTagsDiff()
+	if len(diff.Removed) > 0 || len(diff.Renamed) > 0 {
+		a.MigratedBlocks = append(a.MigratedBlocks, block)
+	}
+	for _, tag := range diff.Removed {
+		a.RemovedTags = append(a.RemovedTags, RemovedTagRecord{
+			File:       block.GetFilePath(),
+			ResourceID: block.GetResourceID(),
+			TagKey:     tag.GetKey(),
+			Value:      tag.GetValue(),
+		})
+	}
+	for _, rename := range diff.Renamed {
+		a.RenamedTags = append(a.RenamedTags, RenamedTagRecord{
+			File:       block.GetFilePath(),
+			ResourceID: block.GetResourceID(),
+			OldKey:     rename.PrevKey,
+			NewKey:     rename.NewKey,
+			Value:      rename.Value,
+		})
+	}
 	// If only tags are new, add to newly traced. If some updates - add to updated. Otherwise will be added to
 	// ScannedBlocks.
 	if len(diff.Updated) == 0 && len(diff.Added) > 0 {
@@ -10,19 +10,29 @@ import (
 	"strings"
 	"sync"
 
+	ansibleStructure "github.com/bridgecrewio/yor/src/ansible/structure"
+	armStructure "github.com/bridgecrewio/yor/src/arm/structure"
+	bicepStructure "github.com/bridgecrewio/yor/src/bicep/structure"
 	cfnStructure "github.com/bridgecrewio/yor/src/cloudformation/structure"
 	"github.com/bridgecrewio/yor/src/common"
 	"github.com/bridgecrewio/yor/src/common/clioptions"
 	"github.com/bridgecrewio/yor/src/common/logger"
 	"github.com/bridgecrewio/yor/src/common/reports"
+	"github.com/bridgecrewio/yor/src/common/structure"
 	"github.com/bridgecrewio/yor/src/common/tagging"
 	"github.com/bridgecrewio/yor/src/common/tagging/external"
 	"github.com/bridgecrewio/yor/src/common/tagging/simple"
 	"github.com/bridgecrewio/yor/src/common/tagging/tags"
 	taggingUtils "github.com/bridgecrewio/yor/src/common/tagging/utils"
 	"github.com/bridgecrewio/yor/src/common/utils"
+	crossplaneStructure "github.com/bridgecrewio/yor/src/crossplane/structure"
+	helmStructure "github.com/bridgecrewio/yor/src/helm/structure"
+	k8sStructure "github.com/bridgecrewio/yor/src/kubernetes/structure"
+	kustomizeStructure "github.com/bridgecrewio/yor/src/kustomize/structure"
+	pulumiStructure "github.com/bridgecrewio/yor/src/pulumi/structure"
 	slsStructure "github.com/bridgecrewio/yor/src/serverless/structure"
 	tfStructure "github.com/bridgecrewio/yor/src/terraform/structure"
+	terragruntStructure "github.com/bridgecrewio/yor/src/terragrunt/structure"
 )
 
 type Runner struct {
@@ -56,11 +66,35 @@ func (r *Runner) Init(commands *clioptions.TagOptions) error {
 	r.TagGroups = append(r.TagGroups, extraTagGroups...)
 	if commands.ConfigFile == "" {
 		logger.Info("Did not get an external config file")
+	} else {
+		if err := structure.LoadTagLimits(commands.ConfigFile); err != nil {
+			logger.Warning(fmt.Sprintf("failed to load the tag limits of %v: %v", commands.ConfigFile, err))
+		}
+		if err := structure.LoadTagConflictPolicies(commands.ConfigFile); err != nil {
+			logger.Warning(fmt.Sprintf("failed to load the tag conflict policies of %v: %v", commands.ConfigFile, err))
+		}
+		if err := structure.LoadProtectedTags(commands.ConfigFile); err != nil {
+			logger.Warning(fmt.Sprintf("failed to load the protected tags of %v: %v", commands.ConfigFile, err))
+		}
+		if err := structure.LoadTagMigrations(commands.ConfigFile); err != nil {
+			logger.Warning(fmt.Sprintf("failed to load the tag migrations of %v: %v", commands.ConfigFile, err))
+		}
+	}
+	if err := structure.AddProtectedTags(commands.ProtectedTags); err != nil {
+		logger.Warning(err.Error())
 	}
+	if err := structure.AddTagRenames(commands.RenameTags); err != nil {
+		return err
+	}
+	if err := structure.AddTagRemovals(commands.RemoveTags); err != nil {
+		return err
+	}
+	var staticTags []tags.ITag
 	for _, tagGroup := range r.TagGroups {
 		tagGroup.InitTagGroup(dir, commands.SkipTags, commands.Tag, tagging.WithTagPrefix(commands.TagPrefix))
 		if simpleTagGroup, ok := tagGroup.(*simple.TagGroup); ok {
 			simpleTagGroup.SetTags(extraTags)
+			staticTags = append(staticTags, simpleTagGroup.GetStaticTags()...)
 		} else if externalTagGroup, ok := tagGroup.(*external.TagGroup); ok && commands.ConfigFile != "" {
 			externalTagGroup.InitExternalTagGroups(commands.ConfigFile, commands.UseCodeOwners)
 		}
@@ -77,15 +111,49 @@ func (r *Runner) Init(commands *clioptions.TagOptions) error {
 			r.parsers = append(r.parsers, &cfnStructure.CloudformationParser{})
 		case "Serverless":
 			r.parsers = append(r.parsers, &slsStructure.ServerlessParser{})
+		case "Kubernetes":
+			r.parsers = append(r.parsers, &k8sStructure.KubernetesParser{})
+		case "Bicep":
+			r.parsers = append(r.parsers, &bicepStructure.BicepParser{})
+		case "ARM":
+			r.parsers = append(r.parsers, &armStructure.ArmParser{})
+		case "Pulumi":
+			r.parsers = append(r.parsers, &pulumiStructure.PulumiParser{})
+		case "Helm":
+			r.parsers = append(r.parsers, &helmStructure.HelmParser{})
+		case "Kustomize":
+			r.parsers = append(r.parsers, &kustomizeStructure.KustomizeParser{})
+		case "Terragrunt":
+			r.parsers = append(r.parsers, &terragruntStructure.TerragruntParser{})
+		case "Ansible":
+			r.parsers = append(r.parsers, &ansibleStructure.AnsibleParser{})
+		case "Crossplane":
+			r.parsers = append(r.parsers, &crossplaneStructure.CrossplaneParser{})
 		default:
 			logger.Warning(fmt.Sprintf("ignoring unknown parser %#v", err))
 		}
 		processedParsers[p] = struct{}{}
 	}
 	options := map[string]string{
-		"tag-local-modules": strconv.FormatBool(commands.TagLocalModules)}
+		"tag-local-modules":       strconv.FormatBool(commands.TagLocalModules),
+		"inspect-modules":         strconv.FormatBool(commands.InspectModules),
+		"cdk-out":                 strconv.FormatBool(commands.CdkOut),
+		"asg-propagate-at-launch": strconv.FormatBool(commands.ASGPropagateAtLaunch),
+		"use-default-tags":        strconv.FormatBool(commands.UseDefaultTags),
+		"config-file":             commands.ConfigFile,
+		"provider-schemas":        commands.ProviderSchemas,
+		"terragrunt-tags-key":     commands.TerragruntTagsKey}
 	for _, parser := range r.parsers {
 		parser.Init(dir, options)
+		if tfParser, ok := parser.(*tfStructure.TerraformParser); ok {
+			tfParser.SetStaticTags(staticTags)
+		}
+		// the managed resources and compositions of Crossplane are tagged in their parameters by the Crossplane parser
+		if k8sParser, ok := parser.(*k8sStructure.KubernetesParser); ok {
+			if _, ok := processedParsers["Crossplane"]; ok {
+				k8sParser.SetSkippedManifests(crossplaneStructure.IsCrossplaneManifest)
+			}
+		}
 	}
 
 	r.ChangeAccumulator = reports.TagChangeAccumulatorInstance
@@ -204,17 +272,44 @@ func (r *Runner) TagFile(file string) {
 			if block.IsBlockTaggable() {
 				logger.Debug(fmt.Sprintf("Tagging %v:%v", file, block.GetResourceID()))
 				isFileTaggable = true
+				var proposals []structure.TagProposal
+				// the tags of every tag group, including the tag groups of plugins, follow the tag constraints
+				tagging.ValidateTagGroupTags(block)
 				for _, tagGroup := range r.TagGroups {
+					previousTags := make(map[tags.ITag]bool)
+					for _, tag := range block.GetNewTags() {
+						previousTags[tag] = true
+					}
 					err := tagGroup.CreateTagsForBlock(block)
+					for _, tag := range block.GetNewTags() {
+						if !previousTags[tag] {
+							proposals = append(proposals, structure.TagProposal{Group: taggingUtils.GetTagGroupName(tagGroup), Tag: tag})
+						}
+					}
 					if err != nil {
 						logger.Warning(fmt.Sprintf("Failed to tag %v in %v due to %v", block.GetResourceID(), block.GetFilePath(), err.Error()))
 						continue
 					}
 				}
+				if conflictsBlock, ok := block.(structure.ITagConflictsBlock); ok {
+					if err := conflictsBlock.ResolveTagConflicts(proposals); err != nil {
+						logger.Warning(fmt.Sprintf("Failed to tag %v in %v due to %v", block.GetResourceID(), block.GetFilePath(), err.Error()))
+					}
+				}
+				if migrationBlock, ok := block.(structure.ITagMigrationBlock); ok {
+					migrationBlock.MigrateTags(structure.TagMigrationRules)
+				}
+				// the migrated tags follow the tag constraints as well, and the tag limit is applied once the conflicts are resolved
+				tagging.ValidateNewTags(block)
 			} else {
 				logger.Debug(fmt.Sprintf("Block %v:%v is not taggable, skipping", file, block.GetResourceID()))
 			}
 			r.ChangeAccumulator.AccumulateChanges(block)
+			if nestedBlocksBlock, ok := block.(structure.INestedBlocksBlock); ok && block.IsBlockTaggable() {
+				for _, nestedBlock := range nestedBlocksBlock.GetNestedBlocks() {
+					r.ChangeAccumulator.AccumulateChanges(nestedBlock)
+				}
+			}
 		}
 		if isFileTaggable && !r.dryRun {
 			err = parser.WriteFile(file, blocks, file)
@@ -13,6 +13,7 @@ import (
 	cloudformationStructure "github.com/bridgecrewio/yor/src/cloudformation/structure"
 	"github.com/bridgecrewio/yor/src/common/clioptions"
 	"github.com/bridgecrewio/yor/src/common/gitservice"
+	"github.com/bridgecrewio/yor/src/common/reports"
 	"github.com/bridgecrewio/yor/src/common/structure"
 	"github.com/bridgecrewio/yor/src/common/tagging/gittag"
 	taggingUtils "github.com/bridgecrewio/yor/src/common/tagging/utils"
@@ -336,3 +337,316 @@ func TestNonRecursiveTagging(t *testing.T) {
 		}
 	})
 }
+
+func TestTagConflicts(t *testing.T) {
+	t.Run("conflicts between tag groups are resolved by the conflict policies", func(t *testing.T) {
+		defer func(accumulator *reports.TagChangeAccumulator, policies map[string]string) {
+			reports.TagChangeAccumulatorInstance = accumulator
+			structure.TagConflictPolicies = policies
+		}(reports.TagChangeAccumulatorInstance, structure.TagConflictPolicies)
+		reports.TagChangeAccumulatorInstance = &reports.TagChangeAccumulator{}
+		structure.TagConflictPolicies = map[string]string{}
+		_ = os.Setenv("YOR_SIMPLE_TAGS", `{"env": "prod", "owner": "data", "team": "data", "cost_center": "1234"}`)
+		defer os.Unsetenv("YOR_SIMPLE_TAGS")
+
+		rootDir := "../../../tests/tag_conflicts"
+		runner := new(Runner)
+		err := runner.Init(&clioptions.TagOptions{
+			Directory:  rootDir,
+			TagGroups:  []string{string(taggingUtils.SimpleTagGroupName), string(taggingUtils.ExternalTagName)},
+			ConfigFile: filepath.Join(rootDir, "yor_config.yml"),
+			Parsers:    []string{"Terraform"},
+			DryRun:     true,
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		reportService, err := runner.TagDirectory()
+		if err != nil {
+			t.Error(err)
+		}
+		report := reportService.CreateReport()
+		file := filepath.Join(rootDir, "main.tf")
+		assert.Equal(t, []reports.TagConflictRecord{
+			{File: file, ResourceID: "aws_s3_bucket.data", TagKey: "cost_center", Proposals: []reports.TagProposalRecord{{Group: "simple", Value: "1234"}, {Group: "external", Value: "5678"}}, Policy: structure.FailPolicy, Resolution: "failed", Failed: true},
+			{File: file, ResourceID: "aws_s3_bucket.data", TagKey: "env", ExistingValue: "dev", Proposals: []reports.TagProposalRecord{{Group: "simple", Value: "prod"}, {Group: "external", Value: "staging"}}, Resolution: "value of external"},
+			{File: file, ResourceID: "aws_s3_bucket.data", TagKey: "owner", ExistingValue: "data-team", Proposals: []reports.TagProposalRecord{{Group: "simple", Value: "data"}, {Group: "external", Value: "platform-team"}}, Policy: structure.KeepExistingPolicy, Resolution: "existing value"},
+			{File: file, ResourceID: "aws_s3_bucket.data", TagKey: "team", Proposals: []reports.TagProposalRecord{{Group: "simple", Value: "data"}, {Group: "external", Value: "platform"}}, Policy: structure.FirstGroupWinsPolicy, Resolution: "value of simple"},
+		}, report.TagConflicts)
+		assert.True(t, reportService.HasFailedTagConflicts())
+		assert.ElementsMatch(t, []reports.TagRecord{
+			{File: file, ResourceID: "aws_s3_bucket.data", TagKey: "team", UpdatedValue: "data"},
+			{File: file, ResourceID: "aws_s3_bucket.data", TagKey: "env", OldValue: "dev", UpdatedValue: "staging"},
+		}, report.UpdatedResourceTags)
+	})
+}
+
+func TestProtectedTags(t *testing.T) {
+	t.Run("protected tags of the config file and the flag are not updated", func(t *testing.T) {
+		defer func(accumulator *reports.TagChangeAccumulator, patterns []string) {
+			reports.TagChangeAccumulatorInstance = accumulator
+			structure.ProtectedTagPatterns = patterns
+		}(reports.TagChangeAccumulatorInstance, structure.ProtectedTagPatterns)
+		reports.TagChangeAccumulatorInstance = &reports.TagChangeAccumulator{}
+		structure.ProtectedTagPatterns = nil
+		_ = os.Setenv("YOR_SIMPLE_TAGS", `{"cost_center": "5678", "billing_id": "finance", "owner": "platform-team"}`)
+		defer os.Unsetenv("YOR_SIMPLE_TAGS")
+
+		rootDir := "../../../tests/protected_tags"
+		runner := new(Runner)
+		err := runner.Init(&clioptions.TagOptions{
+			Directory:     rootDir,
+			TagGroups:     []string{string(taggingUtils.SimpleTagGroupName)},
+			ConfigFile:    filepath.Join(rootDir, "yor_config.yml"),
+			ProtectedTags: []string{"owner"},
+			Parsers:       []string{"Terraform"},
+			DryRun:        true,
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		reportService, err := runner.TagDirectory()
+		if err != nil {
+			t.Error(err)
+		}
+		report := reportService.CreateReport()
+		file := filepath.Join(rootDir, "main.tf")
+		assert.Equal(t, []reports.BlockedTagUpdateRecord{
+			{File: file, ResourceID: "aws_s3_bucket.data", TagKey: "billing_id", ExistingValue: "data-billing", BlockedValue: "finance"},
+			{File: file, ResourceID: "aws_s3_bucket.data", TagKey: "cost_center", ExistingValue: "1234", BlockedValue: "5678"},
+			{File: file, ResourceID: "aws_s3_bucket.data", TagKey: "owner", ExistingValue: "data-team", BlockedValue: "platform-team"},
+		}, report.BlockedTagUpdates)
+		// protected tags the resources do not have are added
+		assert.ElementsMatch(t, []reports.TagRecord{
+			{File: file, ResourceID: "aws_s3_bucket.logs", TagKey: "billing_id", UpdatedValue: "finance"},
+			{File: file, ResourceID: "aws_s3_bucket.logs", TagKey: "cost_center", UpdatedValue: "5678"},
+			{File: file, ResourceID: "aws_s3_bucket.logs", TagKey: "owner", UpdatedValue: "platform-team"},
+		}, report.NewResourceTags)
+		assert.Empty(t, report.UpdatedResourceTags)
+	})
+}
+
+func TestTagMigrations(t *testing.T) {
+	t.Run("existing tags are removed and renamed in every framework", func(t *testing.T) {
+		defer func(accumulator *reports.TagChangeAccumulator, migrations *structure.TagMigrations) {
+			reports.TagChangeAccumulatorInstance = accumulator
+			structure.TagMigrationRules = migrations
+		}(reports.TagChangeAccumulatorInstance, structure.TagMigrationRules)
+		reports.TagChangeAccumulatorInstance = &reports.TagChangeAccumulator{}
+		structure.TagMigrationRules = &structure.TagMigrations{Rename: map[string]string{}}
+
+		testDir := "../../../tests/tag_migrations"
+		rootDir := t.TempDir()
+		fileNames := []string{"main.tf", "main.tf.json", "template.yaml", "template.json", "serverless.yml"}
+		for _, fileName := range fileNames {
+			src, err := os.ReadFile(filepath.Join(testDir, "src", fileName))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = os.WriteFile(filepath.Join(rootDir, fileName), src, 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		runner := new(Runner)
+		err := runner.Init(&clioptions.TagOptions{
+			Directory:  rootDir,
+			ConfigFile: filepath.Join(testDir, "yor_config.yml"),
+			Parsers:    []string{"Terraform", "CloudFormation", "Serverless"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		reportService, err := runner.TagDirectory()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, fileName := range fileNames {
+			expected, _ := os.ReadFile(filepath.Join(testDir, "expected", fileName))
+			actual, _ := os.ReadFile(filepath.Join(rootDir, fileName))
+			assert.Equal(t, string(expected), string(actual), fileName)
+		}
+
+		report := reportService.CreateReport()
+		assert.Equal(t, 8, report.Summary.MigratedResources)
+		tfFile := filepath.Join(rootDir, "main.tf")
+		assert.Contains(t, report.RemovedResourceTags, reports.RemovedTagRecord{File: tfFile, ResourceID: "aws_s3_bucket.data", TagKey: "git_modifiers", Value: "alice/bob"})
+		assert.Contains(t, report.RenamedResourceTags, reports.RenamedTagRecord{File: tfFile, ResourceID: "aws_autoscaling_group.workers", OldKey: "git_repo", NewKey: "source_repository", Value: "yor"})
+		tfJSONFile := filepath.Join(rootDir, "main.tf.json")
+		assert.Contains(t, report.RemovedResourceTags, reports.RemovedTagRecord{File: tfJSONFile, ResourceID: "aws_s3_bucket.archive", TagKey: "git_modifiers", Value: "alice/bob"})
+		assert.Contains(t, report.RenamedResourceTags, reports.RenamedTagRecord{File: tfJSONFile, ResourceID: "aws_s3_bucket.archive", OldKey: "git_repo", NewKey: "source_repository", Value: "yor"})
+		assert.Len(t, report.RemovedResourceTags, 8)
+		assert.Len(t, report.RenamedResourceTags, 8)
+		assert.Empty(t, report.NewResourceTags)
+		assert.Empty(t, report.UpdatedResourceTags)
+	})
+
+	t.Run("renames of the flag apply to the new tags", func(t *testing.T) {
+		defer func(accumulator *reports.TagChangeAccumulator, migrations *structure.TagMigrations) {
+			reports.TagChangeAccumulatorInstance = accumulator
+			structure.TagMigrationRules = migrations
+		}(reports.TagChangeAccumulatorInstance, structure.TagMigrationRules)
+		reports.TagChangeAccumulatorInstance = &reports.TagChangeAccumulator{}
+		structure.TagMigrationRules = &structure.TagMigrations{Rename: map[string]string{}}
+		_ = os.Setenv("YOR_SIMPLE_TAGS", `{"git_repo": "yor", "team": "platform"}`)
+		defer os.Unsetenv("YOR_SIMPLE_TAGS")
+
+		rootDir := "../../../tests/tag_migrations/src"
+		runner := new(Runner)
+		err := runner.Init(&clioptions.TagOptions{
+			Directory:  rootDir,
+			TagGroups:  []string{string(taggingUtils.SimpleTagGroupName)},
+			RenameTags: []string{"git_repo=source_repository"},
+			Parsers:    []string{"Serverless"},
+			DryRun:     true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		reportService, err := runner.TagDirectory()
+		if err != nil {
+			t.Fatal(err)
+		}
+		report := reportService.CreateReport()
+		file := filepath.Join(rootDir, "serverless.yml")
+		assert.Equal(t, []reports.RenamedTagRecord{
+			{File: file, ResourceID: "process", OldKey: "git_repo", NewKey: "source_repository", Value: "yor"},
+		}, report.RenamedResourceTags)
+		assert.Equal(t, []reports.TagRecord{
+			{File: file, ResourceID: "process", TagKey: "team", UpdatedValue: "platform"},
+		}, report.NewResourceTags)
+		assert.Empty(t, report.RemovedResourceTags)
+	})
+
+	t.Run("invalid renames fail", func(t *testing.T) {
+		defer func(migrations *structure.TagMigrations) {
+			structure.TagMigrationRules = migrations
+		}(structure.TagMigrationRules)
+		structure.TagMigrationRules = &structure.TagMigrations{Rename: map[string]string{}}
+		err := new(Runner).Init(&clioptions.TagOptions{
+			Directory:  "../../../tests/tag_migrations/src",
+			RenameTags: []string{"git_repo"},
+			Parsers:    []string{"Serverless"},
+			DryRun:     true,
+		})
+		assert.EqualError(t, err, "invalid tag rename git_repo, expected old_key=new_key")
+	})
+}
+
+func TestNestedTags(t *testing.T) {
+	t.Run("the tags of nested resources are reported", func(t *testing.T) {
+		defer func(accumulator *reports.TagChangeAccumulator) {
+			reports.TagChangeAccumulatorInstance = accumulator
+		}(reports.TagChangeAccumulatorInstance)
+		reports.TagChangeAccumulatorInstance = &reports.TagChangeAccumulator{}
+		_ = os.Setenv("YOR_SIMPLE_TAGS", `{"team": "platform"}`)
+		defer os.Unsetenv("YOR_SIMPLE_TAGS")
+
+		rootDir := "../../../tests/terraform/resources/nested_tags"
+		runner := new(Runner)
+		err := runner.Init(&clioptions.TagOptions{
+			Directory: rootDir,
+			TagGroups: []string{string(taggingUtils.SimpleTagGroupName)},
+			Parsers:   []string{"Terraform"},
+			DryRun:    true,
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		reportService, err := runner.TagDirectory()
+		if err != nil {
+			t.Error(err)
+		}
+		report := reportService.CreateReport()
+		file := filepath.Join(rootDir, "main.tf")
+		var resourceIDs []string
+		for _, record := range report.NewResourceTags {
+			if record.File == file && record.TagKey == "team" {
+				resourceIDs = append(resourceIDs, record.ResourceID)
+			}
+		}
+		// the nested blocks of aws_instance.volume_tags are not tagged, as it sets volume_tags
+		assert.ElementsMatch(t, []string{
+			"aws_instance.web", "aws_instance.web.root_block_device.tags", "aws_instance.web.ebs_block_device.tags",
+			"aws_instance.volume_tags",
+			"aws_launch_template.template", "aws_launch_template.template.tag_specifications[0].tags",
+			"aws_launch_template.template.tag_specifications[1].tags",
+			"azurerm_kubernetes_cluster.cluster", "azurerm_kubernetes_cluster.cluster.default_node_pool.tags",
+		}, resourceIDs)
+	})
+}
+
+func TestKubernetesAndCrossplaneParsers(t *testing.T) {
+	t.Run("managed resources are only tagged by the crossplane parser", func(t *testing.T) {
+		defer func(accumulator *reports.TagChangeAccumulator) {
+			reports.TagChangeAccumulatorInstance = accumulator
+		}(reports.TagChangeAccumulatorInstance)
+		reports.TagChangeAccumulatorInstance = &reports.TagChangeAccumulator{}
+
+		rootDir := t.TempDir()
+		src, err := os.ReadFile("../../../tests/crossplane/resources/managed.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		filePath := filepath.Join(rootDir, "managed.yaml")
+		if err = os.WriteFile(filePath, src, 0600); err != nil {
+			t.Fatal(err)
+		}
+		runner := new(Runner)
+		err = runner.Init(&clioptions.TagOptions{
+			Directory: rootDir,
+			TagGroups: []string{string(taggingUtils.Code2Cloud)},
+			Parsers:   []string{"Kubernetes", "Crossplane"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = runner.TagDirectory(); err != nil {
+			t.Fatal(err)
+		}
+		actual, _ := os.ReadFile(filePath)
+		documents := strings.Split(string(actual), "\n---\n")
+		var traceCounts []int
+		for _, document := range documents {
+			traceCounts = append(traceCounts, strings.Count(document, tags.YorTraceTagKey))
+		}
+		// the managed resources are traced once in their parameters, the ProviderConfig and the ConfigMap in their labels
+		assert.Equal(t, []int{1, 1, 1, 0, 0, 1, 1}, traceCounts)
+	})
+
+	t.Run("managed resources are labelled by the kubernetes parser alone", func(t *testing.T) {
+		defer func(accumulator *reports.TagChangeAccumulator) {
+			reports.TagChangeAccumulatorInstance = accumulator
+		}(reports.TagChangeAccumulatorInstance)
+		reports.TagChangeAccumulatorInstance = &reports.TagChangeAccumulator{}
+
+		rootDir := t.TempDir()
+		src, err := os.ReadFile("../../../tests/crossplane/resources/managed.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		filePath := filepath.Join(rootDir, "managed.yaml")
+		if err = os.WriteFile(filePath, src, 0600); err != nil {
+			t.Fatal(err)
+		}
+		runner := new(Runner)
+		err = runner.Init(&clioptions.TagOptions{
+			Directory: rootDir,
+			TagGroups: []string{string(taggingUtils.Code2Cloud)},
+			Parsers:   []string{"Kubernetes"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = runner.TagDirectory(); err != nil {
+			t.Fatal(err)
+		}
+		actual, _ := os.ReadFile(filePath)
+		var traceCounts []int
+		for _, document := range strings.Split(string(actual), "\n---\n") {
+			traceCounts = append(traceCounts, strings.Count(document, tags.YorTraceTagKey))
+		}
+		// without the crossplane parser, the managed resources are traced in their labels like any other object, apart
+		// from the resource skipped by its comment
+		assert.Equal(t, []int{1, 1, 1, 0, 1, 1, 1}, traceCounts)
+	})
+}
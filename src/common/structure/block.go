@@ -1,7 +1,9 @@
 package structure
 
 import (
+	"regexp"
 	"sort"
+	"strings"
 
 	"github.com/bridgecrewio/yor/src/common/tagging/tags"
 )
@@ -14,11 +16,30 @@ type Lines struct {
 type TagDiff struct {
 	Added   []tags.ITag
 	Updated []*tags.TagDiff
+	Removed []tags.ITag
+	Renamed []*tags.TagRename
 }
 
-var SpecialResourceTypes = map[string]int{
-	"aws_db_proxy":      10,
-	"AWS::RDS::DBProxy": 10,
+// IsEmpty returns true if the tags of the block are not changed
+func (d *TagDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Updated) == 0 && len(d.Removed) == 0 && len(d.Renamed) == 0
+}
+
+// The cloud providers whose tag constraints are known
+const (
+	AWSProvider   = "aws"
+	AzureProvider = "azure"
+	GCPProvider   = "gcp"
+)
+
+// resourceTypePrefixToProvider maps the prefixes of the resource types of the frameworks to the cloud provider of the
+// resources, e.g. aws_ of terraform or AWS:: of cloudformation
+var resourceTypePrefixToProvider = map[string]string{
+	"aws_":       AWSProvider,
+	"AWS::":      AWSProvider,
+	"azurerm_":   AzureProvider,
+	"Microsoft.": AzureProvider,
+	"google_":    GCPProvider,
 }
 
 type IBlock interface {
@@ -42,6 +63,94 @@ type IBlock interface {
 	GetResourceName() string
 }
 
+// IMapTagsBlock is implemented by blocks which may hold their tags as a key-value map rather than a list of
+// Key/Value pairs, such as AWS SAM resources in a CloudFormation template
+type IMapTagsBlock interface {
+	HasMapTags() bool
+}
+
+// HasMapTags returns true if the tags of the block are written as a key-value map
+func HasMapTags(block IBlock) bool {
+	mapTagsBlock, ok := block.(IMapTagsBlock)
+	return ok && mapTagsBlock.HasMapTags()
+}
+
+// IBlamePathsBlock is implemented by blocks which are defined by other files besides their own, such as the templates of
+// a Helm chart, whose git tags are calculated from the blame of all these files
+type IBlamePathsBlock interface {
+	GetBlamePaths() []string
+}
+
+// ICloudProviderBlock is implemented by blocks whose cloud provider is not known by their resource type, such as the
+// blocks of Ansible tasks or Crossplane managed resources
+type ICloudProviderBlock interface {
+	GetCloudProvider() string
+}
+
+// GetCloudProvider returns the cloud provider of the resource of the block, or an empty string if it is not known
+func GetCloudProvider(block IBlock) string {
+	if cloudProviderBlock, ok := block.(ICloudProviderBlock); ok {
+		return cloudProviderBlock.GetCloudProvider()
+	}
+	if block.IsGCPBlock() {
+		return GCPProvider
+	}
+	for prefix, provider := range resourceTypePrefixToProvider {
+		if strings.HasPrefix(block.GetResourceType(), prefix) {
+			return provider
+		}
+	}
+	return ""
+}
+
+// The resolutions of the new tags which violate the tag constraints or the tag limit of their resource, which are
+// listed in the report
+const (
+	TagSanitizedResolution = "sanitized"
+	TagDroppedResolution   = "dropped"
+)
+
+// TagViolation is a new tag of the block which violated the tag constraints of its cloud provider, and how it was
+// resolved
+type TagViolation struct {
+	Provider   string
+	Key        string
+	Value      string
+	Violation  string
+	Resolution string
+}
+
+// INewTagsBlock is implemented by blocks whose new tags can be replaced once all the tag groups added them, e.g. by the
+// new tags which follow the tag constraints of their cloud provider
+type INewTagsBlock interface {
+	SetNewTags(newTags []tags.ITag)
+}
+
+// INewTagsValidationBlock is implemented by blocks which validate the tags of every tag group before they add them. The
+// tag limit of such blocks is not applied by AddNewTags, but once all the tag groups added their tags.
+type INewTagsValidationBlock interface {
+	SetNewTagsValidator(validator func(newTags []tags.ITag) []tags.ITag)
+}
+
+// ITagCharactersBlock is implemented by blocks whose resources do not accept some characters in their tags, besides the
+// tag constraints of their cloud provider, such as the resources of some terraform providers
+type ITagCharactersBlock interface {
+	GetTagProvider() string
+	GetInvalidTagCharacters() (invalidKeyChars *regexp.Regexp, invalidValueChars *regexp.Regexp)
+}
+
+// INestedBlocksBlock is implemented by blocks which tag their nested resources with their own new tags, such as the
+// volumes of an instance, whose changes are reported along with the changes of the blocks
+type INestedBlocksBlock interface {
+	GetNestedBlocks() []IBlock
+}
+
+// ITagViolationsBlock is implemented by blocks which keep the tag constraint violations of their new tags for the report
+type ITagViolationsBlock interface {
+	AddTagViolation(violation TagViolation)
+	GetTagViolations() []TagViolation
+}
+
 type Block struct {
 	FilePath          string
 	ExitingTags       []tags.ITag
@@ -53,6 +162,12 @@ type Block struct {
 	TagLines          Lines
 	Name              string
 	Type              string
+	TagViolations     []TagViolation
+	TagConflicts      []TagConflict
+	BlockedTagUpdates []*tags.TagDiff
+	RemovedTags       []tags.ITag
+	RenamedTags       []*tags.TagRename
+	newTagsValidator  func(newTags []tags.ITag) []tags.ITag
 }
 
 func (b *Block) Init(filePath string, rawBlock interface{}) {
@@ -84,6 +199,10 @@ func (b *Block) AddNewTags(newTags []tags.ITag) {
 	if newTags == nil {
 		return
 	}
+	if b.newTagsValidator != nil {
+		newTags = b.newTagsValidator(newTags)
+	}
+	newTags = b.filterProtectedTags(newTags)
 	isTraced := false
 	yorTagKey := tags.YorTraceTagKey
 	for _, tag := range b.ExitingTags {
@@ -107,20 +226,24 @@ func (b *Block) AddNewTags(newTags []tags.ITag) {
 		}
 	}
 	b.NewTags = append(b.NewTags, newTags...)
-	sort.Slice(b.NewTags, func(i, j int) bool {
+	sort.SliceStable(b.NewTags, func(i, j int) bool {
 		return b.NewTags[i].GetKey() > b.NewTags[j].GetKey()
 	})
-	if limit, ok := SpecialResourceTypes[b.GetResourceType()]; ok && len(b.NewTags)+len(b.ExitingTags) > limit {
-		b.NewTags = b.NewTags[0 : limit-len(b.ExitingTags)]
+	// the new tags of every tag group are truncated to the tag limit of the block, unless they are validated, then the
+	// limit is applied once the tags of all the tag groups are added
+	if b.newTagsValidator == nil {
+		b.ApplyTagLimit(GetTagLimit(b))
 	}
 }
 
-// MergeTags merges the tags and returns all the tags.
+// MergeTags merges the tags and returns all the tags. Removed tags are not included, and renamed tags are included by
+// their new keys.
 func (b *Block) MergeTags() []tags.ITag {
+	existingTags := b.getMigratedTags()
 	existingTagsByKey := map[string]tags.ITag{}
 	newTagsByKey := map[string]tags.ITag{}
 
-	for _, tag := range b.ExitingTags {
+	for _, tag := range existingTags {
 		existingTagsByKey[tag.GetKey()] = tag
 	}
 	for _, tag := range b.NewTags {
@@ -129,7 +252,7 @@ func (b *Block) MergeTags() []tags.ITag {
 
 	var mergedTags []tags.ITag
 	yorTagKeyName := tags.YorTraceTagKey
-	for _, existingTag := range b.ExitingTags {
+	for _, existingTag := range existingTags {
 		if newTag, ok := newTagsByKey[existingTag.GetKey()]; ok {
 			match := tags.IsTagKeyMatch(existingTag, yorTagKeyName)
 			if match {
@@ -143,20 +266,25 @@ func (b *Block) MergeTags() []tags.ITag {
 		}
 	}
 
-	for newTagKey := range newTagsByKey {
-		mergedTags = append(mergedTags, newTagsByKey[newTagKey])
+	// the new tags are added in their order, so the merged tags are the same in every run
+	for _, newTag := range b.NewTags {
+		if tag, ok := newTagsByKey[newTag.GetKey()]; ok && tag == newTag {
+			mergedTags = append(mergedTags, newTag)
+			delete(newTagsByKey, newTag.GetKey())
+		}
 	}
 
 	return mergedTags
 }
 
 // CalculateTagsDiff returns a map which explains the changes in tags for this block
-// Added is the new tags, Updated is the tags which were modified
+// Added is the new tags, Updated is the tags which were modified, Removed and Renamed are the existing tags which were
+// migrated. Updates of renamed tags are by their new keys.
 func (b *Block) CalculateTagsDiff() *TagDiff {
-	var diff = TagDiff{}
+	var diff = TagDiff{Removed: b.RemovedTags, Renamed: b.RenamedTags}
 	for _, newTag := range b.GetNewTags() {
 		found := false
-		for _, existingTag := range b.GetExistingTags() {
+		for _, existingTag := range b.getMigratedTags() {
 			if newTag.GetKey() == existingTag.GetKey() {
 				found = true
 				if newTag.GetValue() != existingTag.GetValue() {
@@ -184,6 +312,14 @@ func (b *Block) GetExistingTags() []tags.ITag {
 	return b.ExitingTags
 }
 
+func (b *Block) SetNewTags(newTags []tags.ITag) {
+	b.NewTags = newTags
+}
+
+func (b *Block) SetNewTagsValidator(validator func(newTags []tags.ITag) []tags.ITag) {
+	b.newTagsValidator = validator
+}
+
 func (b *Block) GetNewTags() []tags.ITag {
 	return b.NewTags
 }
@@ -213,6 +349,14 @@ func (b *Block) IsGCPBlock() bool {
 	return false
 }
 
+func (b *Block) AddTagViolation(violation TagViolation) {
+	b.TagViolations = append(b.TagViolations, violation)
+}
+
+func (b *Block) GetTagViolations() []TagViolation {
+	return b.TagViolations
+}
+
 func (b *Block) GetResourceName() string {
 	return b.GetResourceID()
 }
@@ -0,0 +1,95 @@
+package structure
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"gopkg.in/yaml.v2"
+)
+
+// ProtectedTagPatterns are the glob patterns of the tag keys whose existing values are never modified, from the
+// protected_tags section of the config file and the --protected-tags flag
+var ProtectedTagPatterns []string
+
+// IProtectedTagsBlock is implemented by blocks which keep the blocked updates of their protected tags for the report
+type IProtectedTagsBlock interface {
+	GetBlockedTagUpdates() []*tags.TagDiff
+}
+
+// LoadProtectedTags adds the patterns of the protected_tags section of the config file to ProtectedTagPatterns
+func LoadProtectedTags(configFilePath string) error {
+	// #nosec G304
+	confBytes, err := os.ReadFile(configFilePath)
+	if err != nil {
+		return err
+	}
+	config := struct {
+		ProtectedTags []string `yaml:"protected_tags"`
+	}{}
+	if err = yaml.Unmarshal(confBytes, &config); err != nil {
+		return err
+	}
+	return AddProtectedTags(config.ProtectedTags)
+}
+
+// AddProtectedTags adds glob patterns of protected tag keys, such as billing_*, to ProtectedTagPatterns
+func AddProtectedTags(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid protected tags pattern %v: %v", pattern, err)
+		}
+		ProtectedTagPatterns = append(ProtectedTagPatterns, pattern)
+	}
+	return nil
+}
+
+// IsProtectedTag returns true if the key matches one of the protected tags patterns
+func IsProtectedTag(key string) bool {
+	key = strings.Trim(key, `"`)
+	for _, pattern := range ProtectedTagPatterns {
+		if match, _ := path.Match(pattern, key); match {
+			return true
+		}
+	}
+	return false
+}
+
+// filterProtectedTags removes the new tags which would update the protected tags of the block, and keeps the blocked
+// updates for the report. Protected tags the block does not have are added as usual.
+func (b *Block) filterProtectedTags(newTags []tags.ITag) []tags.ITag {
+	if len(ProtectedTagPatterns) == 0 {
+		return newTags
+	}
+	existingValues := make(map[string]string)
+	for _, tag := range b.ExitingTags {
+		existingValues[tag.GetKey()] = tag.GetValue()
+	}
+	filteredTags := make([]tags.ITag, 0, len(newTags))
+	for _, tag := range newTags {
+		existingValue, isExisting := existingValues[tag.GetKey()]
+		if !isExisting || !IsProtectedTag(tag.GetKey()) {
+			filteredTags = append(filteredTags, tag)
+			continue
+		}
+		if tag.GetValue() != existingValue && !b.isBlockedTagUpdate(tag) {
+			b.BlockedTagUpdates = append(b.BlockedTagUpdates, &tags.TagDiff{Key: tag.GetKey(), PrevValue: existingValue, NewValue: tag.GetValue()})
+		}
+	}
+	return filteredTags
+}
+
+func (b *Block) isBlockedTagUpdate(tag tags.ITag) bool {
+	for _, blockedUpdate := range b.BlockedTagUpdates {
+		if blockedUpdate.Key == tag.GetKey() && blockedUpdate.NewValue == tag.GetValue() {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Block) GetBlockedTagUpdates() []*tags.TagDiff {
+	return b.BlockedTagUpdates
+}
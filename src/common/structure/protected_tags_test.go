@@ -0,0 +1,57 @@
+package structure
+
+import (
+	"testing"
+
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtectedTags(t *testing.T) {
+	defer func(patterns []string) {
+		ProtectedTagPatterns = patterns
+	}(ProtectedTagPatterns)
+
+	t.Run("protected tags are loaded from the config file", func(t *testing.T) {
+		ProtectedTagPatterns = nil
+		err := LoadProtectedTags("../../../tests/protected_tags/yor_config.yml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []string{"cost_center", "billing_*"}, ProtectedTagPatterns)
+		assert.True(t, IsProtectedTag("cost_center"))
+		assert.True(t, IsProtectedTag(`"billing_id"`))
+		assert.False(t, IsProtectedTag("cost_center_id"))
+		assert.Error(t, AddProtectedTags([]string{"billing_["}))
+	})
+
+	t.Run("existing protected tags are not updated", func(t *testing.T) {
+		ProtectedTagPatterns = []string{"cost_center", "billing_*"}
+		b := &Block{
+			Name: "aws_s3_bucket.data",
+			Type: "aws_s3_bucket",
+			ExitingTags: []tags.ITag{
+				&tags.Tag{Key: "cost_center", Value: "1234"},
+				&tags.Tag{Key: "billing_id", Value: "data-billing"},
+				&tags.Tag{Key: "owner", Value: "data-team"},
+			},
+		}
+		b.AddNewTags([]tags.ITag{
+			&tags.Tag{Key: "cost_center", Value: "5678"},
+			&tags.Tag{Key: "billing_id", Value: "data-billing"},
+			&tags.Tag{Key: "billing_account", Value: "finance"},
+			&tags.Tag{Key: "owner", Value: "platform-team"},
+		})
+		// a second tag group proposing the same value is only reported once
+		b.AddNewTags([]tags.ITag{&tags.Tag{Key: "cost_center", Value: "5678"}})
+
+		assert.Equal(t, []tags.ITag{
+			&tags.Tag{Key: "owner", Value: "platform-team"},
+			&tags.Tag{Key: "billing_account", Value: "finance"},
+		}, b.GetNewTags())
+		assert.Equal(t, []*tags.TagDiff{{Key: "cost_center", PrevValue: "1234", NewValue: "5678"}}, b.GetBlockedTagUpdates())
+		diff := b.CalculateTagsDiff()
+		assert.Equal(t, []tags.ITag{&tags.Tag{Key: "billing_account", Value: "finance"}}, diff.Added)
+		assert.Equal(t, []*tags.TagDiff{{Key: "owner", PrevValue: "data-team", NewValue: "platform-team"}}, diff.Updated)
+	})
+}
@@ -0,0 +1,175 @@
+package structure
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"gopkg.in/yaml.v2"
+)
+
+// The policies of resolving the conflicts between the values the tag groups propose for the same tag key
+const (
+	// KeepExistingPolicy keeps the value of the tag the resource already has, and resolves the conflicts of new tags by
+	// the priorities of the tags
+	KeepExistingPolicy = "keep-existing"
+	// OverwritePolicy uses the value of the last tag group which proposes the tag
+	OverwritePolicy = "overwrite"
+	// FirstGroupWinsPolicy uses the value of the first tag group which proposes the tag
+	FirstGroupWinsPolicy = "first-group-wins"
+	// FailPolicy leaves the tag unchanged and fails the run if the tag groups propose different values
+	FailPolicy = "fail"
+)
+
+// TagConflictPolicies maps tag keys to the policy of resolving the conflicts of their values. Conflicts of the other
+// keys are resolved by the priorities of the tags, and by the order of the tag groups for tags of the same priority.
+var TagConflictPolicies = map[string]string{}
+
+// TagProposal is a new tag of the block and the tag group which proposed it
+type TagProposal struct {
+	Group string
+	Tag   tags.ITag
+}
+
+// TagConflict is a tag key which the tag groups proposed different values for, and how it was resolved
+type TagConflict struct {
+	Key           string
+	ExistingValue string
+	Proposals     []TagProposal
+	Policy        string
+	Resolution    string
+	Failed        bool
+}
+
+// ITagConflictsBlock is implemented by blocks which resolve the conflicts of the new tags of their tag groups, and keep
+// them for the report
+type ITagConflictsBlock interface {
+	ResolveTagConflicts(proposals []TagProposal) error
+	GetTagConflicts() []TagConflict
+}
+
+// LoadTagConflictPolicies adds the policies of the tag_conflicts section of the config file to TagConflictPolicies
+func LoadTagConflictPolicies(configFilePath string) error {
+	// #nosec G304
+	confBytes, err := os.ReadFile(configFilePath)
+	if err != nil {
+		return err
+	}
+	config := struct {
+		TagConflicts map[string]string `yaml:"tag_conflicts"`
+	}{}
+	if err = yaml.Unmarshal(confBytes, &config); err != nil {
+		return err
+	}
+	for key, policy := range config.TagConflicts {
+		switch policy {
+		case KeepExistingPolicy, OverwritePolicy, FirstGroupWinsPolicy, FailPolicy:
+			TagConflictPolicies[key] = policy
+		default:
+			return fmt.Errorf("unknown tag conflict policy %v of tag %v", policy, key)
+		}
+	}
+	return nil
+}
+
+// ResolveTagConflicts keeps a single new tag of every key the tag groups proposed, by the conflict policy of the key,
+// and keeps the conflicts for the report. The proposals are in the order of the tag groups. New tags which were
+// already dropped, e.g. by the tag limit, are ignored. An error is returned if the fail policy of a key was violated.
+func (b *Block) ResolveTagConflicts(proposals []TagProposal) error {
+	isNewTag := make(map[tags.ITag]bool)
+	for _, tag := range b.NewTags {
+		isNewTag[tag] = true
+	}
+	existingValues := make(map[string]string)
+	for _, tag := range b.ExitingTags {
+		existingValues[tag.GetKey()] = tag.GetValue()
+	}
+	var keys []string
+	proposalsByKey := make(map[string][]TagProposal)
+	for _, proposal := range proposals {
+		if !isNewTag[proposal.Tag] {
+			continue
+		}
+		key := proposal.Tag.GetKey()
+		if _, ok := proposalsByKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		proposalsByKey[key] = append(proposalsByKey[key], proposal)
+	}
+
+	droppedTags := make(map[tags.ITag]bool)
+	var failedKeys []string
+	for _, key := range keys {
+		keyProposals := proposalsByKey[key]
+		policy := TagConflictPolicies[key]
+		existingValue, isExisting := existingValues[key]
+		winner := -1
+		resolution := "existing value"
+		switch {
+		case policy == KeepExistingPolicy && isExisting:
+		case policy == FailPolicy && hasDifferentValues(keyProposals):
+			resolution = "failed"
+			failedKeys = append(failedKeys, key)
+		case policy == FirstGroupWinsPolicy:
+			winner = 0
+		case policy == OverwritePolicy:
+			winner = len(keyProposals) - 1
+		default:
+			winner = 0
+			for i, proposal := range keyProposals {
+				if proposal.Tag.GetPriority() >= keyProposals[winner].Tag.GetPriority() {
+					winner = i
+				}
+			}
+		}
+		for i, proposal := range keyProposals {
+			if i != winner {
+				droppedTags[proposal.Tag] = true
+			}
+		}
+		if winner >= 0 {
+			resolution = fmt.Sprintf("value of %v", keyProposals[winner].Group)
+		}
+		if hasDifferentValues(keyProposals) {
+			conflict := TagConflict{
+				Key:        key,
+				Proposals:  keyProposals,
+				Policy:     policy,
+				Resolution: resolution,
+				Failed:     winner < 0 && policy == FailPolicy,
+			}
+			if isExisting {
+				conflict.ExistingValue = existingValue
+			}
+			b.TagConflicts = append(b.TagConflicts, conflict)
+		}
+	}
+
+	// tag groups may add the new tags of the previous groups again, such as the external tag group
+	var keptTags []tags.ITag
+	for _, tag := range b.NewTags {
+		if !droppedTags[tag] {
+			keptTags = append(keptTags, tag)
+			droppedTags[tag] = true
+		}
+	}
+	b.NewTags = keptTags
+	if len(failedKeys) > 0 {
+		return fmt.Errorf("the tag groups proposed different values of %v, whose conflict policy is %v", strings.Join(failedKeys, ", "), FailPolicy)
+	}
+	return nil
+}
+
+func hasDifferentValues(proposals []TagProposal) bool {
+	for _, proposal := range proposals {
+		if proposal.Tag.GetValue() != proposals[0].Tag.GetValue() {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Block) GetTagConflicts() []TagConflict {
+	return b.TagConflicts
+}
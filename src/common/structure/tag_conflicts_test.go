@@ -0,0 +1,82 @@
+package structure
+
+import (
+	"testing"
+
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveTagConflicts(t *testing.T) {
+	t.Run("conflicts are resolved by the priorities of the tags", func(t *testing.T) {
+		simpleRepo := &tags.Tag{Key: "git_repo", Value: "data-platform"}
+		gitRepo := &tags.Tag{Key: "git_repo", Value: "yor", Priority: tags.GitTagPriority}
+		simpleEnv := &tags.Tag{Key: "env", Value: "prod"}
+		externalEnv := &tags.Tag{Key: "env", Value: "staging"}
+		gitFile := &tags.Tag{Key: "git_file", Value: "main.tf", Priority: tags.GitTagPriority}
+		b := &Block{Name: "aws_s3_bucket.data", Type: "aws_s3_bucket"}
+		b.AddNewTags([]tags.ITag{simpleRepo, simpleEnv})
+		b.AddNewTags([]tags.ITag{gitRepo, gitFile})
+		// the external tag group adds the new tags of the previous groups again
+		b.AddNewTags([]tags.ITag{simpleRepo, simpleEnv, gitRepo, gitFile, externalEnv})
+
+		err := b.ResolveTagConflicts([]TagProposal{
+			{Group: "simple", Tag: simpleRepo},
+			{Group: "simple", Tag: simpleEnv},
+			{Group: "git", Tag: gitRepo},
+			{Group: "git", Tag: gitFile},
+			{Group: "external", Tag: externalEnv},
+		})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []tags.ITag{simpleRepo, gitFile, externalEnv}, b.GetNewTags())
+		assert.Equal(t, []TagConflict{
+			{Key: "git_repo", Proposals: []TagProposal{{Group: "simple", Tag: simpleRepo}, {Group: "git", Tag: gitRepo}}, Resolution: "value of simple"},
+			{Key: "env", Proposals: []TagProposal{{Group: "simple", Tag: simpleEnv}, {Group: "external", Tag: externalEnv}}, Resolution: "value of external"},
+		}, b.GetTagConflicts())
+	})
+
+	t.Run("conflicts are resolved by the policies of their keys", func(t *testing.T) {
+		defer func(policies map[string]string) {
+			TagConflictPolicies = policies
+		}(TagConflictPolicies)
+		TagConflictPolicies = map[string]string{"owner": KeepExistingPolicy, "env": OverwritePolicy, "team": FailPolicy}
+		simpleOwner := &tags.Tag{Key: "owner", Value: "data"}
+		simpleEnv := &tags.Tag{Key: "env", Value: "prod", Priority: 10}
+		simpleTeam := &tags.Tag{Key: "team", Value: "data"}
+		externalEnv := &tags.Tag{Key: "env", Value: "staging"}
+		externalTeam := &tags.Tag{Key: "team", Value: "platform"}
+		b := &Block{
+			Name:        "aws_s3_bucket.data",
+			Type:        "aws_s3_bucket",
+			ExitingTags: []tags.ITag{&tags.Tag{Key: "owner", Value: "data-team"}, &tags.Tag{Key: "team", Value: "web"}},
+		}
+		b.AddNewTags([]tags.ITag{simpleOwner, simpleEnv, simpleTeam})
+		b.AddNewTags([]tags.ITag{externalEnv, externalTeam})
+
+		err := b.ResolveTagConflicts([]TagProposal{
+			{Group: "simple", Tag: simpleOwner},
+			{Group: "simple", Tag: simpleEnv},
+			{Group: "simple", Tag: simpleTeam},
+			{Group: "external", Tag: externalEnv},
+			{Group: "external", Tag: externalTeam},
+		})
+		assert.EqualError(t, err, "the tag groups proposed different values of team, whose conflict policy is fail")
+		assert.Equal(t, []tags.ITag{externalEnv}, b.GetNewTags())
+		assert.Equal(t, []TagConflict{
+			{Key: "env", Proposals: []TagProposal{{Group: "simple", Tag: simpleEnv}, {Group: "external", Tag: externalEnv}}, Policy: OverwritePolicy, Resolution: "value of external"},
+			{Key: "team", ExistingValue: "web", Proposals: []TagProposal{{Group: "simple", Tag: simpleTeam}, {Group: "external", Tag: externalTeam}}, Policy: FailPolicy, Resolution: "failed", Failed: true},
+		}, b.GetTagConflicts())
+	})
+}
+
+func TestLoadTagConflictPolicies(t *testing.T) {
+	defer func(policies map[string]string) {
+		TagConflictPolicies = policies
+	}(TagConflictPolicies)
+	TagConflictPolicies = map[string]string{}
+	err := LoadTagConflictPolicies("../../../tests/tag_conflicts/yor_config.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{"owner": KeepExistingPolicy, "team": FirstGroupWinsPolicy, "cost_center": FailPolicy}, TagConflictPolicies)
+}
@@ -0,0 +1,150 @@
+package structure
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"gopkg.in/yaml.v2"
+)
+
+// ResourceTypeTagLimits maps the resource types which allow fewer tags than the other resources of their cloud
+// provider to the maximal number of their tags
+var ResourceTypeTagLimits = map[string]int{
+	"aws_s3_object":        10,
+	"aws_s3_bucket_object": 10,
+	"aws_s3_object_copy":   10,
+	"aws_db_proxy":         10,
+	"AWS::RDS::DBProxy":    10,
+}
+
+// ProviderTagLimits maps the cloud providers to the maximal number of tags of their resources
+var ProviderTagLimits = map[string]int{
+	AWSProvider:   50,
+	AzureProvider: 50,
+	GCPProvider:   64,
+}
+
+// TagLimit is the maximal number of tags of a block, and the resource type or cloud provider it is the limit of
+type TagLimit struct {
+	MaxTags  int
+	Scope    string
+	Provider string
+}
+
+// ITagLimitBlock is implemented by blocks whose new tags can be truncated to the tag limit of their resource
+type ITagLimitBlock interface {
+	ApplyTagLimit(limit *TagLimit)
+}
+
+// GetTagLimit returns the tag limit of the resource type of the block, or else of its cloud provider, or nil if its
+// number of tags is not limited
+func GetTagLimit(block IBlock) *TagLimit {
+	provider := GetCloudProvider(block)
+	if maxTags, ok := ResourceTypeTagLimits[block.GetResourceType()]; ok {
+		return &TagLimit{MaxTags: maxTags, Scope: block.GetResourceType(), Provider: provider}
+	}
+	if maxTags, ok := ProviderTagLimits[provider]; ok {
+		return &TagLimit{MaxTags: maxTags, Scope: provider, Provider: provider}
+	}
+	return nil
+}
+
+// LoadTagLimits adds the limits of the tag_limits section of the config file to ResourceTypeTagLimits and
+// ProviderTagLimits, or overrides the built-in limits of the same resource types and providers
+func LoadTagLimits(configFilePath string) error {
+	// #nosec G304
+	confBytes, err := os.ReadFile(configFilePath)
+	if err != nil {
+		return err
+	}
+	config := struct {
+		TagLimits struct {
+			Providers     map[string]int `yaml:"providers"`
+			ResourceTypes map[string]int `yaml:"resource_types"`
+		} `yaml:"tag_limits"`
+	}{}
+	if err = yaml.Unmarshal(confBytes, &config); err != nil {
+		return err
+	}
+	for provider, maxTags := range config.TagLimits.Providers {
+		if maxTags < 0 {
+			return fmt.Errorf("the tag limit of provider %v is negative", provider)
+		}
+		ProviderTagLimits[provider] = maxTags
+	}
+	for resourceType, maxTags := range config.TagLimits.ResourceTypes {
+		if maxTags < 0 {
+			return fmt.Errorf("the tag limit of resource type %v is negative", resourceType)
+		}
+		ResourceTypeTagLimits[resourceType] = maxTags
+	}
+	return nil
+}
+
+// ApplyTagLimit drops the new tags which would exceed the tag limit of the block, starting with the tags of the lowest
+// priority, and tags of the same priority in key order. Existing tags and updates of them are never dropped.
+// Every dropped tag is kept once as a violation for the report.
+func (b *Block) ApplyTagLimit(limit *TagLimit) {
+	if limit == nil {
+		return
+	}
+	existingKeys := make(map[string]bool)
+	for _, tag := range b.ExitingTags {
+		existingKeys[tag.GetKey()] = true
+	}
+	var addedTags []tags.ITag
+	addedKeys := make(map[string]bool)
+	for _, tag := range b.NewTags {
+		if !existingKeys[tag.GetKey()] {
+			addedTags = append(addedTags, tag)
+			addedKeys[tag.GetKey()] = true
+		}
+	}
+	if len(existingKeys)+len(addedKeys) <= limit.MaxTags {
+		return
+	}
+	sort.SliceStable(addedTags, func(i, j int) bool {
+		if addedTags[i].GetPriority() != addedTags[j].GetPriority() {
+			return addedTags[i].GetPriority() > addedTags[j].GetPriority()
+		}
+		return addedTags[i].GetKey() > addedTags[j].GetKey()
+	})
+	keptKeys := make(map[string]bool)
+	for _, tag := range addedTags {
+		if len(existingKeys)+len(keptKeys) >= limit.MaxTags {
+			break
+		}
+		keptKeys[tag.GetKey()] = true
+	}
+	var keptTags []tags.ITag
+	for _, tag := range b.NewTags {
+		if existingKeys[tag.GetKey()] || keptKeys[tag.GetKey()] {
+			keptTags = append(keptTags, tag)
+			continue
+		}
+		violation := TagViolation{
+			Provider:   limit.Provider,
+			Key:        tag.GetKey(),
+			Value:      tag.GetValue(),
+			Violation:  fmt.Sprintf("more than %d tags of %v resources", limit.MaxTags, limit.Scope),
+			Resolution: TagDroppedResolution,
+		}
+		if !b.hasTagViolation(violation) {
+			b.AddTagViolation(violation)
+		}
+	}
+	b.NewTags = keptTags
+}
+
+// hasTagViolation returns whether the violation is already kept, e.g. as a tag was dropped before a tag group added it
+// again
+func (b *Block) hasTagViolation(violation TagViolation) bool {
+	for _, tagViolation := range b.TagViolations {
+		if tagViolation == violation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package structure
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTagLimit(t *testing.T) {
+	t.Run("resource type limits take precedence over provider limits", func(t *testing.T) {
+		assert.Equal(t, &TagLimit{MaxTags: 10, Scope: "aws_s3_object", Provider: AWSProvider}, GetTagLimit(&Block{Type: "aws_s3_object"}))
+		assert.Equal(t, &TagLimit{MaxTags: 50, Scope: AWSProvider, Provider: AWSProvider}, GetTagLimit(&Block{Type: "aws_s3_bucket"}))
+		assert.Equal(t, &TagLimit{MaxTags: 50, Scope: AzureProvider, Provider: AzureProvider}, GetTagLimit(&Block{Type: "azurerm_storage_account"}))
+		assert.Equal(t, &TagLimit{MaxTags: 64, Scope: GCPProvider, Provider: GCPProvider}, GetTagLimit(&Block{Type: "google_storage_bucket"}))
+		assert.Nil(t, GetTagLimit(&Block{Type: "Deployment"}))
+	})
+
+	t.Run("limits are loaded from the config file", func(t *testing.T) {
+		defer func(providerTagLimits map[string]int, resourceTypeTagLimits map[string]int) {
+			ProviderTagLimits, ResourceTypeTagLimits = providerTagLimits, resourceTypeTagLimits
+		}(copyLimits(ProviderTagLimits), copyLimits(ResourceTypeTagLimits))
+		err := LoadTagLimits("../../../tests/tag_limits/yor_config.yml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, &TagLimit{MaxTags: 40, Scope: AWSProvider, Provider: AWSProvider}, GetTagLimit(&Block{Type: "aws_s3_bucket"}))
+		assert.Equal(t, &TagLimit{MaxTags: 20, Scope: "aws_lambda_function", Provider: AWSProvider}, GetTagLimit(&Block{Type: "aws_lambda_function"}))
+		assert.Equal(t, &TagLimit{MaxTags: 10, Scope: "aws_s3_object", Provider: AWSProvider}, GetTagLimit(&Block{Type: "aws_s3_object"}))
+	})
+}
+
+func copyLimits(limits map[string]int) map[string]int {
+	limitsCopy := make(map[string]int, len(limits))
+	for key, limit := range limits {
+		limitsCopy[key] = limit
+	}
+	return limitsCopy
+}
+
+func TestApplyTagLimit(t *testing.T) {
+	t.Run("tags of the lowest priority are dropped", func(t *testing.T) {
+		b := &Block{
+			Name: "aws_s3_object.readme",
+			Type: "aws_s3_object",
+			ExitingTags: []tags.ITag{
+				&tags.Tag{Key: "env", Value: "dev"},
+				&tags.Tag{Key: "team", Value: "data"},
+			},
+		}
+		b.AddNewTags([]tags.ITag{
+			&tags.Tag{Key: "env", Value: "prod"},
+			&tags.Tag{Key: "yor_trace", Value: "4c5d6e7f-8091-4a2b-b3c4-d5e6f7a8b9c0", Priority: tags.YorTraceTagPriority},
+			&tags.Tag{Key: "yor_name", Value: "readme", Priority: tags.YorNameTagPriority},
+			&tags.Tag{Key: "owner", Value: "data-platform"},
+			&tags.Tag{Key: "cost_center", Value: "1234"},
+		})
+		var gitTags []tags.ITag
+		for _, key := range []string{"git_commit", "git_file", "git_last_modified_at", "git_last_modified_by", "git_modifiers", "git_org", "git_repo"} {
+			gitTags = append(gitTags, &tags.Tag{Key: key, Value: key + "_val", Priority: tags.GitTagPriority})
+		}
+		b.AddNewTags(gitTags)
+		b.ApplyTagLimit(GetTagLimit(b))
+
+		var keys []string
+		for _, tag := range b.MergeTags() {
+			keys = append(keys, tag.GetKey())
+		}
+		assert.ElementsMatch(t, []string{"env", "team", "yor_trace", "yor_name", "owner", "cost_center", "git_repo", "git_org", "git_modifiers", "git_last_modified_by"}, keys)
+		var violations []string
+		for _, violation := range b.GetTagViolations() {
+			assert.Equal(t, "more than 10 tags of aws_s3_object resources", violation.Violation)
+			assert.Equal(t, TagDroppedResolution, violation.Resolution)
+			assert.Equal(t, AWSProvider, violation.Provider)
+			violations = append(violations, fmt.Sprintf("%v=%v", violation.Key, violation.Value))
+		}
+		assert.ElementsMatch(t, []string{"git_last_modified_at=git_last_modified_at_val", "git_file=git_file_val", "git_commit=git_commit_val"}, violations)
+	})
+
+	t.Run("limit of validated tags is applied once all the tag groups added them", func(t *testing.T) {
+		b := &Block{Name: "aws_s3_object.readme", Type: "aws_s3_object"}
+		b.SetNewTagsValidator(func(newTags []tags.ITag) []tags.ITag { return newTags })
+		var gitTags []tags.ITag
+		for i := 0; i < 10; i++ {
+			gitTags = append(gitTags, &tags.Tag{Key: fmt.Sprintf("git_tag%d", i), Value: "value", Priority: tags.GitTagPriority})
+		}
+		b.AddNewTags(gitTags)
+		assert.Equal(t, 10, len(b.GetNewTags()))
+		b.AddNewTags([]tags.ITag{&tags.Tag{Key: "yor_trace", Value: "4c5d6e7f-8091-4a2b-b3c4-d5e6f7a8b9c0", Priority: tags.YorTraceTagPriority}})
+		b.ApplyTagLimit(GetTagLimit(b))
+		b.ApplyTagLimit(GetTagLimit(b))
+
+		var keys []string
+		for _, tag := range b.GetNewTags() {
+			keys = append(keys, tag.GetKey())
+		}
+		assert.Contains(t, keys, "yor_trace")
+		assert.Equal(t, 10, len(keys))
+		assert.Equal(t, []TagViolation{
+			{Provider: AWSProvider, Key: "git_tag0", Value: "value", Violation: "more than 10 tags of aws_s3_object resources", Resolution: TagDroppedResolution},
+		}, b.GetTagViolations())
+	})
+
+	t.Run("tags dropped again are reported once", func(t *testing.T) {
+		b := &Block{Name: "aws_s3_object.readme", Type: "aws_s3_object"}
+		for i := 0; i < 10; i++ {
+			b.ExitingTags = append(b.ExitingTags, &tags.Tag{Key: fmt.Sprintf("tag%d", i), Value: "value"})
+		}
+		b.AddNewTags([]tags.ITag{&tags.Tag{Key: "owner", Value: "data"}})
+		b.AddNewTags([]tags.ITag{&tags.Tag{Key: "owner", Value: "data"}})
+		assert.Empty(t, b.GetNewTags())
+		assert.Equal(t, 1, len(b.GetTagViolations()))
+	})
+
+	t.Run("updates of existing tags are kept", func(t *testing.T) {
+		b := &Block{Name: "aws_s3_object.readme", Type: "aws_s3_object"}
+		for i := 0; i < 10; i++ {
+			b.ExitingTags = append(b.ExitingTags, &tags.Tag{Key: fmt.Sprintf("tag%d", i), Value: "value"})
+		}
+		b.AddNewTags([]tags.ITag{
+			&tags.Tag{Key: "tag0", Value: "updated"},
+			&tags.Tag{Key: "yor_trace", Value: "4c5d6e7f-8091-4a2b-b3c4-d5e6f7a8b9c0", Priority: tags.YorTraceTagPriority},
+		})
+		b.ApplyTagLimit(GetTagLimit(b))
+		assert.Equal(t, []tags.ITag{&tags.Tag{Key: "tag0", Value: "updated"}}, b.GetNewTags())
+		assert.Equal(t, 1, len(b.GetTagViolations()))
+	})
+
+	t.Run("tags within the limit are not dropped", func(t *testing.T) {
+		b := &Block{Name: "aws_s3_bucket.data", Type: "aws_s3_bucket"}
+		b.AddNewTags([]tags.ITag{&tags.Tag{Key: "yor_trace", Value: "4c5d6e7f-8091-4a2b-b3c4-d5e6f7a8b9c0"}})
+		b.ApplyTagLimit(GetTagLimit(b))
+		assert.Equal(t, 1, len(b.GetNewTags()))
+		assert.Empty(t, b.GetTagViolations())
+	})
+}
@@ -0,0 +1,214 @@
+package structure
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"gopkg.in/yaml.v2"
+)
+
+// TagMigrations are the rules of removing and renaming the existing tags of the resources, for changes of the tagging
+// convention such as renaming git_repo to source_repository
+type TagMigrations struct {
+	// Rename maps the keys of the renamed tags to their new keys
+	Rename map[string]string `yaml:"rename"`
+	Remove []string          `yaml:"remove"`
+}
+
+// TagMigrationRules are the rules of the tag_migrations section of the config file and the flags of the migrate command
+var TagMigrationRules = &TagMigrations{Rename: map[string]string{}}
+
+// ITagMigrationBlock is implemented by blocks whose parser can remove and rename their existing tags in their file
+type ITagMigrationBlock interface {
+	MigrateTags(migrations *TagMigrations)
+}
+
+// LoadTagMigrations adds the rules of the tag_migrations section of the config file to TagMigrationRules
+func LoadTagMigrations(configFilePath string) error {
+	// #nosec G304
+	confBytes, err := os.ReadFile(configFilePath)
+	if err != nil {
+		return err
+	}
+	config := struct {
+		TagMigrations TagMigrations `yaml:"tag_migrations"`
+	}{}
+	if err = yaml.Unmarshal(confBytes, &config); err != nil {
+		return err
+	}
+	prevKeys := make([]string, 0, len(config.TagMigrations.Rename))
+	for prevKey := range config.TagMigrations.Rename {
+		prevKeys = append(prevKeys, prevKey)
+	}
+	sort.Strings(prevKeys)
+	for _, prevKey := range prevKeys {
+		if err = TagMigrationRules.addRename(prevKey, config.TagMigrations.Rename[prevKey]); err != nil {
+			return err
+		}
+	}
+	return AddTagRemovals(config.TagMigrations.Remove)
+}
+
+// AddTagRenames adds renames written as old_key=new_key, such as the values of the --rename flag, to TagMigrationRules
+func AddTagRenames(renames []string) error {
+	for _, rename := range renames {
+		prevKey, newKey, found := strings.Cut(rename, "=")
+		if !found {
+			return fmt.Errorf("invalid tag rename %v, expected old_key=new_key", rename)
+		}
+		if err := TagMigrationRules.addRename(strings.TrimSpace(prevKey), strings.TrimSpace(newKey)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddTagRemovals adds the keys of removed tags to TagMigrationRules
+func AddTagRemovals(keys []string) error {
+	for _, key := range keys {
+		if _, ok := TagMigrationRules.Rename[key]; ok || TagMigrationRules.isRenameTarget(key) {
+			return fmt.Errorf("tag %v is both removed and renamed", key)
+		}
+		if !TagMigrationRules.isRemoved(key) {
+			TagMigrationRules.Remove = append(TagMigrationRules.Remove, key)
+		}
+	}
+	return nil
+}
+
+// addRename adds a rename of a tag key. Renames are not chained, so a new key cannot be renamed or removed itself.
+func (m *TagMigrations) addRename(prevKey string, newKey string) error {
+	if m.Rename == nil {
+		m.Rename = make(map[string]string)
+	}
+	switch existingNewKey, isRenamed := m.Rename[prevKey]; {
+	case prevKey == "" || newKey == "":
+		return fmt.Errorf("tag rename %v=%v has an empty key", prevKey, newKey)
+	case prevKey == newKey:
+		return fmt.Errorf("tag %v is renamed to itself", prevKey)
+	case isRenamed && existingNewKey != newKey:
+		return fmt.Errorf("tag %v is renamed to both %v and %v", prevKey, existingNewKey, newKey)
+	case m.isRemoved(prevKey) || m.isRemoved(newKey):
+		return fmt.Errorf("tag %v is both removed and renamed", prevKey)
+	}
+	if renamedNewKey, ok := m.Rename[newKey]; ok {
+		return fmt.Errorf("tag %v is renamed to %v, which is renamed to %v as well", prevKey, newKey, renamedNewKey)
+	}
+	if m.isRenameTarget(prevKey) {
+		return fmt.Errorf("tag %v is renamed to %v, after other tags are renamed to it", prevKey, newKey)
+	}
+	m.Rename[prevKey] = newKey
+	return nil
+}
+
+// IsEmpty returns true if there are no rules to remove or rename tags
+func (m *TagMigrations) IsEmpty() bool {
+	return m == nil || len(m.Rename) == 0 && len(m.Remove) == 0
+}
+
+// IsMigrated returns true if the tag key is removed or renamed by the rules
+func (m *TagMigrations) IsMigrated(key string) bool {
+	_, isRenamed := m.Rename[key]
+	return isRenamed || m.isRemoved(key)
+}
+
+func (m *TagMigrations) isRemoved(key string) bool {
+	for _, removedKey := range m.Remove {
+		if removedKey == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *TagMigrations) isRenameTarget(key string) bool {
+	for _, newKey := range m.Rename {
+		if newKey == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyTagMigrations removes and renames the given existing tags of the block, which its parser can migrate, by the
+// migration rules. The removed and renamed keys are then part of the tags diff of the block. New tags of the removed
+// and renamed keys are dropped or renamed as well, so the tag groups do not add the old keys again. A renamed tag is
+// removed instead if the block already has the new key. Protected tags are never migrated.
+func (b *Block) ApplyTagMigrations(migrations *TagMigrations, migratableTags []tags.ITag) {
+	if migrations.IsEmpty() {
+		return
+	}
+	existingKeys := make(map[string]bool)
+	for _, tag := range b.ExitingTags {
+		existingKeys[tag.GetKey()] = true
+	}
+	for _, tag := range migratableTags {
+		key := tag.GetKey()
+		newKey, isRenamed := migrations.Rename[key]
+		if !isRenamed && !migrations.isRemoved(key) {
+			continue
+		}
+		if IsProtectedTag(key) {
+			continue
+		}
+		if isRenamed && !existingKeys[newKey] {
+			b.RenamedTags = append(b.RenamedTags, &tags.TagRename{PrevKey: key, NewKey: newKey, Value: tag.GetValue()})
+		} else {
+			b.RemovedTags = append(b.RemovedTags, tag)
+		}
+	}
+
+	newKeys := make(map[string]bool)
+	for _, tag := range b.NewTags {
+		newKeys[tag.GetKey()] = true
+	}
+	migratedNewTags := make([]tags.ITag, 0, len(b.NewTags))
+	for _, tag := range b.NewTags {
+		if migrations.isRemoved(tag.GetKey()) {
+			continue
+		}
+		if newKey, ok := migrations.Rename[tag.GetKey()]; ok {
+			if !newKeys[newKey] {
+				migratedNewTags = append(migratedNewTags, &tags.Tag{Key: newKey, Value: tag.GetValue(), Priority: tag.GetPriority(), IsTemplate: tags.IsTemplateTag(tag)})
+				newKeys[newKey] = true
+			}
+			continue
+		}
+		migratedNewTags = append(migratedNewTags, tag)
+	}
+	sort.SliceStable(migratedNewTags, func(i, j int) bool {
+		return migratedNewTags[i].GetKey() > migratedNewTags[j].GetKey()
+	})
+	b.NewTags = migratedNewTags
+}
+
+// getMigratedTags returns the existing tags of the block without its removed tags, and with the new keys of its renamed
+// tags in their place
+func (b *Block) getMigratedTags() []tags.ITag {
+	if len(b.RemovedTags) == 0 && len(b.RenamedTags) == 0 {
+		return b.ExitingTags
+	}
+	removedKeys := make(map[string]bool)
+	for _, tag := range b.RemovedTags {
+		removedKeys[tag.GetKey()] = true
+	}
+	renames := make(map[string]*tags.TagRename)
+	for _, rename := range b.RenamedTags {
+		renames[rename.PrevKey] = rename
+	}
+	migratedTags := make([]tags.ITag, 0, len(b.ExitingTags))
+	for _, tag := range b.ExitingTags {
+		if removedKeys[tag.GetKey()] {
+			continue
+		}
+		if rename, ok := renames[tag.GetKey()]; ok {
+			migratedTags = append(migratedTags, &tags.Tag{Key: rename.NewKey, Value: rename.Value})
+			continue
+		}
+		migratedTags = append(migratedTags, tag)
+	}
+	return migratedTags
+}
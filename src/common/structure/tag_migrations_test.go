@@ -0,0 +1,123 @@
+package structure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagMigrationRules(t *testing.T) {
+	t.Run("rules are loaded from the config file and the flags", func(t *testing.T) {
+		defer func(migrations *TagMigrations) {
+			TagMigrationRules = migrations
+		}(TagMigrationRules)
+		TagMigrationRules = &TagMigrations{Rename: map[string]string{}}
+		configFile := filepath.Join(t.TempDir(), "yor_config.yml")
+		config := "tag_migrations:\n  rename:\n    git_repo: source_repository\n  remove:\n    - git_modifiers\n"
+		if err := os.WriteFile(configFile, []byte(config), 0600); err != nil {
+			t.Fatal(err)
+		}
+		assert.Nil(t, LoadTagMigrations(configFile))
+		assert.Nil(t, AddTagRenames([]string{"git_file = source_file"}))
+		assert.Nil(t, AddTagRemovals([]string{"git_modifiers", "yor_name"}))
+		assert.Equal(t, &TagMigrations{
+			Rename: map[string]string{"git_repo": "source_repository", "git_file": "source_file"},
+			Remove: []string{"git_modifiers", "yor_name"},
+		}, TagMigrationRules)
+	})
+
+	t.Run("conflicting rules fail", func(t *testing.T) {
+		defer func(migrations *TagMigrations) {
+			TagMigrationRules = migrations
+		}(TagMigrationRules)
+		TagMigrationRules = &TagMigrations{Rename: map[string]string{"git_repo": "source_repository"}, Remove: []string{"git_modifiers"}}
+		assert.EqualError(t, AddTagRenames([]string{"git_repo"}), "invalid tag rename git_repo, expected old_key=new_key")
+		assert.EqualError(t, AddTagRenames([]string{"git_repo="}), "tag rename git_repo= has an empty key")
+		assert.EqualError(t, AddTagRenames([]string{"env=env"}), "tag env is renamed to itself")
+		assert.EqualError(t, AddTagRenames([]string{"git_repo=repo"}), "tag git_repo is renamed to both source_repository and repo")
+		assert.EqualError(t, AddTagRenames([]string{"git_modifiers=modifiers"}), "tag git_modifiers is both removed and renamed")
+		assert.EqualError(t, AddTagRenames([]string{"repo=git_repo"}), "tag repo is renamed to git_repo, which is renamed to source_repository as well")
+		assert.EqualError(t, AddTagRenames([]string{"source_repository=repo"}), "tag source_repository is renamed to repo, after other tags are renamed to it")
+		assert.EqualError(t, AddTagRemovals([]string{"source_repository"}), "tag source_repository is both removed and renamed")
+	})
+}
+
+func TestApplyTagMigrations(t *testing.T) {
+	migrations := &TagMigrations{
+		Rename: map[string]string{"git_repo": "source_repository", "git_file": "source_file"},
+		Remove: []string{"git_modifiers"},
+	}
+
+	t.Run("existing tags are removed and renamed in the tags diff", func(t *testing.T) {
+		gitModifiers := &tags.Tag{Key: "git_modifiers", Value: "alice"}
+		sourceFile := &tags.Tag{Key: "source_file", Value: "main.tf"}
+		gitFile := &tags.Tag{Key: "git_file", Value: "old/main.tf"}
+		b := &Block{
+			Name: "aws_s3_bucket.data",
+			ExitingTags: []tags.ITag{
+				&tags.Tag{Key: "env", Value: "prod"},
+				&tags.Tag{Key: "git_repo", Value: "yor"},
+				gitModifiers,
+				sourceFile,
+				gitFile,
+			},
+		}
+		b.AddNewTags([]tags.ITag{&tags.Tag{Key: "env", Value: "staging"}})
+		b.ApplyTagMigrations(migrations, b.ExitingTags)
+
+		diff := b.CalculateTagsDiff()
+		// git_file is removed, as the resource already has its new key
+		assert.Equal(t, []tags.ITag{gitModifiers, gitFile}, diff.Removed)
+		assert.Equal(t, []*tags.TagRename{{PrevKey: "git_repo", NewKey: "source_repository", Value: "yor"}}, diff.Renamed)
+		assert.Equal(t, []*tags.TagDiff{{Key: "env", PrevValue: "prod", NewValue: "staging"}}, diff.Updated)
+		assert.Empty(t, diff.Added)
+		assert.False(t, diff.IsEmpty())
+		assert.ElementsMatch(t, []tags.ITag{
+			&tags.Tag{Key: "env", Value: "staging"},
+			&tags.Tag{Key: "source_repository", Value: "yor"},
+			sourceFile,
+		}, b.MergeTags())
+	})
+
+	t.Run("new tags are removed and renamed", func(t *testing.T) {
+		b := &Block{
+			Name:        "aws_s3_bucket.data",
+			ExitingTags: []tags.ITag{&tags.Tag{Key: "git_repo", Value: "yor"}},
+		}
+		b.AddNewTags([]tags.ITag{
+			&tags.Tag{Key: "git_repo", Value: "yor", Priority: tags.GitTagPriority},
+			&tags.Tag{Key: "git_modifiers", Value: "alice"},
+			&tags.Tag{Key: "git_file", Value: "main.tf"},
+		})
+		b.ApplyTagMigrations(migrations, b.ExitingTags)
+
+		assert.Equal(t, []tags.ITag{
+			&tags.Tag{Key: "source_repository", Value: "yor", Priority: tags.GitTagPriority},
+			&tags.Tag{Key: "source_file", Value: "main.tf"},
+		}, b.GetNewTags())
+		diff := b.CalculateTagsDiff()
+		assert.Equal(t, []tags.ITag{&tags.Tag{Key: "source_file", Value: "main.tf"}}, diff.Added)
+		assert.Empty(t, diff.Updated)
+	})
+
+	t.Run("protected and unmigratable tags are not migrated", func(t *testing.T) {
+		defer func(patterns []string) {
+			ProtectedTagPatterns = patterns
+		}(ProtectedTagPatterns)
+		ProtectedTagPatterns = []string{"git_modifiers"}
+		b := &Block{
+			Name: "aws_s3_bucket.data",
+			ExitingTags: []tags.ITag{
+				&tags.Tag{Key: "git_repo", Value: "yor"},
+				&tags.Tag{Key: "git_modifiers", Value: "alice"},
+			},
+		}
+		b.ApplyTagMigrations(migrations, b.ExitingTags[1:])
+
+		assert.True(t, b.CalculateTagsDiff().IsEmpty())
+		assert.Equal(t, b.ExitingTags, b.MergeTags())
+	})
+}
@@ -23,9 +23,14 @@ func (t *YorNameTag) CalculateValue(block interface{}) (tags.ITag, error) {
 		return nil, fmt.Errorf("failed to convert data to IBlock, which is required to calculte tag value. Type of data: %s", reflect.TypeOf(block))
 	}
 
-	return &tags.Tag{Key: t.Key, Value: blockVal.GetResourceName()}, nil
+	// the names of blocks with multiple instances may be templates of the name of each instance, e.g. "bucket-${each.key}"
+	return &tags.Tag{Key: t.Key, Value: blockVal.GetResourceName(), Priority: t.GetPriority(), IsTemplate: true}, nil
 }
 
 func (t *YorNameTag) GetDescription() string {
 	return "A tag that states the resource name in the IaC config file"
 }
+
+func (t *YorNameTag) GetPriority() int {
+	return tags.YorNameTagPriority
+}
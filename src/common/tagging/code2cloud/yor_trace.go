@@ -21,9 +21,13 @@ func (t *YorTraceTag) CalculateValue(_ interface{}) (tags.ITag, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create a new uuidv4")
 	}
-	return &tags.Tag{Key: t.Key, Value: uuidv4.String()}, nil
+	return &tags.Tag{Key: t.Key, Value: uuidv4.String(), Priority: t.GetPriority()}, nil
 }
 
 func (t *YorTraceTag) GetDescription() string {
 	return "A UUID tag that allows easily finding the root IaC config of the resource"
 }
+
+func (t *YorTraceTag) GetPriority() int {
+	return tags.YorTraceTagPriority
+}
@@ -181,7 +181,7 @@ func (t *TagGroup) CreateTagsForBlock(block structure.IBlock) error {
 }
 
 func (t *TagGroup) CalculateTagValue(block structure.IBlock, tag Tag) (tags.ITag, error) {
-	var retTag = &tags.Tag{}
+	var retTag = &tags.Tag{IsTemplate: true}
 	if !tag.SatisfyFilters(block) {
 		return nil, nil
 	}
@@ -250,6 +250,7 @@ func (t *TagGroup) CalculateTagValue(block structure.IBlock, tag Tag) (tags.ITag
 		} else if t.useCodeOwners && len(gitModifiersCounts) > 1 {
 			if res, found := t.getSectionFromCodeOwners(block); found {
 				retTag.Value = res
+				retTag.IsTemplate = false
 			}
 		}
 		return retTag, nil
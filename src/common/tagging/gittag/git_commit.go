@@ -26,11 +26,15 @@ func (t *GitCommitTag) CalculateValue(data interface{}) (tags.ITag, error) {
 
 	latestCommit := gitBlame.GetLatestCommit()
 	if latestCommit == nil || latestCommit.Hash.IsZero() {
-		return &tags.Tag{Key: t.Key, Value: CommitUnavailable}, nil
+		return &tags.Tag{Key: t.Key, Value: CommitUnavailable, Priority: t.GetPriority()}, nil
 	}
-	return &tags.Tag{Key: t.Key, Value: latestCommit.Hash.String()}, nil
+	return &tags.Tag{Key: t.Key, Value: latestCommit.Hash.String(), Priority: t.GetPriority()}, nil
 }
 
 func (t *GitCommitTag) GetDescription() string {
 	return "The hash of the latest commit which edited this resource"
 }
+
+func (t *GitCommitTag) GetPriority() int {
+	return tags.GitTagPriority
+}
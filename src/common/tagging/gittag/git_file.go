@@ -21,9 +21,13 @@ func (t *GitFileTag) CalculateValue(data interface{}) (tags.ITag, error) {
 	if !ok {
 		return nil, fmt.Errorf("failed to convert data to *GitBlame, which is required to calculte tag value. Type of data: %s", reflect.TypeOf(data))
 	}
-	return &tags.Tag{Key: t.Key, Value: gitBlame.FilePath}, nil
+	return &tags.Tag{Key: t.Key, Value: gitBlame.FilePath, Priority: t.GetPriority()}, nil
 }
 
 func (t *GitFileTag) GetDescription() string {
 	return "The file (including path) in the repository where this resource is provisioned in IaC"
 }
+
+func (t *GitFileTag) GetPriority() int {
+	return tags.GitTagPriority
+}
@@ -25,9 +25,13 @@ func (t *GitLastModifiedAtTag) CalculateValue(data interface{}) (tags.ITag, erro
 	if latestCommit == nil {
 		return nil, fmt.Errorf("latest commit is unavailable")
 	}
-	return &tags.Tag{Key: t.Key, Value: latestCommit.Date.UTC().Format("2006-01-02 15:04:05")}, nil
+	return &tags.Tag{Key: t.Key, Value: latestCommit.Date.UTC().Format("2006-01-02 15:04:05"), Priority: t.GetPriority()}, nil
 }
 
 func (t *GitLastModifiedAtTag) GetDescription() string {
 	return "The last time this resource's configuration was modified"
 }
+
+func (t *GitLastModifiedAtTag) GetPriority() int {
+	return tags.GitTagPriority
+}
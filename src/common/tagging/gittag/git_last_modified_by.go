@@ -26,9 +26,13 @@ func (t *GitLastModifiedByTag) CalculateValue(data interface{}) (tags.ITag, erro
 	if latestCommit == nil {
 		return nil, fmt.Errorf("latest commit is unavailable")
 	}
-	return &tags.Tag{Key: t.Key, Value: latestCommit.Author}, nil
+	return &tags.Tag{Key: t.Key, Value: latestCommit.Author, Priority: t.GetPriority()}, nil
 }
 
 func (t *GitLastModifiedByTag) GetDescription() string {
 	return "The last user who modified this resource"
 }
+
+func (t *GitLastModifiedByTag) GetPriority() int {
+	return tags.GitTagPriority
+}
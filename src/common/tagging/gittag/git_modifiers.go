@@ -35,9 +35,13 @@ func (t *GitModifiersTag) CalculateValue(data interface{}) (tags.ITag, error) {
 
 	sort.Strings(modifyingUsers)
 
-	return &tags.Tag{Key: t.Key, Value: strings.Join(modifyingUsers, "/")}, nil
+	return &tags.Tag{Key: t.Key, Value: strings.Join(modifyingUsers, "/"), Priority: t.GetPriority()}, nil
 }
 
 func (t *GitModifiersTag) GetDescription() string {
 	return "The users who modified this resource"
 }
+
+func (t *GitModifiersTag) GetPriority() int {
+	return tags.GitTagPriority
+}
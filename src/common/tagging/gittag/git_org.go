@@ -21,9 +21,13 @@ func (t *GitOrgTag) CalculateValue(data interface{}) (tags.ITag, error) {
 	if !ok {
 		return nil, fmt.Errorf("failed to convert data to *GitBlame, which is required to calculte tag value. Type of data: %s", reflect.TypeOf(data))
 	}
-	return &tags.Tag{Key: t.Key, Value: gitBlame.GitOrg}, nil
+	return &tags.Tag{Key: t.Key, Value: gitBlame.GitOrg, Priority: t.GetPriority()}, nil
 }
 
 func (t *GitOrgTag) GetDescription() string {
 	return "The entity which owns the repository where this resource is provisioned in IaC"
 }
+
+func (t *GitOrgTag) GetPriority() int {
+	return tags.GitTagPriority
+}
@@ -21,9 +21,13 @@ func (t *GitRepoTag) CalculateValue(data interface{}) (tags.ITag, error) {
 	if !ok {
 		return nil, fmt.Errorf("failed to convert data to *GitBlame, which is required to calculte tag value. Type of data: %s", reflect.TypeOf(data))
 	}
-	return &tags.Tag{Key: t.Key, Value: gitBlame.GitRepository}, nil
+	return &tags.Tag{Key: t.Key, Value: gitBlame.GitRepository, Priority: t.GetPriority()}, nil
 }
 
 func (t *GitRepoTag) GetDescription() string {
 	return "The repository where this resource is provisioned in IaC"
 }
+
+func (t *GitRepoTag) GetPriority() int {
+	return tags.GitTagPriority
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bridgecrewio/yor/src/common/gitservice"
@@ -21,6 +22,9 @@ import (
 type TagGroup struct {
 	tagging.TagGroup
 	GitService *gitservice.GitService
+	// blameLinesByPath caches the blame lines of the blame paths of blocks, which the blocks of a directory share, e.g.
+	// the templates of a chart. Paths without a blame are cached as nil, so they are not blamed again either.
+	blameLinesByPath sync.Map
 }
 
 type fileLineMapper struct {
@@ -86,19 +90,21 @@ func (t *TagGroup) CreateTagsForBlock(block structure.IBlock) error {
 		return nil
 	}
 	t.updateBlameForOriginLines(block, blame, fileLinesMap.originToGit)
+	if blamePathsBlock, ok := block.(structure.IBlamePathsBlock); ok {
+		t.addPathsBlame(blame, blamePathsBlock.GetBlamePaths())
+	}
 	if !t.hasNonTagChanges(blame, block) {
 		return nil
 	}
-	err = t.UpdateBlockTags(block, blame)
-	if err != nil {
-		return err
-	}
+	newTags, err := t.CalculateTagValues(block, blame)
+	// the git tags have their own forms for GCP labels, which are more readable than the general sanitization of labels
 	if block.IsGCPBlock() {
-		for _, tag := range block.GetNewTags() {
+		for _, tag := range newTags {
 			t.cleanGCPTagValue(tag)
 		}
 	}
-	return nil
+	block.AddNewTags(newTags)
+	return err
 }
 
 func (t *TagGroup) getBlockLinesInGit(block structure.IBlock, linesMap fileLineMapper) structure.Lines {
@@ -192,6 +198,38 @@ func (t *TagGroup) updateBlameForOriginLines(block structure.IBlock, blame *gits
 	blame.BlamesByLine = newBlameByLines
 }
 
+// addPathsBlame adds the lines of the files to the blame of the block, after its own lines, so the git tags of the block
+// reflect the changes of these files as well. Files without a blame, e.g. uncommitted files, are skipped.
+func (t *TagGroup) addPathsBlame(blame *gitservice.GitBlame, paths []string) {
+	lastLine := 0
+	for line := range blame.BlamesByLine {
+		if line > lastLine {
+			lastLine = line
+		}
+	}
+	for _, path := range paths {
+		for _, line := range t.getPathBlameLines(path) {
+			lastLine++
+			blame.BlamesByLine[lastLine] = line
+		}
+	}
+}
+
+func (t *TagGroup) getPathBlameLines(path string) []*git.Line {
+	if lines, ok := t.blameLinesByPath.Load(path); ok {
+		return lines.([]*git.Line)
+	}
+	var lines []*git.Line
+	fileBlame, err := t.GitService.GetFileBlame(path)
+	if err != nil {
+		logger.Debug(fmt.Sprintf("Unable to get git blame for file %s: %s", path, err))
+	} else {
+		lines = fileBlame.Lines
+	}
+	t.blameLinesByPath.Store(path, lines)
+	return lines
+}
+
 func (t *TagGroup) hasNonTagChanges(blame *gitservice.GitBlame, block structure.IBlock) bool {
 	tagsLines := block.GetTagsLines()
 	latestBlame := blame.GetLatestCommit()
@@ -12,6 +12,8 @@ import (
 	"github.com/bridgecrewio/yor/src/common/tagging/tags"
 	"github.com/bridgecrewio/yor/src/common/utils"
 	"github.com/bridgecrewio/yor/tests/utils/blameutils"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -107,6 +109,61 @@ func TestGitTagGroupWithPrefix(t *testing.T) {
 		assert.True(t, strings.HasPrefix(tag.GetKey(), "prefix_"))
 	}
 }
+
+func TestGitTagGroupBlamePaths(t *testing.T) {
+	path := "../../../../tests/utils/blameutils/git_tagger_file.txt"
+	otherPath := "../../../../tests/helm/resources/web/templates/deployment.yaml"
+	latestDate, _ := blameutils.ExtractDate("2021-01-05T10:00:00.000Z")
+	var blameByFile sync.Map
+	blameByFile.Store(path, blameutils.SetupBlameResults(t, path, 3))
+	blameByFile.Store(otherPath, &git.BlameResult{Path: otherPath, Lines: []*git.Line{{
+		Author: "helm@example.com",
+		Text:   "apiVersion: apps/v1",
+		Date:   latestDate,
+		Hash:   plumbing.NewHash("3f2a1b"),
+	}}})
+	tagGroup := TagGroup{}
+	tagGroup.InitTagGroup("", nil, nil)
+	tagGroup.GitService = &gitservice.GitService{BlameByFile: &blameByFile}
+
+	block := &MockBlamePathsBlock{
+		MockTestBlock: MockTestBlock{Block: structure.Block{
+			FilePath:   path,
+			IsTaggable: true,
+			Lines:      structure.Lines{Start: 1, End: 3},
+		}},
+		blamePaths: []string{otherPath},
+	}
+	err := tagGroup.CreateTagsForBlock(block)
+	assert.NoError(t, err)
+	tagValues := make(map[string]string)
+	for _, tag := range block.NewTags {
+		tagValues[tag.GetKey()] = tag.GetValue()
+	}
+	// the latest change of the block is the change of the other file
+	assert.Equal(t, "helm@example.com", tagValues[tags.GitLastModifiedByTagKey])
+	assert.Contains(t, tagValues[tags.GitModifiersTagKey], "helm")
+	assert.Contains(t, tagValues[tags.GitModifiersTagKey], "schosterbarak")
+
+	// the other file is blamed once for all the blocks which share it
+	blameByFile.Delete(otherPath)
+	otherBlock := &MockBlamePathsBlock{
+		MockTestBlock: MockTestBlock{Block: structure.Block{
+			FilePath:   path,
+			IsTaggable: true,
+			Lines:      structure.Lines{Start: 1, End: 3},
+		}},
+		blamePaths: []string{otherPath},
+	}
+	err = tagGroup.CreateTagsForBlock(otherBlock)
+	assert.NoError(t, err)
+	tagValues = make(map[string]string)
+	for _, tag := range otherBlock.NewTags {
+		tagValues[tag.GetKey()] = tag.GetValue()
+	}
+	assert.Equal(t, "helm@example.com", tagValues[tags.GitLastModifiedByTagKey])
+}
+
 func TestCleanGCPTagValueWithTagPrefix(t *testing.T) {
 	tagGroup := TagGroup{}
 	tagGroup.Options.TagPrefix = "prefix_"
@@ -129,6 +186,15 @@ type MockTestBlock struct {
 	structure.Block
 }
 
+type MockBlamePathsBlock struct {
+	MockTestBlock
+	blamePaths []string
+}
+
+func (b *MockBlamePathsBlock) GetBlamePaths() []string {
+	return b.blamePaths
+}
+
 func (b *MockTestBlock) UpdateTags() {
 }
 
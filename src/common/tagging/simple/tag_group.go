@@ -39,7 +39,7 @@ func (t *TagGroup) InitTagGroup(_ string, skippedTags []string, explicitlySpecif
 	} else {
 		var envTags []tags.ITag
 		for key, value := range extraTagsFromArgs {
-			envTags = append(envTags, tags.Init(key, value))
+			envTags = append(envTags, &tags.Tag{Key: key, Value: value, IsTemplate: true})
 		}
 
 		t.SetTags(envTags)
@@ -49,6 +49,19 @@ func (t *TagGroup) InitTagGroup(_ string, skippedTags []string, explicitlySpecif
 func (t *TagGroup) GetDefaultTags() []tags.ITag {
 	return []tags.ITag{}
 }
+
+// GetStaticTags returns the tags of the group with their values, which are the same for all of the blocks
+func (t *TagGroup) GetStaticTags() []tags.ITag {
+	var staticTags []tags.ITag
+	for _, tag := range t.GetTags() {
+		tagVal, err := tag.CalculateValue(struct{}{})
+		if err == nil && tagVal != nil && tagVal.GetValue() != "" {
+			staticTags = append(staticTags, tagVal)
+		}
+	}
+	return staticTags
+}
+
 func (t *TagGroup) CreateTagsForBlock(block structure.IBlock) error {
 	return t.UpdateBlockTags(block, struct{}{})
 }
@@ -0,0 +1,247 @@
+package tagging
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bridgecrewio/yor/src/common/logger"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/bridgecrewio/yor/src/common/utils"
+)
+
+// TagConstraints are the rules the tags of the resources of a cloud provider must follow
+type TagConstraints struct {
+	MaxKeyLength   int
+	MaxValueLength int
+	// InvalidKeyChars and InvalidValueChars match the characters which are replaced with underscores when the tags are
+	// sanitized
+	InvalidKeyChars   *regexp.Regexp
+	InvalidValueChars *regexp.Regexp
+	// ValidKeyStart is matched by the beginning of valid keys, which sanitizing does not fix
+	ValidKeyStart *regexp.Regexp
+	// ReservedKeyPrefixes are the prefixes of the keys which the provider reserves for itself
+	ReservedKeyPrefixes []string
+	Lowercase           bool
+}
+
+// templates such as the terraform templates of tag values are only resolved when the resources are applied, so only
+// their literal parts are sanitized
+var templateRegex = regexp.MustCompile(`\$\{[^}]*}`)
+
+// ProviderTagConstraints maps the cloud providers to the constraints of the tags of their resources. Sources:
+// https://docs.aws.amazon.com/tag-editor/latest/userguide/tagging.html,
+// https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources#limitations and
+// https://cloud.google.com/compute/docs/labeling-resources#requirements
+var ProviderTagConstraints = map[string]*TagConstraints{
+	structure.AWSProvider: {
+		MaxKeyLength:        128,
+		MaxValueLength:      256,
+		InvalidKeyChars:     regexp.MustCompile(`[^\p{L}\p{Z}\p{N}_.:/=+\-@]`),
+		InvalidValueChars:   regexp.MustCompile(`[^\p{L}\p{Z}\p{N}_.:/=+\-@]`),
+		ReservedKeyPrefixes: []string{"aws:"},
+	},
+	structure.AzureProvider: {
+		MaxKeyLength:        512,
+		MaxValueLength:      256,
+		InvalidKeyChars:     regexp.MustCompile(`[<>%&\\?/]`),
+		ReservedKeyPrefixes: []string{"microsoft", "azure", "windows"},
+	},
+	structure.GCPProvider: {
+		MaxKeyLength:      63,
+		MaxValueLength:    63,
+		InvalidKeyChars:   utils.RemoveGcpInvalidChars,
+		InvalidValueChars: utils.RemoveGcpInvalidChars,
+		ValidKeyStart:     regexp.MustCompile(`^\p{Ll}`),
+		Lowercase:         true,
+	},
+}
+
+// getTagConstraints returns the tag constraints of the block and the provider they belong to, the constraints of its
+// cloud provider with the characters the provider of the block does not accept, if it has such characters
+func getTagConstraints(block structure.IBlock) (string, *TagConstraints) {
+	provider := structure.GetCloudProvider(block)
+	constraints := ProviderTagConstraints[provider]
+	charactersBlock, ok := block.(structure.ITagCharactersBlock)
+	if !ok {
+		return provider, constraints
+	}
+	invalidKeyChars, invalidValueChars := charactersBlock.GetInvalidTagCharacters()
+	if invalidKeyChars == nil && invalidValueChars == nil {
+		return provider, constraints
+	}
+	blockConstraints := &TagConstraints{}
+	if constraints != nil {
+		*blockConstraints = *constraints
+	} else {
+		provider = charactersBlock.GetTagProvider()
+	}
+	if invalidKeyChars != nil {
+		blockConstraints.InvalidKeyChars = invalidKeyChars
+	}
+	if invalidValueChars != nil {
+		blockConstraints.InvalidValueChars = invalidValueChars
+	}
+	return provider, blockConstraints
+}
+
+// ApplyTagConstraints returns the new tags of the block, sanitized to the tag constraints of the cloud provider of the
+// block. Tags which cannot be sanitized are dropped, and so are tags whose sanitized keys collide with the key of a tag of
+// a higher priority. Every violation is reported.
+func ApplyTagConstraints(block structure.IBlock, newTags []tags.ITag) []tags.ITag {
+	provider, constraints := getTagConstraints(block)
+	if constraints == nil {
+		return newTags
+	}
+	reportViolation := func(tag tags.ITag, violation string, resolution string) {
+		logger.Info(fmt.Sprintf("Tag %v of %v violates the %v tag constraints: %v, the tag is %v", tag.GetKey(), block.GetResourceID(), provider, violation, resolution))
+		if violationsBlock, ok := block.(structure.ITagViolationsBlock); ok {
+			violationsBlock.AddTagViolation(structure.TagViolation{
+				Provider:   provider,
+				Key:        tag.GetKey(),
+				Value:      tag.GetValue(),
+				Violation:  violation,
+				Resolution: resolution,
+			})
+		}
+	}
+	validTags := make([]tags.ITag, 0, len(newTags))
+	// the tags before they were sanitized, by the index of their sanitized tags
+	originalTags := make([]tags.ITag, 0, len(newTags))
+	for _, tag := range newTags {
+		originalTag := tag
+		key, keyViolations := constraints.sanitize(tag.GetKey(), constraints.InvalidKeyChars, constraints.MaxKeyLength, false)
+		value, valueViolations := constraints.sanitize(tag.GetValue(), constraints.InvalidValueChars, constraints.MaxValueLength, tags.IsTemplateTag(tag))
+		if reason := constraints.getInvalidKeyReason(key); reason != "" {
+			reportViolation(originalTag, reason, structure.TagDroppedResolution)
+			continue
+		}
+		if len(keyViolations) > 0 || len(valueViolations) > 0 {
+			var violations []string
+			for _, keyViolation := range keyViolations {
+				violations = append(violations, "key "+keyViolation)
+			}
+			for _, valueViolation := range valueViolations {
+				violations = append(violations, "value "+valueViolation)
+			}
+			reportViolation(originalTag, strings.Join(violations, ", "), fmt.Sprintf("%v to %v=%v", structure.TagSanitizedResolution, key, value))
+			if key != tag.GetKey() {
+				tag = &tags.Tag{Key: key, Value: value, Priority: tag.GetPriority(), IsTemplate: tags.IsTemplateTag(tag)}
+			} else {
+				tag.SetValue(value)
+			}
+		}
+		collisionIndex := getCollisionIndex(validTags, originalTags, key, originalTag.GetKey())
+		if collisionIndex < 0 {
+			validTags = append(validTags, tag)
+			originalTags = append(originalTags, originalTag)
+			continue
+		}
+		// the tag of the higher priority keeps the key, and the first of the tags of the same priority
+		violation := fmt.Sprintf("sanitized key %v is the key of another tag", key)
+		if tag.GetPriority() > validTags[collisionIndex].GetPriority() {
+			reportViolation(originalTags[collisionIndex], violation, structure.TagDroppedResolution)
+			validTags[collisionIndex] = tag
+			originalTags[collisionIndex] = originalTag
+		} else {
+			reportViolation(originalTag, violation, structure.TagDroppedResolution)
+		}
+	}
+	return validTags
+}
+
+// getCollisionIndex returns the index of the valid tag whose key is the sanitized key, if either of their keys was
+// sanitized to it, or -1. Tags of the same key are not sanitized to it, they are the tag conflicts of the block.
+func getCollisionIndex(validTags []tags.ITag, originalTags []tags.ITag, key string, originalKey string) int {
+	for i, validTag := range validTags {
+		if validTag.GetKey() == key && (originalKey != key || originalTags[i].GetKey() != key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// sanitize lowercases the text if needed, replaces its invalid characters and truncates it to the maximal length, and
+// returns the violations it fixed. Only the literal parts of templates are sanitized, and templates are not truncated,
+// as their values are not known.
+func (c *TagConstraints) sanitize(text string, invalidChars *regexp.Regexp, maxLength int, isTemplate bool) (string, []string) {
+	var violations []string
+	isTemplate = isTemplate && templateRegex.MatchString(text)
+	if c.Lowercase {
+		if lowercased := replaceLiterals(text, isTemplate, strings.ToLower); lowercased != text {
+			text = lowercased
+			violations = append(violations, "has uppercase characters")
+		}
+	}
+	if invalidChars != nil {
+		replaced := replaceLiterals(text, isTemplate, func(literal string) string {
+			return invalidChars.ReplaceAllString(literal, "_")
+		})
+		if replaced != text {
+			text = replaced
+			violations = append(violations, "has invalid characters")
+		}
+	}
+	if maxLength > 0 && !isTemplate && utf8.RuneCountInString(text) > maxLength {
+		text = string([]rune(text)[:maxLength])
+		violations = append(violations, fmt.Sprintf("is longer than %d characters", maxLength))
+	}
+	return text, violations
+}
+
+// replaceLiterals replaces the text, or only the parts of the text outside of its templates if it is a template
+func replaceLiterals(text string, isTemplate bool, replace func(string) string) string {
+	if !isTemplate {
+		return replace(text)
+	}
+	var sb strings.Builder
+	start := 0
+	for _, match := range templateRegex.FindAllStringIndex(text, -1) {
+		sb.WriteString(replace(text[start:match[0]]))
+		sb.WriteString(text[match[0]:match[1]])
+		start = match[1]
+	}
+	sb.WriteString(replace(text[start:]))
+	return sb.String()
+}
+
+// getInvalidKeyReason returns the reason a sanitized key is still not valid
+func (c *TagConstraints) getInvalidKeyReason(key string) string {
+	if strings.Trim(key, "_") == "" {
+		return "key has no valid characters"
+	}
+	for _, prefix := range c.ReservedKeyPrefixes {
+		if strings.HasPrefix(strings.ToLower(key), prefix) {
+			return fmt.Sprintf("key prefix %v is reserved", prefix)
+		}
+	}
+	if c.ValidKeyStart != nil && !c.ValidKeyStart.MatchString(key) {
+		return "key does not start with a lowercase letter"
+	}
+	return ""
+}
+
+// ValidateTagGroupTags makes the block sanitize the tags of every tag group to the tag constraints of the cloud provider
+// of the block before it adds them, so the tags of the tag groups, including the tag groups of plugins, conflict by their
+// sanitized keys. The tag limit of the block is then applied once, by ValidateNewTags.
+func ValidateTagGroupTags(block structure.IBlock) {
+	if validationBlock, ok := block.(structure.INewTagsValidationBlock); ok {
+		validationBlock.SetNewTagsValidator(func(newTags []tags.ITag) []tags.ITag {
+			return ApplyTagConstraints(block, newTags)
+		})
+	}
+}
+
+// ValidateNewTags validates the new tags of the block against the tag constraints of the cloud provider of the block,
+// such as the keys the tag migrations renamed them to, and truncates them to the tag limit of the block
+func ValidateNewTags(block structure.IBlock) {
+	if newTagsBlock, ok := block.(structure.INewTagsBlock); ok {
+		newTagsBlock.SetNewTags(ApplyTagConstraints(block, block.GetNewTags()))
+	}
+	// the cloud provider of some blocks, and so their tag limit, is known only by the block
+	if limitBlock, ok := block.(structure.ITagLimitBlock); ok {
+		limitBlock.ApplyTagLimit(structure.GetTagLimit(block))
+	}
+}
@@ -0,0 +1,116 @@
+package tagging
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyTagConstraints(t *testing.T) {
+	t.Run("aws tags are sanitized or dropped", func(t *testing.T) {
+		block := &structure.Block{Name: "aws_s3_bucket.data", Type: "aws_s3_bucket"}
+		validTags := ApplyTagConstraints(block, []tags.ITag{
+			&tags.Tag{Key: "yor_trace", Value: "4c5d6e7f-8091-4a2b-b3c4-d5e6f7a8b9c0"},
+			&tags.Tag{Key: "owner", Value: "<platform>"},
+			&tags.Tag{Key: "aws:cloudformation:stack-name", Value: "data"},
+			&tags.Tag{Key: "description", Value: strings.Repeat("a", 300)},
+			&tags.Tag{Key: "instance", Value: "${each.key}", IsTemplate: true},
+		})
+		assert.Equal(t, []tags.ITag{
+			&tags.Tag{Key: "yor_trace", Value: "4c5d6e7f-8091-4a2b-b3c4-d5e6f7a8b9c0"},
+			&tags.Tag{Key: "owner", Value: "_platform_"},
+			&tags.Tag{Key: "description", Value: strings.Repeat("a", 256)},
+			&tags.Tag{Key: "instance", Value: "${each.key}", IsTemplate: true},
+		}, validTags)
+		assert.Equal(t, []structure.TagViolation{
+			{Provider: "aws", Key: "owner", Value: "<platform>", Violation: "value has invalid characters", Resolution: "sanitized to owner=_platform_"},
+			{Provider: "aws", Key: "aws:cloudformation:stack-name", Value: "data", Violation: "key prefix aws: is reserved", Resolution: "dropped"},
+			{Provider: "aws", Key: "description", Value: strings.Repeat("a", 300), Violation: "value is longer than 256 characters", Resolution: fmt.Sprintf("sanitized to description=%s", strings.Repeat("a", 256))},
+		}, block.GetTagViolations())
+	})
+
+	t.Run("gcp labels are lowercased", func(t *testing.T) {
+		block := &structure.Block{Name: "google_storage_bucket.data", Type: "google_storage_bucket"}
+		validTags := ApplyTagConstraints(block, []tags.ITag{
+			&tags.Tag{Key: "Team", Value: "Data Platform"},
+			&tags.Tag{Key: "1st_owner", Value: "data"},
+		})
+		assert.Equal(t, []tags.ITag{&tags.Tag{Key: "team", Value: "data_platform"}}, validTags)
+		assert.Equal(t, []structure.TagViolation{
+			{Provider: "gcp", Key: "Team", Value: "Data Platform", Violation: "key has uppercase characters, value has uppercase characters, value has invalid characters", Resolution: "sanitized to team=data_platform"},
+			{Provider: "gcp", Key: "1st_owner", Value: "data", Violation: "key does not start with a lowercase letter", Resolution: "dropped"},
+		}, block.GetTagViolations())
+	})
+
+	t.Run("sanitized keys which collide keep the tag of the higher priority", func(t *testing.T) {
+		block := &structure.Block{Name: "google_storage_bucket.data", Type: "google_storage_bucket"}
+		validTags := ApplyTagConstraints(block, []tags.ITag{
+			&tags.Tag{Key: "Team", Value: "web"},
+			&tags.Tag{Key: "team", Value: "data", Priority: 1},
+			&tags.Tag{Key: "Owner", Value: "web"},
+			&tags.Tag{Key: "OWNER", Value: "data"},
+		})
+		assert.Equal(t, []tags.ITag{
+			&tags.Tag{Key: "team", Value: "data", Priority: 1},
+			&tags.Tag{Key: "owner", Value: "web"},
+		}, validTags)
+		assert.Equal(t, []structure.TagViolation{
+			{Provider: "gcp", Key: "Team", Value: "web", Violation: "key has uppercase characters", Resolution: "sanitized to team=web"},
+			{Provider: "gcp", Key: "Team", Value: "web", Violation: "sanitized key team is the key of another tag", Resolution: "dropped"},
+			{Provider: "gcp", Key: "Owner", Value: "web", Violation: "key has uppercase characters", Resolution: "sanitized to owner=web"},
+			{Provider: "gcp", Key: "OWNER", Value: "data", Violation: "key has uppercase characters", Resolution: "sanitized to owner=data"},
+			{Provider: "gcp", Key: "OWNER", Value: "data", Violation: "sanitized key owner is the key of another tag", Resolution: "dropped"},
+		}, block.GetTagViolations())
+	})
+
+	t.Run("tags of every tag group are sanitized before they are added", func(t *testing.T) {
+		block := &structure.Block{Name: "google_storage_bucket.data", Type: "google_storage_bucket"}
+		ValidateTagGroupTags(block)
+		block.AddNewTags([]tags.ITag{&tags.Tag{Key: "Team", Value: "web"}})
+		assert.Equal(t, []tags.ITag{&tags.Tag{Key: "team", Value: "web"}}, block.GetNewTags())
+		// the tags of the next tag group conflict with the sanitized key
+		webTeam := block.GetNewTags()[0]
+		dataTeam := &tags.Tag{Key: "team", Value: "data", Priority: 1}
+		block.AddNewTags([]tags.ITag{dataTeam})
+		assert.Nil(t, block.ResolveTagConflicts([]structure.TagProposal{{Group: "simple", Tag: webTeam}, {Group: "external", Tag: dataTeam}}))
+		assert.Equal(t, []tags.ITag{dataTeam}, block.GetNewTags())
+	})
+
+	t.Run("azure keys are sanitized", func(t *testing.T) {
+		block := &structure.Block{Name: "storage", Type: "Microsoft.Storage/storageAccounts"}
+		validTags := ApplyTagConstraints(block, []tags.ITag{&tags.Tag{Key: "cost/center", Value: "<1234>"}})
+		assert.Equal(t, []tags.ITag{&tags.Tag{Key: "cost_center", Value: "<1234>"}}, validTags)
+	})
+
+	t.Run("tags beyond the maximal number of tags are dropped", func(t *testing.T) {
+		block := &structure.Block{Name: "aws_instance.web", Type: "aws_instance"}
+		for i := 0; i < 49; i++ {
+			block.ExitingTags = append(block.ExitingTags, &tags.Tag{Key: fmt.Sprintf("tag%d", i), Value: "value"})
+		}
+		ValidateTagGroupTags(block)
+		block.AddNewTags([]tags.ITag{
+			&tags.Tag{Key: "tag0", Value: "updated"},
+			&tags.Tag{Key: "yor_trace", Value: "4c5d6e7f-8091-4a2b-b3c4-d5e6f7a8b9c0"},
+			&tags.Tag{Key: "git_repo", Value: "web"},
+		})
+		ValidateNewTags(block)
+		assert.Equal(t, []tags.ITag{
+			&tags.Tag{Key: "yor_trace", Value: "4c5d6e7f-8091-4a2b-b3c4-d5e6f7a8b9c0"},
+			&tags.Tag{Key: "tag0", Value: "updated"},
+		}, block.GetNewTags())
+		assert.Equal(t, []structure.TagViolation{
+			{Provider: "aws", Key: "git_repo", Value: "web", Violation: "more than 50 tags of aws resources", Resolution: "dropped"},
+		}, block.GetTagViolations())
+	})
+
+	t.Run("tags of unknown providers are not changed", func(t *testing.T) {
+		block := &structure.Block{Name: "Deployment.web", Type: "Deployment"}
+		newTags := []tags.ITag{&tags.Tag{Key: "Team", Value: "<web>"}}
+		assert.Equal(t, newTags, ApplyTagConstraints(block, newTags))
+		assert.Empty(t, block.GetTagViolations())
+	})
+}
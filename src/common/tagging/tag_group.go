@@ -71,6 +71,13 @@ func (t *TagGroup) IsTagSkipped(tag tags.ITag) bool {
 }
 
 func (t *TagGroup) UpdateBlockTags(block structure.IBlock, data interface{}) error {
+	newTags, err := t.CalculateTagValues(block, data)
+	block.AddNewTags(newTags)
+	return err
+}
+
+// CalculateTagValues returns the tags of the group with their values for the block, without the tags with no value
+func (t *TagGroup) CalculateTagValues(block structure.IBlock, data interface{}) ([]tags.ITag, error) {
 	var newTags []tags.ITag
 	var err error
 	var tagVal tags.ITag
@@ -83,6 +90,5 @@ func (t *TagGroup) UpdateBlockTags(block structure.IBlock, data interface{}) err
 			newTags = append(newTags, tagVal)
 		}
 	}
-	block.AddNewTags(newTags)
-	return err
+	return newTags, err
 }
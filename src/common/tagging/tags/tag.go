@@ -8,6 +8,10 @@ import (
 type Tag struct {
 	Key   string
 	Value string
+	// Priority is not written with the tags, e.g. in the Key/Value pairs of CloudFormation templates
+	Priority int `json:"-" yaml:"-"`
+	// IsTemplate marks the values which a user configures, which may be templates of the IaC framework, e.g. "${each.key}"
+	IsTemplate bool `json:"-" yaml:"-"`
 }
 
 const YorTraceTagKey = "yor_trace"
@@ -18,6 +22,16 @@ const GitLastModifiedByTagKey = "git_last_modified_by"
 const GitRepoTagKey = "git_repo"
 const YorNameTagKey = "yor_name"
 
+// The priorities of the tags yor adds. When a resource would have more tags than its tag limit, the new tags of the
+// lowest priority are dropped first. Tags of the simple and external tag groups have the default priority, so the
+// tags a user defines are kept over the git tags.
+const (
+	YorTraceTagPriority = 20
+	YorNameTagPriority  = 10
+	DefaultTagPriority  = 0
+	GitTagPriority      = -10
+)
+
 type ITag interface {
 	Init()
 	CalculateValue(data interface{}) (ITag, error)
@@ -35,6 +49,13 @@ type TagDiff struct {
 	NewValue  string
 }
 
+// TagRename is an existing tag whose key is renamed, keeping its value
+type TagRename struct {
+	PrevKey string
+	NewKey  string
+	Value   string
+}
+
 func Init(key string, value string) ITag {
 	return &Tag{
 		Key:   key,
@@ -49,13 +70,15 @@ func (t *Tag) SetTagPrefix(tagPrefix string) {
 }
 
 func (t *Tag) GetPriority() int {
-	return 0
+	return t.Priority
 }
 
 func (t *Tag) CalculateValue(_ interface{}) (ITag, error) {
 	return &Tag{
-		Key:   t.Key,
-		Value: t.Value,
+		Key:        t.Key,
+		Value:      t.Value,
+		Priority:   t.Priority,
+		IsTemplate: t.IsTemplate,
 	}, nil
 }
 
@@ -75,6 +98,13 @@ func (t *Tag) GetValue() string {
 	return t.Value
 }
 
+// IsTemplateTag checks if the value of the tag may be a template of the IaC framework. The values yor computes, such as
+// the git tags, are always written as literals.
+func IsTemplateTag(tag ITag) bool {
+	t, ok := tag.(*Tag)
+	return ok && t.IsTemplate
+}
+
 // IsTagKeyMatch Try to match the tag's key name with a potentially quoted string
 func IsTagKeyMatch(tag ITag, keyName string) bool {
 	match, _ := regexp.Match(fmt.Sprintf(`\b"?%s"?\b`, regexp.QuoteMeta(keyName)), []byte(tag.GetKey()))
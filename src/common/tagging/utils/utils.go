@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"reflect"
 	"sort"
 
 	"github.com/bridgecrewio/yor/src/common/tagging"
@@ -41,6 +42,21 @@ func TagGroupsByName(name TagGroupName) tagging.ITagGroup {
 	return tagGroup
 }
 
+// GetTagGroupName returns the name of the tag group, or the type of the tag groups of plugins
+func GetTagGroupName(tagGroup tagging.ITagGroup) string {
+	switch tagGroup.(type) {
+	case *simple.TagGroup:
+		return string(SimpleTagGroupName)
+	case *gittag.TagGroup:
+		return string(GitTagGroupName)
+	case *code2cloud.TagGroup:
+		return string(Code2Cloud)
+	case *external.TagGroup:
+		return string(ExternalTagName)
+	}
+	return reflect.TypeOf(tagGroup).String()
+}
+
 func GetAllTagGroupsNames() []string {
 	tagGroupNames := make([]string, 0)
 	for name := range tagGroupsByName {
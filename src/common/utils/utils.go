@@ -178,3 +178,32 @@ func MaxMapCountKey(m map[string]int) string {
 	}
 	return maxKey
 }
+
+// resource types which are not tracked by Azure Resource Manager as taggable resources
+var untaggableAzureResourceTypePrefixes = []string{"Microsoft.Authorization/"}
+
+// IsTaggableAzureResourceType checks if Azure resources of the given type accept tags. Child resources (e.g. subnets of a
+// virtual network or nested resources declared with a short type such as 'blobServices') and authorization resources
+// don't.
+func IsTaggableAzureResourceType(resourceType string) bool {
+	for _, prefix := range untaggableAzureResourceTypePrefixes {
+		if strings.HasPrefix(resourceType, prefix) {
+			return false
+		}
+	}
+	return strings.Count(resourceType, "/") == 1
+}
+
+// ToSnakeCase converts a PascalCase or camelCase name to snake_case, keeping acronyms together, e.g. VPCEndpoint to
+// vpc_endpoint
+func ToSnakeCase(name string) string {
+	var sb strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			sb.WriteRune('_')
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
@@ -0,0 +1,371 @@
+package yaml
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common/logger"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/bridgecrewio/yor/src/common/utils"
+	"gopkg.in/yaml.v2"
+)
+
+var documentSeparatorRegex = regexp.MustCompile(`^---(\s.*)?$`)
+
+// MapTagsEdit describes the tags that should be written into the YAML mapping found at Path, inside the lines of a
+// single resource (Scope). Scope lines are 1-based, the same as the lines of a block.
+type MapTagsEdit struct {
+	Scope   structure.Lines
+	Path    []string
+	Added   []tags.ITag
+	Updated []*tags.TagDiff
+}
+
+// MapDocumentsLinesYAML splits the lines of a (possibly multi-document) YAML file by the `---` separators and returns
+// the 1-based lines range of every non-empty document
+func MapDocumentsLinesYAML(fileLines []string) []structure.Lines {
+	documents := make([]structure.Lines, 0)
+	start := -1
+	end := -1
+	for i, line := range fileLines {
+		if documentSeparatorRegex.MatchString(strings.TrimRight(line, " \r")) {
+			if start != -1 {
+				documents = append(documents, structure.Lines{Start: start + 1, End: end + 1})
+			}
+			start, end = -1, -1
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if start == -1 {
+			start = i
+		}
+		end = i
+	}
+	if start != -1 {
+		documents = append(documents, structure.Lines{Start: start + 1, End: end + 1})
+	}
+	return documents
+}
+
+// FindMapPathLinesYAML finds the nested key at the end of `path` inside the 1-based lines range `scope`, and returns
+// the 1-based lines range from the key line to the last line of its value.
+func FindMapPathLinesYAML(fileLines []string, scope structure.Lines, path []string) (structure.Lines, bool) {
+	notFound := structure.Lines{Start: -1, End: -1}
+	if scope.Start < 1 || scope.End > len(fileLines) || len(path) == 0 {
+		return notFound, false
+	}
+	parentLine, parentIndent, parentEnd := scope.Start-2, -1, scope.End-1
+	for _, key := range path {
+		keyLine := findChildKeyLine(fileLines, parentLine+1, parentEnd, parentIndent, key)
+		if keyLine == -1 {
+			return notFound, false
+		}
+		parentIndent = len(ExtractIndentationOfLine(fileLines[keyLine]))
+		parentEnd = findKeyBlockEnd(fileLines, keyLine, parentEnd, parentIndent)
+		parentLine = keyLine
+	}
+	return structure.Lines{Start: parentLine + 1, End: parentEnd + 1}, true
+}
+
+// FindSequenceItemsLinesYAML returns the 1-based lines of the items of the block sequence which starts in the given lines,
+// from the line of the dash of each item to its last content line
+func FindSequenceItemsLinesYAML(fileLines []string, scope structure.Lines) []structure.Lines {
+	itemsLines := make([]structure.Lines, 0)
+	dashIndent := -1
+	itemStart, lastContentLine := -1, -1
+	for i := scope.Start - 1; i < scope.End && i < len(fileLines); i++ {
+		trimmed := strings.TrimSpace(fileLines[i])
+		if isEmptyOrComment(fileLines[i]) || documentSeparatorRegex.MatchString(trimmed) {
+			continue
+		}
+		indent := countLeadingSpaces(fileLines[i])
+		isItemStart := trimmed == "-" || strings.HasPrefix(trimmed, "- ")
+		if dashIndent == -1 {
+			if !isItemStart {
+				return itemsLines
+			}
+			dashIndent = indent
+		}
+		if indent < dashIndent || indent == dashIndent && !isItemStart {
+			break
+		}
+		if indent == dashIndent {
+			if itemStart != -1 {
+				itemsLines = append(itemsLines, structure.Lines{Start: itemStart + 1, End: lastContentLine + 1})
+			}
+			itemStart = i
+		}
+		lastContentLine = i
+	}
+	if itemStart != -1 {
+		itemsLines = append(itemsLines, structure.Lines{Start: itemStart + 1, End: lastContentLine + 1})
+	}
+	return itemsLines
+}
+
+// ReadMapPathYAML returns the string entries of the YAML mapping found at `path` in an unmarshalled document
+func ReadMapPathYAML(document map[interface{}]interface{}, path []string) (map[string]string, bool) {
+	var current interface{} = document
+	for _, key := range path {
+		currentMap, ok := current.(map[interface{}]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = currentMap[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	entries := make(map[string]string)
+	if current == nil {
+		return entries, true
+	}
+	currentMap, ok := current.(map[interface{}]interface{})
+	if !ok {
+		return nil, false
+	}
+	for k, v := range currentMap {
+		if v == nil {
+			entries[fmt.Sprintf("%v", k)] = ""
+			continue
+		}
+		entries[fmt.Sprintf("%v", k)] = fmt.Sprintf("%v", v)
+	}
+	return entries, true
+}
+
+// ApplyMapTagsEdits applies all the edits on the lines of a YAML file and returns the updated lines. Existing entries
+// are updated in place, new entries are added at the end of the mapping and missing parent keys are created.
+func ApplyMapTagsEdits(fileLines []string, edits []MapTagsEdit) []string {
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].Scope.Start > edits[j].Scope.Start
+	})
+	for i, edit := range edits {
+		if len(edit.Added) == 0 && len(edit.Updated) == 0 {
+			continue
+		}
+		var delta int
+		fileLines, delta = applyMapTagsEdit(fileLines, edit)
+		for j := i + 1; j < len(edits); j++ {
+			if edits[j].Scope.Start == edit.Scope.Start {
+				edits[j].Scope.End += delta
+			}
+		}
+	}
+	return fileLines
+}
+
+func applyMapTagsEdit(fileLines []string, edit MapTagsEdit) ([]string, int) {
+	originalLen := len(fileLines)
+	parentLine, parentIndent, parentEnd := edit.Scope.Start-2, -1, edit.Scope.End-1
+	for depth, key := range edit.Path {
+		keyLine := findChildKeyLine(fileLines, parentLine+1, parentEnd, parentIndent, key)
+		if keyLine == -1 {
+			// create the missing keys of the path after the last line of the deepest existing parent
+			indent := findChildIndent(fileLines, parentLine+1, parentEnd, parentIndent)
+			newLines := make([]string, 0)
+			for _, missingKey := range edit.Path[depth:] {
+				newLines = append(newLines, strings.Repeat(" ", indent)+formatYAMLKey(missingKey)+":")
+				indent += len(SingleIndent)
+			}
+			newLines = append(newLines, buildMapEntriesLines(edit.Added, indent)...)
+			insertAt := findLastContentLine(fileLines, parentLine, parentEnd) + 1
+			return insertLines(fileLines, insertAt, newLines), len(fileLines) + len(newLines) - originalLen
+		}
+		parentIndent = len(ExtractIndentationOfLine(fileLines[keyLine]))
+		parentEnd = findKeyBlockEnd(fileLines, keyLine, parentEnd, parentIndent)
+		parentLine = keyLine
+	}
+
+	if inlineValue := getInlineValue(fileLines[parentLine]); inlineValue != "" {
+		// the mapping is written in flow style (or is empty), rewrite it as a block mapping
+		entries := yaml.MapSlice{}
+		if err := yaml.Unmarshal([]byte(inlineValue), &entries); err != nil {
+			logger.Warning(fmt.Sprintf("failed to parse inline mapping %s, skipping: %s", inlineValue, err))
+			return fileLines, 0
+		}
+		updatedEntries := make([]tags.ITag, 0)
+		for _, entry := range entries {
+			key := fmt.Sprintf("%v", entry.Key)
+			value := fmt.Sprintf("%v", entry.Value)
+			for _, updated := range edit.Updated {
+				if updated.Key == key {
+					value = updated.NewValue
+				}
+			}
+			updatedEntries = append(updatedEntries, &tags.Tag{Key: key, Value: value})
+		}
+		updatedEntries = append(updatedEntries, edit.Added...)
+		keyLineStr := fileLines[parentLine]
+		fileLines[parentLine] = keyLineStr[:strings.Index(keyLineStr, inlineValue)]
+		fileLines[parentLine] = strings.TrimRight(fileLines[parentLine], " ")
+		newLines := buildMapEntriesLines(updatedEntries, parentIndent+len(SingleIndent))
+		fileLines = insertLines(fileLines, parentLine+1, newLines)
+		return fileLines, len(fileLines) - originalLen
+	}
+
+	childIndent := findChildIndent(fileLines, parentLine+1, parentEnd, parentIndent)
+	for i := parentLine + 1; i <= parentEnd; i++ {
+		if len(ExtractIndentationOfLine(fileLines[i])) != childIndent {
+			continue
+		}
+		lineKey, ok := getYAMLLineKey(fileLines[i])
+		if !ok {
+			continue
+		}
+		for _, updated := range edit.Updated {
+			if updated.Key == lineKey {
+				fileLines[i] = fileLines[i][:childIndent] + formatYAMLEntry(updated.Key, updated.NewValue)
+			}
+		}
+	}
+	newLines := buildMapEntriesLines(edit.Added, childIndent)
+	insertAt := findLastContentLine(fileLines, parentLine, parentEnd) + 1
+	fileLines = insertLines(fileLines, insertAt, newLines)
+	return fileLines, len(fileLines) - originalLen
+}
+
+// findChildKeyLine returns the 0-based index of the line holding `key` as a direct child of the parent whose content
+// is between startLine and endLine
+func findChildKeyLine(fileLines []string, startLine int, endLine int, parentIndent int, key string) int {
+	childIndent := findChildIndent(fileLines, startLine, endLine, parentIndent)
+	for i := startLine; i <= endLine && i < len(fileLines); i++ {
+		if isEmptyOrComment(fileLines[i]) {
+			continue
+		}
+		indent := len(ExtractIndentationOfLine(fileLines[i]))
+		if indent <= parentIndent {
+			break
+		}
+		if indent != childIndent {
+			continue
+		}
+		if lineKey, ok := getYAMLLineKey(fileLines[i]); ok && lineKey == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func findChildIndent(fileLines []string, startLine int, endLine int, parentIndent int) int {
+	for i := startLine; i <= endLine && i < len(fileLines); i++ {
+		if isEmptyOrComment(fileLines[i]) {
+			continue
+		}
+		indent := len(ExtractIndentationOfLine(fileLines[i]))
+		if indent > parentIndent {
+			return indent
+		}
+		break
+	}
+	if parentIndent < 0 {
+		return 0
+	}
+	return parentIndent + len(SingleIndent)
+}
+
+// findKeyBlockEnd returns the 0-based index of the last content line of the value of the key in keyLine
+func findKeyBlockEnd(fileLines []string, keyLine int, endLine int, keyIndent int) int {
+	lastContentLine := keyLine
+	for i := keyLine + 1; i <= endLine && i < len(fileLines); i++ {
+		if isEmptyOrComment(fileLines[i]) {
+			continue
+		}
+		if len(ExtractIndentationOfLine(fileLines[i])) <= keyIndent {
+			break
+		}
+		lastContentLine = i
+	}
+	return lastContentLine
+}
+
+func findLastContentLine(fileLines []string, startLine int, endLine int) int {
+	for i := utils.MinInt(endLine, len(fileLines)-1); i > startLine; i-- {
+		if !isEmptyOrComment(fileLines[i]) {
+			return i
+		}
+	}
+	return startLine
+}
+
+func isEmptyOrComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
+
+// getYAMLLineKey extracts the (unquoted) key of a `key: value` line
+func getYAMLLineKey(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "- "))
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return "", false
+	}
+	if trimmed[0] == '"' || trimmed[0] == '\'' {
+		closingIndex := strings.IndexByte(trimmed[1:], trimmed[0])
+		if closingIndex == -1 || !strings.HasPrefix(strings.TrimSpace(trimmed[closingIndex+2:]), ":") {
+			return "", false
+		}
+		return trimmed[1 : closingIndex+1], true
+	}
+	colonIndex := strings.Index(trimmed, ": ")
+	if colonIndex == -1 {
+		if !strings.HasSuffix(trimmed, ":") {
+			return "", false
+		}
+		colonIndex = len(trimmed) - 1
+	}
+	return strings.TrimSpace(trimmed[:colonIndex]), true
+}
+
+// getInlineValue returns the value written on the same line as the key, without trailing comments
+func getInlineValue(line string) string {
+	key, ok := getYAMLLineKey(line)
+	if !ok {
+		return ""
+	}
+	keyIndex := strings.Index(line, key)
+	colonIndex := strings.Index(line[keyIndex+len(key):], ":")
+	if colonIndex == -1 {
+		return ""
+	}
+	value := line[keyIndex+len(key)+colonIndex+1:]
+	if commentIndex := strings.Index(value, " #"); commentIndex != -1 {
+		value = value[:commentIndex]
+	}
+	return strings.TrimSpace(value)
+}
+
+func formatYAMLKey(key string) string {
+	entry := formatYAMLEntry(key, "")
+	return strings.TrimSuffix(strings.TrimSuffix(entry, ` ""`), ":")
+}
+
+// formatYAMLEntry formats a `key: value` line, quoting the key and value only when needed
+func formatYAMLEntry(key string, value string) string {
+	entryBytes, err := yaml.Marshal(yaml.MapSlice{{Key: key, Value: value}})
+	if err != nil {
+		logger.Warning(fmt.Sprintf("failed to marshal tag %s to yaml: %s", key, err))
+		return fmt.Sprintf("%q: %q", key, value)
+	}
+	return strings.TrimSuffix(string(entryBytes), "\n")
+}
+
+func buildMapEntriesLines(entries []tags.ITag, indent int) []string {
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		lines = append(lines, strings.Repeat(" ", indent)+formatYAMLEntry(entry.GetKey(), entry.GetValue()))
+	}
+	return lines
+}
+
+func insertLines(fileLines []string, index int, newLines []string) []string {
+	result := make([]string, 0, len(fileLines)+len(newLines))
+	result = append(result, fileLines[:index]...)
+	result = append(result, newLines...)
+	return append(result, fileLines[index:]...)
+}
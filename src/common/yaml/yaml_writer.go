@@ -32,11 +32,13 @@ func WriteYAMLFile(readFilePath string, blocks []structure.IBlock, writeFilePath
 	sort.Slice(blocks, func(i, j int) bool {
 		return blocks[i].GetLines().Start < blocks[j].GetLines().Start
 	})
-	linesPerTag := 1
-	if isCfn {
-		linesPerTag = 2
-	}
 	for _, resourceBlock := range blocks {
+		// CFN tags are a list of Key/Value pairs, unless the resource takes its tags as a map like SAM resources do
+		isListTags := isCfn && !structure.HasMapTags(resourceBlock)
+		linesPerTag := 1
+		if isListTags {
+			linesPerTag = 2
+		}
 		rawBlock := resourceBlock.GetRawBlock()
 		newResourceLines := getYAMLLines(rawBlock)
 		newResourceTagLineRange, _ := FindTagsLinesYAML(newResourceLines, tagsAttributeName)
@@ -67,7 +69,7 @@ func WriteYAMLFile(readFilePath string, blocks []structure.IBlock, writeFilePath
 			resourcesLines = append(resourcesLines, oldResourceLines[:lastIndex+1]...)
 			resourcesLines = append(resourcesLines, tagAttributeIndent+tagsAttributeName+":") // add the 'Tags:' line
 			tagIndent := tagAttributeIndent
-			if isCfn {
+			if isListTags {
 				tagIndent += SingleIndent
 			}
 			resourcesLines = append(resourcesLines, IndentLines(newResourceLines[newResourceTagLineRange.Start+1:newResourceTagLineRange.End+1], tagIndent, 0)...)
@@ -77,16 +79,15 @@ func WriteYAMLFile(readFilePath string, blocks []structure.IBlock, writeFilePath
 
 		oldTagsIndent := ExtractIndentationOfLine(oldResourceLines[oldResourceTagLines.Start-oldResourceLinesRange.Start])
 		oldTagsValueIndent := len(ExtractIndentationOfLine(oldResourceLines[oldResourceTagLines.Start-oldResourceLinesRange.Start+1])) - len(oldTagsIndent)
-		if isCfn {
+		if isListTags {
 			oldTagsValueIndent = 0
-		}
-		if isCfn {
 			oldTagsIndent += SingleIndent
 		}
 		resourcesLines = append(resourcesLines, oldResourceLines[:oldResourceTagLines.Start-oldResourceLinesRange.Start]...) // add all the resource's line before the tags
 		tagLines := oldResourceLines[oldResourceTagLines.Start-oldResourceLinesRange.Start : oldResourceTagLines.End-oldResourceLinesRange.Start+1]
 		diff := resourceBlock.CalculateTagsDiff()
-		if isCfn {
+		tagLines = MigrateExistingTags(tagLines, diff, isListTags)
+		if isListTags {
 			UpdateExistingCFNTags(tagLines, diff.Updated)
 		} else {
 			UpdateExistingSLSTags(tagLines, diff.Updated)
@@ -95,7 +96,7 @@ func WriteYAMLFile(readFilePath string, blocks []structure.IBlock, writeFilePath
 		var netNewResourceLines []string
 		for i := 0; i < len(allNewResourceTagLines); i += linesPerTag {
 			l := allNewResourceTagLines[i]
-			key := getKeyFromLine(l, isCfn)
+			key := getKeyFromLine(l, isListTags)
 			if key == "" {
 				continue
 			}
@@ -129,8 +130,8 @@ func WriteYAMLFile(readFilePath string, blocks []structure.IBlock, writeFilePath
 	return err
 }
 
-func getKeyFromLine(l string, isCfn bool) string {
-	if isCfn {
+func getKeyFromLine(l string, isListTags bool) string {
+	if isListTags {
 		if strings.Contains(l, " Key:") {
 			return strings.ReplaceAll(strings.ReplaceAll(l, " ", ""), "-Key:", "")
 		}
@@ -170,6 +171,96 @@ func UpdateExistingCFNTags(tagsLinesList []string, diff []*tags.TagDiff) {
 	}
 }
 
+// listTagKeyRegex matches the Key line of a tag in a list of Key/Value pairs, and captures the key
+var listTagKeyRegex = regexp.MustCompile(`^(\s*(?:-\s+)?Key\s*:\s*)(.*?)\s*$`)
+
+// MigrateExistingTags removes and renames the tags of the diff in the lines of the tags attribute of a resource, whose
+// first line is the attribute itself. Renamed tags keep their values as they are written. The attribute is dropped if
+// all of its tags are removed and no tags are added.
+func MigrateExistingTags(tagLines []string, diff *structure.TagDiff, isListTags bool) []string {
+	if len(diff.Removed) == 0 && len(diff.Renamed) == 0 || len(tagLines) == 0 {
+		return tagLines
+	}
+	removedKeys := make(map[string]bool)
+	for _, tag := range diff.Removed {
+		removedKeys[tag.GetKey()] = true
+	}
+	renamedKeys := make(map[string]string)
+	for _, rename := range diff.Renamed {
+		renamedKeys[rename.PrevKey] = rename.NewKey
+	}
+
+	// split the tags to the lines of each entry, an entry starts with its dash in lists or with its key in maps
+	entryIndent := findChildIndent(tagLines, 1, len(tagLines)-1, len(ExtractIndentationOfLine(tagLines[0])))
+	var entries [][]int
+	for i := 1; i < len(tagLines); i++ {
+		trimmedLine := strings.TrimSpace(tagLines[i])
+		isEntryStart := strings.HasPrefix(trimmedLine, "- ") || trimmedLine == "-"
+		if !isListTags {
+			isEntryStart = !isEmptyOrComment(tagLines[i]) && len(ExtractIndentationOfLine(tagLines[i])) == entryIndent
+		}
+		if isEntryStart || len(entries) == 0 {
+			entries = append(entries, []int{})
+		}
+		entries[len(entries)-1] = append(entries[len(entries)-1], i)
+	}
+
+	migratedLines := []string{tagLines[0]}
+	hasEntries := false
+	for _, entry := range entries {
+		keyLine, key := -1, ""
+		for _, i := range entry {
+			if isListTags {
+				if match := listTagKeyRegex.FindStringSubmatch(tagLines[i]); match != nil {
+					keyLine, key = i, strings.Trim(match[2], `"'`)
+					break
+				}
+			} else if lineKey, ok := getYAMLLineKey(tagLines[i]); ok {
+				keyLine, key = i, lineKey
+				break
+			}
+		}
+		if keyLine >= 0 && removedKeys[key] {
+			delete(removedKeys, key)
+			continue
+		}
+		if newKey, ok := renamedKeys[key]; ok && keyLine >= 0 {
+			delete(renamedKeys, key)
+			tagLines[keyLine] = renameYAMLKey(tagLines[keyLine], key, newKey, isListTags)
+		}
+		for _, i := range entry {
+			migratedLines = append(migratedLines, tagLines[i])
+			hasEntries = hasEntries || !isEmptyOrComment(tagLines[i])
+		}
+	}
+	for key := range removedKeys {
+		logger.Warning(fmt.Sprintf("Failed to find the tag %v in the tags %v, it was not removed", key, strings.TrimSpace(tagLines[0])))
+	}
+	for key := range renamedKeys {
+		logger.Warning(fmt.Sprintf("Failed to find the tag %v in the tags %v, it was not renamed", key, strings.TrimSpace(tagLines[0])))
+	}
+	if !hasEntries && len(diff.Added) == 0 {
+		return nil
+	}
+	return migratedLines
+}
+
+// renameYAMLKey replaces the key of a map entry line, or the value of the Key line of a list entry, keeping its quotes
+func renameYAMLKey(line string, key string, newKey string, isListTags bool) string {
+	if isListTags {
+		match := listTagKeyRegex.FindStringSubmatch(line)
+		if strings.Trim(match[2], `"'`) != match[2] {
+			return match[1] + strings.Replace(match[2], key, newKey, 1)
+		}
+		return match[1] + strings.TrimPrefix(formatYAMLEntry("Key", newKey), "Key: ")
+	}
+	keyIndex := strings.Index(line, key)
+	if keyIndex > 0 && (line[keyIndex-1] == '"' || line[keyIndex-1] == '\'') {
+		return line[:keyIndex] + newKey + line[keyIndex+len(key):]
+	}
+	return line[:keyIndex] + formatYAMLKey(newKey) + line[keyIndex+len(key):]
+}
+
 func ReplaceTagValue(line string, value string) string {
 	tr := regexp.MustCompile(`\bValue\s*:\s*.*`)
 	return tr.ReplaceAllString(line, `Value: `+value)
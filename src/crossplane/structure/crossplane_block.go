@@ -0,0 +1,43 @@
+package structure
+
+import (
+	"fmt"
+
+	"github.com/bridgecrewio/yor/src/common/structure"
+)
+
+type CrossplaneBlock struct {
+	structure.Block
+	// APIGroup is the API group of the managed resource, e.g. s3.aws.upbound.io
+	APIGroup string
+	// Provider is the cloud of the API group, e.g. aws for s3.aws.upbound.io
+	Provider string
+	// TagsPath is the path of the tags in the lines of the block, which are the lines of a managed resource or of a
+	// resource template of a composition
+	TagsPath []string
+}
+
+func (b *CrossplaneBlock) GetResourceID() string {
+	return fmt.Sprintf("%s.%s", b.Type, b.Name)
+}
+
+func (b *CrossplaneBlock) GetResourceName() string {
+	return b.Name
+}
+
+func (b *CrossplaneBlock) GetTagsLines() structure.Lines {
+	return b.TagLines
+}
+
+func (b *CrossplaneBlock) GetSeparator() string {
+	return ":"
+}
+
+// IsGCPBlock checks if the resource is a GCP managed resource, whose labels only accept a subset of the tag values
+func (b *CrossplaneBlock) IsGCPBlock() bool {
+	return b.Provider == GCPProvider
+}
+
+func (b *CrossplaneBlock) GetCloudProvider() string {
+	return b.Provider
+}
@@ -0,0 +1,369 @@
+package structure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common"
+	"github.com/bridgecrewio/yor/src/common/logger"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/bridgecrewio/yor/src/common/utils"
+	yamlUtils "github.com/bridgecrewio/yor/src/common/yaml"
+	tfStructure "github.com/bridgecrewio/yor/src/terraform/structure"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	AWSProvider   = structure.AWSProvider
+	AzureProvider = structure.AzureProvider
+	GCPProvider   = structure.GCPProvider
+)
+
+const SpecAttributeName = "spec"
+const ForProviderAttributeName = "forProvider"
+const BaseAttributeName = "base"
+const ResourcesAttributeName = "resources"
+const PipelineAttributeName = "pipeline"
+const InputAttributeName = "input"
+const PatchesAttributeName = "patches"
+const CompositionKind = "Composition"
+const CompositionAPIGroup = "apiextensions.crossplane.io"
+
+// providerAPIGroups maps the API groups of the providers to their cloud, the managed resources of a provider are in its
+// group or in the subgroups of its services, e.g. s3.aws.upbound.io
+var providerAPIGroups = map[string]string{
+	"aws.upbound.io":   AWSProvider,
+	"azure.upbound.io": AzureProvider,
+	"gcp.upbound.io":   GCPProvider,
+}
+
+// ProviderToTagsAttribute maps the clouds to the parameter of their managed resources which holds the tags
+var ProviderToTagsAttribute = map[string]string{
+	AWSProvider:   "tags",
+	AzureProvider: "tags",
+	GCPProvider:   "labels",
+}
+
+// the providers are generated from the terraform providers, so their resources are checked against the taggable
+// terraform resource types
+var providerToTerraformPrefix = map[string]string{
+	AWSProvider:   "aws",
+	AzureProvider: "azurerm",
+	GCPProvider:   "google",
+}
+
+// kinds of the provider groups which configure the provider and are not managed resources
+var providerConfigKinds = []string{"ProviderConfig", "ProviderConfigUsage", "StoreConfig"}
+
+type CrossplaneParser struct {
+	skippedByCommentList []string
+}
+
+type manifest struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+}
+
+func (p *CrossplaneParser) Name() string {
+	return "Crossplane"
+}
+
+func (p *CrossplaneParser) Init(_ string, _ map[string]string) {}
+
+func (p *CrossplaneParser) Close() {}
+
+func (p *CrossplaneParser) GetSkippedDirs() []string {
+	return []string{}
+}
+
+func (p *CrossplaneParser) GetSupportedFileExtensions() []string {
+	return []string{common.YamlFileType.Extension, common.YmlFileType.Extension}
+}
+
+func (p *CrossplaneParser) GetSkipResourcesByComment() []string {
+	return p.skippedByCommentList
+}
+
+// ValidFile checks that at least one of the documents in the file is a managed resource or a composition
+func (p *CrossplaneParser) ValidFile(filePath string) bool {
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Error reading file %s, skipping: %v", filePath, err))
+		return false
+	}
+	fileLines := utils.GetLinesFromBytes(src)
+	for _, documentLines := range yamlUtils.MapDocumentsLinesYAML(fileLines) {
+		m, _ := parseManifest(fileLines, documentLines)
+		if m != nil && IsCrossplaneManifest(m.APIVersion, m.Kind) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseManifest(fileLines []string, documentLines structure.Lines) (*manifest, map[interface{}]interface{}) {
+	documentStr := strings.Join(fileLines[documentLines.Start-1:documentLines.End], "\n")
+	m := &manifest{}
+	if err := yaml.Unmarshal([]byte(documentStr), m); err != nil {
+		return nil, nil
+	}
+	document := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal([]byte(documentStr), &document); err != nil {
+		return nil, nil
+	}
+	return m, document
+}
+
+func getAPIGroup(apiVersion string) string {
+	return strings.Split(apiVersion, "/")[0]
+}
+
+// GetProvider returns the cloud of the provider which the API group belongs to
+func GetProvider(apiGroup string) string {
+	for providerGroup, provider := range providerAPIGroups {
+		if apiGroup == providerGroup || strings.HasSuffix(apiGroup, "."+providerGroup) {
+			return provider
+		}
+	}
+	return ""
+}
+
+// IsCrossplaneManifest checks if the manifest is a managed resource or a composition, which the Crossplane parser tags
+// rather than the Kubernetes parser
+func IsCrossplaneManifest(apiVersion string, kind string) bool {
+	return isComposition(apiVersion, kind) || isManagedResource(apiVersion, kind)
+}
+
+func isComposition(apiVersion string, kind string) bool {
+	return getAPIGroup(apiVersion) == CompositionAPIGroup && kind == CompositionKind
+}
+
+func isManagedResource(apiVersion string, kind string) bool {
+	return strings.Contains(apiVersion, "/") && GetProvider(getAPIGroup(apiVersion)) != "" && !utils.InSlice(providerConfigKinds, kind)
+}
+
+// IsTaggableResourceKind checks if managed resources of the given kind accept tags, by their terraform resource type,
+// e.g. Bucket of s3.aws.upbound.io is checked as aws_s3_bucket or aws_bucket
+func IsTaggableResourceKind(apiGroup string, kind string) bool {
+	provider := GetProvider(apiGroup)
+	terraformPrefix, ok := providerToTerraformPrefix[provider]
+	if !ok {
+		return false
+	}
+	name := utils.ToSnakeCase(kind)
+	candidates := []string{fmt.Sprintf("%s_%s", terraformPrefix, name)}
+	if service := strings.Split(apiGroup, ".")[0]; strings.Count(apiGroup, ".") > 2 {
+		candidates = append(candidates, fmt.Sprintf("%s_%s_%s", terraformPrefix, service, name))
+	}
+	for _, candidate := range candidates {
+		if utils.InSlice(tfStructure.TfTaggableResourceTypes, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFile returns a block for every managed resource in the file, and for every resource template of the
+// compositions in the file
+func (p *CrossplaneParser) ParseFile(filePath string) ([]structure.IBlock, error) {
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s because %s", filePath, err)
+	}
+	fileLines := utils.GetLinesFromBytes(src)
+	parsedBlocks := make([]structure.IBlock, 0)
+	skipAll := false
+	for _, documentLines := range yamlUtils.MapDocumentsLinesYAML(fileLines) {
+		m, document := parseManifest(fileLines, documentLines)
+		if m == nil {
+			continue
+		}
+		var documentBlocks []*CrossplaneBlock
+		switch {
+		case isComposition(m.APIVersion, m.Kind):
+			documentBlocks = p.parseComposition(filePath, fileLines, documentLines, m.Metadata.Name, document)
+		case isManagedResource(m.APIVersion, m.Kind) && m.Metadata.Name != "":
+			documentBlocks = []*CrossplaneBlock{newCrossplaneBlock(filePath, fileLines, documentLines, m.Metadata.Name, m.APIVersion, m.Kind, document, []string{SpecAttributeName, ForProviderAttributeName})}
+		}
+
+		// skip comments are placed in the header of the document, before its first key
+		skipDocument := skipAll
+		for i := documentLines.Start - 1; i < documentLines.End && strings.HasPrefix(strings.TrimSpace(fileLines[i]), "#"); i++ {
+			comment := strings.ToUpper(strings.ReplaceAll(fileLines[i], " ", ""))
+			if comment == "#YOR:SKIPALL" {
+				skipAll = true
+			}
+			if comment == "#YOR:SKIP" || comment == "#YOR:SKIPALL" {
+				skipDocument = true
+			}
+		}
+		for _, block := range documentBlocks {
+			if skipDocument {
+				p.skippedByCommentList = append(p.skippedByCommentList, block.GetResourceID())
+			}
+			parsedBlocks = append(parsedBlocks, block)
+		}
+	}
+	return parsedBlocks, nil
+}
+
+// parseComposition returns the blocks of the resource templates of the composition, in its resources or in the inputs
+// of the steps of its pipeline
+func (p *CrossplaneParser) parseComposition(filePath string, fileLines []string, documentLines structure.Lines, compositionName string, document map[interface{}]interface{}) []*CrossplaneBlock {
+	spec, _ := document[SpecAttributeName].(map[interface{}]interface{})
+	blocks := p.parseResourceTemplates(filePath, fileLines, documentLines, compositionName, spec, []string{SpecAttributeName, ResourcesAttributeName})
+	steps, _ := spec[PipelineAttributeName].([]interface{})
+	if len(steps) == 0 {
+		return blocks
+	}
+	pipelineLines, _ := yamlUtils.FindMapPathLinesYAML(fileLines, documentLines, []string{SpecAttributeName, PipelineAttributeName})
+	stepsLines := yamlUtils.FindSequenceItemsLinesYAML(fileLines, structure.Lines{Start: pipelineLines.Start + 1, End: pipelineLines.End})
+	if len(stepsLines) != len(steps) {
+		logger.Warning(fmt.Sprintf("Failed to find the lines of the pipeline of composition %s in %s, skipping it", compositionName, filePath))
+		return blocks
+	}
+	for i, step := range steps {
+		stepMap, ok := step.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		input, _ := stepMap[InputAttributeName].(map[interface{}]interface{})
+		blocks = append(blocks, p.parseResourceTemplates(filePath, fileLines, stepsLines[i], compositionName, input, []string{InputAttributeName, ResourcesAttributeName})...)
+	}
+	return blocks
+}
+
+func (p *CrossplaneParser) parseResourceTemplates(filePath string, fileLines []string, parentLines structure.Lines, compositionName string, parent map[interface{}]interface{}, path []string) []*CrossplaneBlock {
+	templates, _ := parent[path[len(path)-1]].([]interface{})
+	if len(templates) == 0 {
+		return nil
+	}
+	templatesKeyLines, _ := yamlUtils.FindMapPathLinesYAML(fileLines, parentLines, path)
+	templatesLines := yamlUtils.FindSequenceItemsLinesYAML(fileLines, structure.Lines{Start: templatesKeyLines.Start + 1, End: templatesKeyLines.End})
+	if len(templatesLines) != len(templates) {
+		logger.Warning(fmt.Sprintf("Failed to find the lines of the resources of composition %s in %s, skipping them", compositionName, filePath))
+		return nil
+	}
+	blocks := make([]*CrossplaneBlock, 0)
+	for i, template := range templates {
+		templateMap, ok := template.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		base, _ := templateMap[BaseAttributeName].(map[interface{}]interface{})
+		apiVersion, _ := base["apiVersion"].(string)
+		kind, _ := base["kind"].(string)
+		if !isManagedResource(apiVersion, kind) {
+			continue
+		}
+		name := fmt.Sprintf("%s.%d", compositionName, i)
+		if templateName, ok := templateMap["name"].(string); ok && templateName != "" {
+			name = fmt.Sprintf("%s.%s", compositionName, templateName)
+		}
+		block := newCrossplaneBlock(filePath, fileLines, templatesLines[i], name, apiVersion, kind, templateMap, []string{BaseAttributeName, SpecAttributeName, ForProviderAttributeName})
+		// a patch of the whole tags replaces the tags of the template
+		patches, _ := templateMap[PatchesAttributeName].([]interface{})
+		tagsFieldPath := strings.Join(block.TagsPath[1:], ".")
+		for _, patch := range patches {
+			if patchMap, ok := patch.(map[interface{}]interface{}); ok && patchMap["toFieldPath"] == tagsFieldPath {
+				logger.Info(fmt.Sprintf("Not tagging resource %v of composition %v, its %v are patched as a whole", name, compositionName, tagsFieldPath))
+				block.IsTaggable = false
+			}
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// newCrossplaneBlock returns the block of a managed resource whose parameters are at the given path of the lines
+func newCrossplaneBlock(filePath string, fileLines []string, lines structure.Lines, name string, apiVersion string, kind string, resource map[interface{}]interface{}, forProviderPath []string) *CrossplaneBlock {
+	apiGroup := getAPIGroup(apiVersion)
+	provider := GetProvider(apiGroup)
+	tagsAttributeName := ProviderToTagsAttribute[provider]
+	tagsPath := append(append([]string{}, forProviderPath...), tagsAttributeName)
+	existingTagsMap, hasTags := yamlUtils.ReadMapPathYAML(resource, tagsPath)
+	var existingTags []tags.ITag
+	for _, key := range sortedKeys(existingTagsMap) {
+		existingTags = append(existingTags, &tags.Tag{Key: key, Value: existingTagsMap[key]})
+	}
+	tagsLines, _ := yamlUtils.FindMapPathLinesYAML(fileLines, lines, tagsPath)
+	return &CrossplaneBlock{
+		Block: structure.Block{
+			FilePath:          filePath,
+			ExitingTags:       existingTags,
+			RawBlock:          resource,
+			IsTaggable:        hasTags || IsTaggableResourceKind(apiGroup, kind),
+			TagsAttributeName: tagsAttributeName,
+			Lines:             lines,
+			TagLines:          tagsLines,
+			Name:              name,
+			Type:              kind,
+		},
+		APIGroup: apiGroup,
+		Provider: provider,
+		TagsPath: tagsPath,
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (p *CrossplaneParser) WriteFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(readFilePath), "temp.*.yaml")
+	defer func() {
+		_ = os.Remove(tempFile.Name())
+	}()
+	if err != nil {
+		return err
+	}
+	err = p.writeToFile(readFilePath, blocks, tempFile.Name())
+	if err != nil {
+		return err
+	}
+	tempBlocks, err := p.ParseFile(tempFile.Name())
+	if err != nil || len(tempBlocks) != len(blocks) {
+		return fmt.Errorf("editing file %v resulted in a malformed manifest, please open a github issue with the relevant details", readFilePath)
+	}
+	return p.writeToFile(readFilePath, blocks, writeFilePath)
+}
+
+func (p *CrossplaneParser) writeToFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	// #nosec G304
+	src, err := os.ReadFile(readFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s because %s", readFilePath, err)
+	}
+	fileLines := utils.GetLinesFromBytes(src)
+	edits := make([]yamlUtils.MapTagsEdit, 0)
+	for _, block := range blocks {
+		crossplaneBlock, ok := block.(*CrossplaneBlock)
+		if !ok || !crossplaneBlock.IsBlockTaggable() {
+			continue
+		}
+		diff := crossplaneBlock.CalculateTagsDiff()
+		edits = append(edits, yamlUtils.MapTagsEdit{
+			Scope:   crossplaneBlock.GetLines(),
+			Path:    crossplaneBlock.TagsPath,
+			Added:   diff.Added,
+			Updated: diff.Updated,
+		})
+	}
+	fileLines = yamlUtils.ApplyMapTagsEdits(fileLines, edits)
+
+	return os.WriteFile(writeFilePath, []byte(strings.Join(fileLines, "\n")), 0600)
+}
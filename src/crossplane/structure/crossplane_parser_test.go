@@ -0,0 +1,123 @@
+package structure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+const resourcesDir = "../../../tests/crossplane/resources"
+
+func TestCrossplaneParser_ValidFile(t *testing.T) {
+	p := CrossplaneParser{}
+	p.Init(resourcesDir, nil)
+	assert.True(t, p.ValidFile(filepath.Join(resourcesDir, "managed.yaml")))
+	assert.True(t, p.ValidFile(filepath.Join(resourcesDir, "composition.yaml")))
+	assert.False(t, p.ValidFile("../../../tests/kubernetes/resources/deployment.yaml"))
+}
+
+func TestIsTaggableResourceKind(t *testing.T) {
+	assert.True(t, IsTaggableResourceKind("s3.aws.upbound.io", "Bucket"))
+	assert.True(t, IsTaggableResourceKind("ec2.aws.upbound.io", "VPC"))
+	assert.True(t, IsTaggableResourceKind("azure.upbound.io", "ResourceGroup"))
+	assert.True(t, IsTaggableResourceKind("storage.gcp.upbound.io", "Bucket"))
+	assert.False(t, IsTaggableResourceKind("iam.aws.upbound.io", "RolePolicyAttachment"))
+	assert.False(t, IsTaggableResourceKind("apps.example.org", "Bucket"))
+}
+
+func TestCrossplaneParser_ParseFile(t *testing.T) {
+	t.Run("managed resources", func(t *testing.T) {
+		p := CrossplaneParser{}
+		p.Init(resourcesDir, nil)
+		blocks, err := p.ParseFile(filepath.Join(resourcesDir, "managed.yaml"))
+		if err != nil {
+			t.Errorf("ParseFile() error = %v", err)
+			return
+		}
+		expectedBlocks := []struct {
+			id        string
+			tagsLines structure.Lines
+			taggable  bool
+			gcp       bool
+		}{
+			{id: "Bucket.web-assets", tagsLines: structure.Lines{Start: 9, End: 10}, taggable: true},
+			{id: "VPC.web-vpc", tagsLines: structure.Lines{Start: -1, End: -1}, taggable: true},
+			{id: "Bucket.web-backups", tagsLines: structure.Lines{Start: 30, End: 30}, taggable: true, gcp: true},
+			{id: "ResourceGroup.web-rg", tagsLines: structure.Lines{Start: -1, End: -1}, taggable: true},
+			{id: "RolePolicyAttachment.web-policy", tagsLines: structure.Lines{Start: -1, End: -1}, taggable: false},
+		}
+		assert.Len(t, blocks, len(expectedBlocks))
+		for i, expected := range expectedBlocks {
+			block := blocks[i].(*CrossplaneBlock)
+			assert.Equal(t, expected.id, block.GetResourceID())
+			assert.Equal(t, expected.tagsLines, block.GetTagsLines(), expected.id)
+			assert.Equal(t, expected.taggable, block.IsBlockTaggable(), expected.id)
+			assert.Equal(t, expected.gcp, block.IsGCPBlock(), expected.id)
+		}
+		assert.Equal(t, []tags.ITag{&tags.Tag{Key: "team", Value: "web"}}, blocks[0].GetExistingTags())
+		assert.Equal(t, []string{"spec", "forProvider", "labels"}, blocks[2].(*CrossplaneBlock).TagsPath)
+		assert.Equal(t, []string{"ResourceGroup.web-rg"}, p.GetSkipResourcesByComment())
+	})
+
+	t.Run("compositions", func(t *testing.T) {
+		p := CrossplaneParser{}
+		p.Init(resourcesDir, nil)
+		blocks, err := p.ParseFile(filepath.Join(resourcesDir, "composition.yaml"))
+		if err != nil {
+			t.Errorf("ParseFile() error = %v", err)
+			return
+		}
+		assert.Len(t, blocks, 3)
+		vpcBlock := blocks[0].(*CrossplaneBlock)
+		assert.Equal(t, "VPC.xnetworks.aws.example.org.vpc", vpcBlock.GetResourceID())
+		assert.Equal(t, structure.Lines{Start: 10, End: 22}, vpcBlock.GetLines())
+		assert.Equal(t, []string{"base", "spec", "forProvider", "tags"}, vpcBlock.TagsPath)
+		assert.Equal(t, []tags.ITag{&tags.Tag{Key: "team", Value: "network"}}, vpcBlock.GetExistingTags())
+		assert.True(t, vpcBlock.IsBlockTaggable())
+		// the tags of the subnet are patched as a whole from the composite resource
+		assert.Equal(t, "Subnet.xnetworks.aws.example.org.subnet", blocks[1].GetResourceID())
+		assert.False(t, blocks[1].IsBlockTaggable())
+		assert.Equal(t, "Bucket.xbuckets.gcp.example.org.bucket", blocks[2].GetResourceID())
+		assert.Equal(t, structure.Lines{Start: 51, End: 60}, blocks[2].GetLines())
+		assert.True(t, blocks[2].IsBlockTaggable())
+	})
+}
+
+func TestCrossplaneParser_WriteFile(t *testing.T) {
+	newTags := func() []tags.ITag {
+		return []tags.ITag{
+			&tags.Tag{Key: "yor_trace", Value: "0b1c2d3e-4f50-4617-8293-a4b5c6d7e8f9"},
+			&tags.Tag{Key: "git_repo", Value: "platform"},
+			&tags.Tag{Key: "team", Value: "platform"},
+		}
+	}
+	for _, file := range []string{"managed", "composition"} {
+		t.Run(file, func(t *testing.T) {
+			p := CrossplaneParser{}
+			p.Init(resourcesDir, nil)
+			filePath := filepath.Join(resourcesDir, file+".yaml")
+			blocks, err := p.ParseFile(filePath)
+			if err != nil {
+				t.Errorf("ParseFile() error = %v", err)
+				return
+			}
+			for _, block := range blocks {
+				block.AddNewTags(newTags())
+			}
+			f, _ := os.CreateTemp(resourcesDir, "crossplane.*.yaml")
+			defer func() { _ = os.Remove(f.Name()) }()
+			if err = p.WriteFile(filePath, blocks, f.Name()); err != nil {
+				t.Errorf("WriteFile() error = %v", err)
+				return
+			}
+
+			expected, _ := os.ReadFile(filepath.Join(resourcesDir, file+".expected.yaml"))
+			actual, _ := os.ReadFile(f.Name())
+			assert.Equal(t, string(expected), string(actual))
+		})
+	}
+}
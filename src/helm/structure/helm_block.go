@@ -0,0 +1,35 @@
+package structure
+
+import (
+	"github.com/bridgecrewio/yor/src/common/structure"
+)
+
+type HelmBlock struct {
+	structure.Block
+	// LabelsHelpers are the named templates which the templates of the chart include to render their labels, e.g.
+	// chart.labels
+	LabelsHelpers  []string
+	ExistingLabels map[string]string
+	// ChartFiles are the other files of the chart directory, which are blamed together with the values file
+	ChartFiles []string
+}
+
+func (b *HelmBlock) GetResourceID() string {
+	return b.Name
+}
+
+func (b *HelmBlock) GetResourceName() string {
+	return b.Name
+}
+
+func (b *HelmBlock) GetTagsLines() structure.Lines {
+	return b.TagLines
+}
+
+func (b *HelmBlock) GetSeparator() string {
+	return ":"
+}
+
+func (b *HelmBlock) GetBlamePaths() []string {
+	return b.ChartFiles
+}
@@ -0,0 +1,281 @@
+package structure
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common"
+	"github.com/bridgecrewio/yor/src/common/logger"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/bridgecrewio/yor/src/common/utils"
+	yamlUtils "github.com/bridgecrewio/yor/src/common/yaml"
+	k8sStructure "github.com/bridgecrewio/yor/src/kubernetes/structure"
+	"gopkg.in/yaml.v2"
+)
+
+const CommonLabelsAttributeName = "commonLabels"
+const CommonAnnotationsAttributeName = "commonAnnotations"
+const HelmChartResourceType = "helm_chart"
+
+const chartFileName = "Chart.yaml"
+const valuesFileName = "values.yaml"
+const templatesDirName = "templates"
+
+// subcharts are charts of their own, which are tagged by their own values
+const subchartsDirName = "charts"
+
+// labelsHelperRegex matches the includes of named templates which render labels, e.g. {{ include "chart.labels" . }}
+var labelsHelperRegex = regexp.MustCompile(`(?:include|template)\s+"([^"]*\.labels[^"]*)"`)
+
+// commonLabelsRegex matches templates which render the common labels of the values themselves
+var commonLabelsRegex = regexp.MustCompile(`\.Values\.` + CommonLabelsAttributeName + `\b`)
+
+type HelmParser struct {
+	skippedByCommentList []string
+}
+
+type chart struct {
+	APIVersion string `yaml:"apiVersion"`
+	Name       string `yaml:"name"`
+}
+
+func (p *HelmParser) Name() string {
+	return "Helm"
+}
+
+func (p *HelmParser) Init(_ string, _ map[string]string) {}
+
+func (p *HelmParser) Close() {}
+
+func (p *HelmParser) GetSkippedDirs() []string {
+	return []string{}
+}
+
+func (p *HelmParser) GetSupportedFileExtensions() []string {
+	return []string{common.YamlFileType.Extension}
+}
+
+func (p *HelmParser) GetSkipResourcesByComment() []string {
+	return p.skippedByCommentList
+}
+
+// ValidFile checks the file is the values file of a chart, which is next to its Chart.yaml
+func (p *HelmParser) ValidFile(filePath string) bool {
+	if filepath.Base(filePath) != valuesFileName {
+		return false
+	}
+	_, err := readChart(filepath.Dir(filePath))
+	return err == nil
+}
+
+func readChart(chartDir string) (*chart, error) {
+	// #nosec G304
+	src, err := os.ReadFile(filepath.Join(chartDir, chartFileName))
+	if err != nil {
+		return nil, err
+	}
+	c := &chart{}
+	if err = yaml.Unmarshal(src, c); err != nil {
+		return nil, err
+	}
+	if c.APIVersion == "" || c.Name == "" {
+		return nil, fmt.Errorf("%s of %s has no apiVersion or name", chartFileName, chartDir)
+	}
+	return c, nil
+}
+
+// ParseFile returns a single block for the chart of the values file, whose tags are its common labels and annotations.
+// The chart is only taggable if its templates render labels through a labels helper or the common labels themselves.
+func (p *HelmParser) ParseFile(filePath string) ([]structure.IBlock, error) {
+	chartDir := filepath.Dir(filePath)
+	c, err := readChart(chartDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the chart of %s because %s", filePath, err)
+	}
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s because %s", filePath, err)
+	}
+	values := make(map[interface{}]interface{})
+	if err = yaml.Unmarshal(src, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse file %s because %s", filePath, err)
+	}
+	fileLines := utils.GetLinesFromBytes(src)
+	// the block spans the whole values file, without the empty line after its last newline
+	valuesLines := structure.Lines{Start: 1, End: len(strings.Split(strings.TrimRight(string(src), "\n"), "\n"))}
+
+	labels, _ := yamlUtils.ReadMapPathYAML(values, []string{CommonLabelsAttributeName})
+	annotations, _ := yamlUtils.ReadMapPathYAML(values, []string{CommonAnnotationsAttributeName})
+	var existingTags []tags.ITag
+	for _, key := range sortedKeys(labels) {
+		existingTags = append(existingTags, &tags.Tag{Key: key, Value: labels[key]})
+	}
+	for _, key := range sortedKeys(annotations) {
+		if _, ok := labels[key]; !ok {
+			existingTags = append(existingTags, &tags.Tag{Key: key, Value: annotations[key]})
+		}
+	}
+	tagsLines, _ := yamlUtils.FindMapPathLinesYAML(fileLines, valuesLines, []string{CommonLabelsAttributeName})
+
+	chartFiles, labelsHelpers, rendersCommonLabels := scanChart(chartDir, filePath)
+	isTaggable := len(labelsHelpers) > 0 || rendersCommonLabels
+	if !isTaggable {
+		logger.Info(fmt.Sprintf("Not tagging chart %v, its templates do not include a labels helper or render .Values.%v", c.Name, CommonLabelsAttributeName))
+	}
+	helmBlock := &HelmBlock{
+		Block: structure.Block{
+			FilePath:          filePath,
+			ExitingTags:       existingTags,
+			RawBlock:          values,
+			IsTaggable:        isTaggable,
+			TagsAttributeName: CommonLabelsAttributeName,
+			Lines:             valuesLines,
+			TagLines:          tagsLines,
+			Name:              c.Name,
+			Type:              HelmChartResourceType,
+		},
+		LabelsHelpers:  labelsHelpers,
+		ExistingLabels: labels,
+		ChartFiles:     chartFiles,
+	}
+	for _, line := range fileLines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			break
+		}
+		if strings.ToUpper(strings.ReplaceAll(line, " ", "")) == "#YOR:SKIP" {
+			p.skippedByCommentList = append(p.skippedByCommentList, helmBlock.GetResourceID())
+		}
+	}
+	return []structure.IBlock{helmBlock}, nil
+}
+
+// scanChart returns the files of the chart apart from the values file and its subcharts, the labels helpers its
+// templates include, and whether its templates render the common labels of the values themselves
+func scanChart(chartDir string, valuesFilePath string) ([]string, []string, bool) {
+	var chartFiles []string
+	labelsHelpersSet := make(map[string]bool)
+	rendersCommonLabels := false
+	templatesDir := filepath.Join(chartDir, templatesDirName)
+	_ = filepath.WalkDir(chartDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != chartDir && (filepath.Dir(path) == chartDir && d.Name() == subchartsDirName || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Clean(path) == filepath.Clean(valuesFilePath) {
+			return nil
+		}
+		chartFiles = append(chartFiles, path)
+		if !strings.HasPrefix(path, templatesDir+string(filepath.Separator)) {
+			return nil
+		}
+		// #nosec G304
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		for _, match := range labelsHelperRegex.FindAllStringSubmatch(string(src), -1) {
+			labelsHelpersSet[match[1]] = true
+		}
+		if commonLabelsRegex.Match(src) {
+			rendersCommonLabels = true
+		}
+		return nil
+	})
+	labelsHelpers := make([]string, 0, len(labelsHelpersSet))
+	for labelsHelper := range labelsHelpersSet {
+		labelsHelpers = append(labelsHelpers, labelsHelper)
+	}
+	sort.Strings(labelsHelpers)
+	return chartFiles, labelsHelpers, rendersCommonLabels
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (p *HelmParser) WriteFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(readFilePath), "temp.*.yaml")
+	defer func() {
+		_ = os.Remove(tempFile.Name())
+	}()
+	if err != nil {
+		return err
+	}
+	err = p.writeToFile(readFilePath, blocks, tempFile.Name())
+	if err != nil {
+		return err
+	}
+	values := make(map[interface{}]interface{})
+	// #nosec G304
+	tempSrc, err := os.ReadFile(tempFile.Name())
+	if err != nil || yaml.Unmarshal(tempSrc, &values) != nil {
+		return fmt.Errorf("editing file %v resulted in malformed values, please open a github issue with the relevant details", readFilePath)
+	}
+	return p.writeToFile(readFilePath, blocks, writeFilePath)
+}
+
+func (p *HelmParser) writeToFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	// #nosec G304
+	src, err := os.ReadFile(readFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s because %s", readFilePath, err)
+	}
+	fileLines := utils.GetLinesFromBytes(src)
+	edits := make([]yamlUtils.MapTagsEdit, 0)
+	for _, block := range blocks {
+		if !block.IsBlockTaggable() {
+			continue
+		}
+		edits = append(edits, getBlockEdits(block.(*HelmBlock))...)
+	}
+	fileLines = yamlUtils.ApplyMapTagsEdits(fileLines, edits)
+
+	return os.WriteFile(writeFilePath, []byte(strings.Join(fileLines, "\n")), 0600)
+}
+
+// getBlockEdits splits the tags of the chart between its common labels and common annotations, the same as the tags
+// of Kubernetes objects are split between their labels and annotations
+func getBlockEdits(block *HelmBlock) []yamlUtils.MapTagsEdit {
+	labelsEdit := yamlUtils.MapTagsEdit{Scope: block.GetLines(), Path: []string{CommonLabelsAttributeName}}
+	annotationsEdit := yamlUtils.MapTagsEdit{Scope: block.GetLines(), Path: []string{CommonAnnotationsAttributeName}}
+	diff := block.CalculateTagsDiff()
+	for _, tag := range diff.Added {
+		switch {
+		case k8sStructure.IsValidLabelKey(tag.GetKey()) && k8sStructure.IsValidLabelValue(tag.GetValue()):
+			labelsEdit.Added = append(labelsEdit.Added, tag)
+		case k8sStructure.IsValidLabelKey(tag.GetKey()):
+			annotationsEdit.Added = append(annotationsEdit.Added, tag)
+		default:
+			logger.Warning(fmt.Sprintf("Skipping tag %v of %v, it is not a valid label or annotation key", tag.GetKey(), block.GetResourceID()))
+		}
+	}
+	for _, tagDiff := range diff.Updated {
+		if _, ok := block.ExistingLabels[tagDiff.Key]; ok {
+			if !k8sStructure.IsValidLabelValue(tagDiff.NewValue) {
+				logger.Warning(fmt.Sprintf("Skipping update of label %v of %v, %q is not a valid label value", tagDiff.Key, block.GetResourceID(), tagDiff.NewValue))
+				continue
+			}
+			labelsEdit.Updated = append(labelsEdit.Updated, tagDiff)
+		} else {
+			annotationsEdit.Updated = append(annotationsEdit.Updated, tagDiff)
+		}
+	}
+	return []yamlUtils.MapTagsEdit{labelsEdit, annotationsEdit}
+}
@@ -0,0 +1,83 @@
+package structure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+const resourcesDir = "../../../tests/helm/resources"
+
+func TestHelmParser_ValidFile(t *testing.T) {
+	p := HelmParser{}
+	p.Init(resourcesDir, nil)
+	assert.True(t, p.ValidFile(filepath.Join(resourcesDir, "web", "values.yaml")))
+	assert.True(t, p.ValidFile(filepath.Join(resourcesDir, "web", "charts", "cache", "values.yaml")))
+	assert.False(t, p.ValidFile(filepath.Join(resourcesDir, "web.expected.yaml")))
+	assert.False(t, p.ValidFile(filepath.Join(resourcesDir, "web", "templates", "deployment.yaml")))
+}
+
+func TestHelmParser_ParseFile(t *testing.T) {
+	p := HelmParser{}
+	p.Init(resourcesDir, nil)
+	chartDir := filepath.Join(resourcesDir, "web")
+	blocks, err := p.ParseFile(filepath.Join(chartDir, "values.yaml"))
+	if err != nil {
+		t.Errorf("ParseFile() error = %v", err)
+		return
+	}
+	assert.Len(t, blocks, 1)
+	helmBlock := blocks[0].(*HelmBlock)
+	assert.Equal(t, "web", helmBlock.GetResourceID())
+	assert.Equal(t, HelmChartResourceType, helmBlock.GetResourceType())
+	assert.True(t, helmBlock.IsBlockTaggable())
+	assert.Equal(t, []string{"web.labels"}, helmBlock.LabelsHelpers)
+	assert.Equal(t, structure.Lines{Start: 7, End: 9}, helmBlock.GetTagsLines())
+	assert.Equal(t, []tags.ITag{
+		&tags.Tag{Key: "env", Value: "dev"},
+		&tags.Tag{Key: "team", Value: "web"},
+	}, helmBlock.GetExistingTags())
+	// the subchart is not part of the chart files
+	assert.Equal(t, []string{
+		filepath.Join(chartDir, "Chart.yaml"),
+		filepath.Join(chartDir, "templates", "_helpers.tpl"),
+		filepath.Join(chartDir, "templates", "deployment.yaml"),
+	}, helmBlock.GetBlamePaths())
+
+	blocks, err = p.ParseFile(filepath.Join(resourcesDir, "plain", "values.yaml"))
+	if err != nil {
+		t.Errorf("ParseFile() error = %v", err)
+		return
+	}
+	assert.False(t, blocks[0].IsBlockTaggable())
+}
+
+func TestHelmParser_WriteFile(t *testing.T) {
+	p := HelmParser{}
+	p.Init(resourcesDir, nil)
+	filePath := filepath.Join(resourcesDir, "web", "values.yaml")
+	blocks, err := p.ParseFile(filePath)
+	if err != nil {
+		t.Errorf("ParseFile() error = %v", err)
+		return
+	}
+	blocks[0].AddNewTags([]tags.ITag{
+		&tags.Tag{Key: "env", Value: "prod"},
+		&tags.Tag{Key: "yor_trace", Value: "9b1f4c2a-7d3e-4f5a-8b6c-1d2e3f4a5b6c"},
+		&tags.Tag{Key: "git_file", Value: "charts/web/values.yaml"},
+	})
+	f, _ := os.CreateTemp(resourcesDir, "values.*.yaml")
+	defer func() { _ = os.Remove(f.Name()) }()
+	if err = p.WriteFile(filePath, blocks, f.Name()); err != nil {
+		t.Errorf("WriteFile() error = %v", err)
+		return
+	}
+
+	expected, _ := os.ReadFile(filepath.Join(resourcesDir, "web.expected.yaml"))
+	actual, _ := os.ReadFile(f.Name())
+	assert.Equal(t, string(expected), string(actual))
+}
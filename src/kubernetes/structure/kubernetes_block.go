@@ -0,0 +1,94 @@
+package structure
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+)
+
+type KubernetesBlock struct {
+	structure.Block
+	Namespace           string
+	ExistingLabels      map[string]string
+	ExistingAnnotations map[string]string
+}
+
+// Source of the label syntax: https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#syntax-and-character-set
+var labelNameRegex = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?)?$`)
+var labelPrefixRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+var invalidLabelValueCharsRegex = regexp.MustCompile(`[^-A-Za-z0-9_.]`)
+
+const maxLabelLength = 63
+const maxLabelPrefixLength = 253
+
+func (b *KubernetesBlock) GetResourceID() string {
+	return fmt.Sprintf("%s.%s", b.Type, b.Name)
+}
+
+func (b *KubernetesBlock) GetResourceName() string {
+	return b.Name
+}
+
+func (b *KubernetesBlock) GetTagsLines() structure.Lines {
+	return b.TagLines
+}
+
+func (b *KubernetesBlock) GetSeparator() string {
+	return ":"
+}
+
+// AddNewTags adds the new tags of a tag group. Tags which are not valid labels are written as annotations, so the
+// annotations of the keys of the new tags are existing tags. Other annotations, such as
+// kubectl.kubernetes.io/last-applied-configuration, are not tags and are left alone.
+func (b *KubernetesBlock) AddNewTags(newTags []tags.ITag) {
+	for _, tag := range newTags {
+		if _, ok := b.ExistingLabels[tag.GetKey()]; ok {
+			continue
+		}
+		value, ok := b.ExistingAnnotations[tag.GetKey()]
+		if ok && !isTagKeyIn(tag.GetKey(), b.ExitingTags) {
+			b.ExitingTags = append(b.ExitingTags, &tags.Tag{Key: tag.GetKey(), Value: value})
+		}
+	}
+	b.Block.AddNewTags(newTags)
+}
+
+func isTagKeyIn(key string, tagsList []tags.ITag) bool {
+	for _, tag := range tagsList {
+		if tag.GetKey() == key {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidLabelKey checks the key is an optional DNS subdomain prefix followed by a valid label name
+func IsValidLabelKey(key string) bool {
+	name := key
+	if slashIndex := strings.LastIndex(key, "/"); slashIndex != -1 {
+		prefix := key[:slashIndex]
+		if len(prefix) == 0 || len(prefix) > maxLabelPrefixLength || !labelPrefixRegex.MatchString(prefix) {
+			return false
+		}
+		name = key[slashIndex+1:]
+	}
+	return name != "" && IsValidLabelValue(name)
+}
+
+// IsValidLabelValue checks the value is at most 63 alphanumeric characters, dashes, underscores or dots, and that it
+// begins and ends with an alphanumeric character
+func IsValidLabelValue(value string) bool {
+	return len(value) <= maxLabelLength && labelNameRegex.MatchString(value)
+}
+
+// SanitizeLabelValue replaces the characters which label values may not contain with underscores, and trims the
+// characters they may not begin or end with. The result may still be invalid, e.g. if it is longer than 63 characters.
+func SanitizeLabelValue(value string) string {
+	sanitized := invalidLabelValueCharsRegex.ReplaceAllString(value, "_")
+	return strings.TrimFunc(sanitized, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	})
+}
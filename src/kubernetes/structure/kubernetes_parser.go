@@ -0,0 +1,243 @@
+package structure
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common"
+	"github.com/bridgecrewio/yor/src/common/logger"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/bridgecrewio/yor/src/common/types"
+	"github.com/bridgecrewio/yor/src/common/utils"
+	yamlUtils "github.com/bridgecrewio/yor/src/common/yaml"
+	"gopkg.in/yaml.v2"
+)
+
+const LabelsAttributeName = "labels"
+const AnnotationsAttributeName = "annotations"
+const MetadataAttributeName = "metadata"
+
+var LabelsPath = []string{MetadataAttributeName, LabelsAttributeName}
+var AnnotationsPath = []string{MetadataAttributeName, AnnotationsAttributeName}
+
+// kinds which share the manifest structure but are not deployed as objects to the cluster
+var unsupportedKinds = []string{"List", "Kustomization", "Component"}
+
+type KubernetesParser struct {
+	YamlParser           types.YamlParser
+	skippedByCommentList []string
+	// isSkippedManifest checks if another parser tags the manifest, e.g. the managed resources of Crossplane
+	isSkippedManifest func(apiVersion string, kind string) bool
+}
+
+// Manifest is a single Kubernetes object, only the fields yor needs
+type Manifest struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+}
+
+func (p *KubernetesParser) Name() string {
+	return "Kubernetes"
+}
+
+func (p *KubernetesParser) Init(rootDir string, _ map[string]string) {
+	p.YamlParser.RootDir = rootDir
+}
+
+func (p *KubernetesParser) Close() {}
+
+// SetSkippedManifests sets the manifests which are tagged by another parser, such as the managed resources and
+// compositions of Crossplane, which are then not tagged as Kubernetes objects
+func (p *KubernetesParser) SetSkippedManifests(isSkippedManifest func(apiVersion string, kind string) bool) {
+	p.isSkippedManifest = isSkippedManifest
+}
+
+func (p *KubernetesParser) GetSkippedDirs() []string {
+	return []string{}
+}
+
+func (p *KubernetesParser) GetSupportedFileExtensions() []string {
+	return []string{common.YamlFileType.Extension, common.YmlFileType.Extension}
+}
+
+func (p *KubernetesParser) GetSkipResourcesByComment() []string {
+	return p.skippedByCommentList
+}
+
+// ValidFile checks that at least one of the documents in the file is a Kubernetes object
+func (p *KubernetesParser) ValidFile(filePath string) bool {
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Error reading file %s, skipping: %v", filePath, err))
+		return false
+	}
+	fileLines := utils.GetLinesFromBytes(src)
+	for _, documentLines := range yamlUtils.MapDocumentsLinesYAML(fileLines) {
+		if manifest, _ := p.parseManifest(fileLines, documentLines); manifest != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *KubernetesParser) parseManifest(fileLines []string, documentLines structure.Lines) (*Manifest, map[interface{}]interface{}) {
+	documentStr := strings.Join(fileLines[documentLines.Start-1:documentLines.End], "\n")
+	manifest := &Manifest{}
+	if err := yaml.Unmarshal([]byte(documentStr), manifest); err != nil {
+		return nil, nil
+	}
+	if manifest.APIVersion == "" || manifest.Kind == "" || manifest.Metadata.Name == "" || utils.InSlice(unsupportedKinds, manifest.Kind) {
+		return nil, nil
+	}
+	if p.isSkippedManifest != nil && p.isSkippedManifest(manifest.APIVersion, manifest.Kind) {
+		return nil, nil
+	}
+	document := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal([]byte(documentStr), &document); err != nil {
+		return nil, nil
+	}
+	return manifest, document
+}
+
+func (p *KubernetesParser) ParseFile(filePath string) ([]structure.IBlock, error) {
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s because %s", filePath, err)
+	}
+	fileLines := utils.GetLinesFromBytes(src)
+	parsedBlocks := make([]structure.IBlock, 0)
+	minResourceLine := math.MaxInt32
+	maxResourceLine := 0
+	skipAll := false
+	for _, documentLines := range yamlUtils.MapDocumentsLinesYAML(fileLines) {
+		manifest, document := p.parseManifest(fileLines, documentLines)
+		if manifest == nil {
+			continue
+		}
+		labels, _ := yamlUtils.ReadMapPathYAML(document, LabelsPath)
+		// annotations are existing tags only if yor writes their keys, see KubernetesBlock.AddNewTags
+		annotations, _ := yamlUtils.ReadMapPathYAML(document, AnnotationsPath)
+		var existingTags []tags.ITag
+		for key, value := range labels {
+			existingTags = append(existingTags, &tags.Tag{Key: key, Value: value})
+		}
+		tagsLines, _ := yamlUtils.FindMapPathLinesYAML(fileLines, documentLines, LabelsPath)
+		k8sBlock := &KubernetesBlock{
+			Block: structure.Block{
+				FilePath:          filePath,
+				ExitingTags:       existingTags,
+				RawBlock:          document,
+				IsTaggable:        true,
+				TagsAttributeName: LabelsAttributeName,
+				Lines:             documentLines,
+				TagLines:          tagsLines,
+				Name:              manifest.Metadata.Name,
+				Type:              manifest.Kind,
+			},
+			Namespace:           manifest.Metadata.Namespace,
+			ExistingLabels:      labels,
+			ExistingAnnotations: annotations,
+		}
+
+		// skip comments are placed in the header of the document, before its first key
+		for i := documentLines.Start - 1; i < documentLines.End && strings.HasPrefix(strings.TrimSpace(fileLines[i]), "#"); i++ {
+			comment := strings.ToUpper(strings.ReplaceAll(fileLines[i], " ", ""))
+			if comment == "#YOR:SKIPALL" {
+				skipAll = true
+			}
+			if comment == "#YOR:SKIP" {
+				p.skippedByCommentList = append(p.skippedByCommentList, k8sBlock.GetResourceID())
+			}
+		}
+		if skipAll {
+			p.skippedByCommentList = append(p.skippedByCommentList, k8sBlock.GetResourceID())
+		}
+
+		minResourceLine = int(math.Min(float64(minResourceLine), float64(documentLines.Start)))
+		maxResourceLine = int(math.Max(float64(maxResourceLine), float64(documentLines.End)))
+		parsedBlocks = append(parsedBlocks, k8sBlock)
+	}
+	if len(parsedBlocks) > 0 {
+		p.YamlParser.FileToResourcesLines.Store(filePath, structure.Lines{Start: minResourceLine, End: maxResourceLine})
+	}
+
+	return parsedBlocks, nil
+}
+
+func (p *KubernetesParser) WriteFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(readFilePath), "temp.*.yaml")
+	defer func() {
+		_ = os.Remove(tempFile.Name())
+	}()
+	if err != nil {
+		return err
+	}
+	err = p.writeToFile(readFilePath, blocks, tempFile.Name())
+	if err != nil {
+		return err
+	}
+	tempBlocks, err := p.ParseFile(tempFile.Name())
+	if err != nil || len(tempBlocks) != len(blocks) {
+		return fmt.Errorf("editing file %v resulted in a malformed manifest, please open a github issue with the relevant details", readFilePath)
+	}
+	return p.writeToFile(readFilePath, blocks, writeFilePath)
+}
+
+func (p *KubernetesParser) writeToFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	// #nosec G304
+	src, err := os.ReadFile(readFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s because %s", readFilePath, err)
+	}
+	fileLines := utils.GetLinesFromBytes(src)
+	edits := make([]yamlUtils.MapTagsEdit, 0)
+	for _, block := range blocks {
+		if !block.IsBlockTaggable() {
+			continue
+		}
+		edits = append(edits, p.getBlockEdits(block.(*KubernetesBlock))...)
+	}
+	fileLines = yamlUtils.ApplyMapTagsEdits(fileLines, edits)
+
+	return os.WriteFile(writeFilePath, []byte(strings.Join(fileLines, "\n")), 0600)
+}
+
+// getBlockEdits splits the tags of the block between the labels and the annotations of the object - tags which are
+// not valid labels are written as annotations
+func (p *KubernetesParser) getBlockEdits(block *KubernetesBlock) []yamlUtils.MapTagsEdit {
+	labelsEdit := yamlUtils.MapTagsEdit{Scope: block.GetLines(), Path: LabelsPath}
+	annotationsEdit := yamlUtils.MapTagsEdit{Scope: block.GetLines(), Path: AnnotationsPath}
+	diff := block.CalculateTagsDiff()
+	for _, tag := range diff.Added {
+		switch {
+		case IsValidLabelKey(tag.GetKey()) && IsValidLabelValue(tag.GetValue()):
+			labelsEdit.Added = append(labelsEdit.Added, tag)
+		case IsValidLabelKey(tag.GetKey()):
+			annotationsEdit.Added = append(annotationsEdit.Added, tag)
+		default:
+			logger.Warning(fmt.Sprintf("Skipping tag %v of %v, it is not a valid label or annotation key", tag.GetKey(), block.GetResourceID()))
+		}
+	}
+	for _, tagDiff := range diff.Updated {
+		if _, ok := block.ExistingLabels[tagDiff.Key]; ok {
+			if !IsValidLabelValue(tagDiff.NewValue) {
+				logger.Warning(fmt.Sprintf("Skipping update of label %v of %v, %q is not a valid label value", tagDiff.Key, block.GetResourceID(), tagDiff.NewValue))
+				continue
+			}
+			labelsEdit.Updated = append(labelsEdit.Updated, tagDiff)
+		} else {
+			annotationsEdit.Updated = append(annotationsEdit.Updated, tagDiff)
+		}
+	}
+	return []yamlUtils.MapTagsEdit{labelsEdit, annotationsEdit}
+}
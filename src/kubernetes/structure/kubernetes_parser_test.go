@@ -0,0 +1,124 @@
+package structure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKubernetesParser_ParseFile(t *testing.T) {
+	directory := "../../../tests/kubernetes/resources/manifests"
+
+	t.Run("parse multi-document manifest", func(t *testing.T) {
+		p := KubernetesParser{}
+		p.Init(directory, nil)
+		filePath := filepath.Join(directory, "deployment.yaml")
+		assert.True(t, p.ValidFile(filePath))
+		blocks, err := p.ParseFile(filePath)
+		if err != nil {
+			t.Errorf("ParseFile() error = %v", err)
+			return
+		}
+		assert.Equal(t, 3, len(blocks))
+
+		deployment := blocks[0].(*KubernetesBlock)
+		assert.Equal(t, "Deployment.web", deployment.GetResourceID())
+		assert.Equal(t, "web", deployment.GetResourceName())
+		assert.Equal(t, "frontend", deployment.Namespace)
+		assert.Equal(t, structure.Lines{Start: 1, End: 22}, deployment.GetLines())
+		assert.Equal(t, structure.Lines{Start: 7, End: 9}, deployment.GetTagsLines())
+		assert.ElementsMatch(t, []tags.ITag{
+			&tags.Tag{Key: "app", Value: "web"},
+			&tags.Tag{Key: "team", Value: "frontend"},
+		}, deployment.GetExistingTags())
+
+		service := blocks[1].(*KubernetesBlock)
+		assert.Equal(t, "Service.web", service.GetResourceID())
+		assert.Equal(t, structure.Lines{Start: -1, End: -1}, service.GetTagsLines())
+		assert.Empty(t, service.GetExistingTags())
+
+		assert.Equal(t, []string{"ConfigMap.web-config"}, p.GetSkipResourcesByComment())
+	})
+
+	t.Run("annotations are existing tags only for the keys of new tags", func(t *testing.T) {
+		p := KubernetesParser{}
+		p.Init(directory, nil)
+		blocks, err := p.ParseFile(filepath.Join(directory, "deployment.yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		service := blocks[1].(*KubernetesBlock)
+		service.ExistingAnnotations["kubectl.kubernetes.io/last-applied-configuration"] = "{}"
+		service.AddNewTags([]tags.ITag{
+			&tags.Tag{Key: "owner", Value: "web team"},
+			&tags.Tag{Key: "yor_trace", Value: "9a1f3b2c-5d7e-4f60-8a9b-0c1d2e3f4a5b"},
+		})
+		assert.Equal(t, []tags.ITag{&tags.Tag{Key: "owner", Value: "platform"}}, service.GetExistingTags())
+		diff := service.CalculateTagsDiff()
+		assert.Equal(t, []*tags.TagDiff{{Key: "owner", PrevValue: "platform", NewValue: "web team"}}, diff.Updated)
+		assert.Equal(t, []tags.ITag{&tags.Tag{Key: "yor_trace", Value: "9a1f3b2c-5d7e-4f60-8a9b-0c1d2e3f4a5b"}}, diff.Added)
+	})
+
+	t.Run("ignore yaml files which are not manifests", func(t *testing.T) {
+		p := KubernetesParser{}
+		p.Init(directory, nil)
+		assert.False(t, p.ValidFile(filepath.Join(directory, "not_a_manifest.yaml")))
+	})
+}
+
+func TestKubernetesParser_WriteFile(t *testing.T) {
+	directory := "../../../tests/kubernetes/resources/manifests"
+	p := KubernetesParser{}
+	p.Init(directory, nil)
+	filePath := filepath.Join(directory, "deployment.yaml")
+	blocks, err := p.ParseFile(filePath)
+	if err != nil {
+		t.Errorf("ParseFile() error = %v", err)
+		return
+	}
+	for _, block := range blocks[:2] {
+		block.AddNewTags([]tags.ITag{
+			&tags.Tag{Key: "yor_trace", Value: "9a1f3b2c-5d7e-4f60-8a9b-0c1d2e3f4a5b"},
+			&tags.Tag{Key: "git_file", Value: "manifests/deployment.yaml"},
+		})
+	}
+	f, _ := os.CreateTemp(directory, "deployment.*.yaml")
+	defer func() { _ = os.Remove(f.Name()) }()
+	err = p.WriteFile(filePath, blocks, f.Name())
+	if err != nil {
+		t.Errorf("WriteFile() error = %v", err)
+		return
+	}
+
+	expected, _ := os.ReadFile(filepath.Join(directory, "deployment_expected.yaml"))
+	actual, _ := os.ReadFile(f.Name())
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestIsValidLabel(t *testing.T) {
+	t.Run("label keys", func(t *testing.T) {
+		assert.True(t, IsValidLabelKey("yor_trace"))
+		assert.True(t, IsValidLabelKey("app.kubernetes.io/name"))
+		assert.False(t, IsValidLabelKey("Cost Center"))
+		assert.False(t, IsValidLabelKey("/name"))
+	})
+
+	t.Run("label values", func(t *testing.T) {
+		assert.True(t, IsValidLabelValue(""))
+		assert.True(t, IsValidLabelValue("9a1f3b2c-5d7e-4f60-8a9b-0c1d2e3f4a5b"))
+		assert.False(t, IsValidLabelValue("2023-01-01 10:00:00"))
+		assert.False(t, IsValidLabelValue("src/main.yaml"))
+		assert.False(t, IsValidLabelValue("-leading-dash"))
+	})
+
+	t.Run("sanitized label values", func(t *testing.T) {
+		assert.Equal(t, "2023-01-01_10_00_00", SanitizeLabelValue("2023-01-01 10:00:00"))
+		assert.Equal(t, "src_main.yaml", SanitizeLabelValue("src/main.yaml"))
+		assert.Equal(t, "jane_example.com", SanitizeLabelValue("jane@example.com"))
+		assert.Equal(t, "leading-dash", SanitizeLabelValue("-leading-dash"))
+	})
+}
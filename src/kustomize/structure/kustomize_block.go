@@ -0,0 +1,47 @@
+package structure
+
+import (
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	k8sStructure "github.com/bridgecrewio/yor/src/kubernetes/structure"
+)
+
+type KustomizeBlock struct {
+	structure.Block
+	// ExistingLabels are the labels yor writes to, the pairs of a labels entry without selectors or the commonLabels
+	ExistingLabels      map[string]string
+	ExistingAnnotations map[string]string
+	// SelectorLabels are the labels of the other labels entries, which kustomize adds to selectors as well
+	SelectorLabels map[string]string
+}
+
+func (b *KustomizeBlock) GetResourceID() string {
+	return b.Name
+}
+
+func (b *KustomizeBlock) GetResourceName() string {
+	return b.Name
+}
+
+func (b *KustomizeBlock) GetTagsLines() structure.Lines {
+	return b.TagLines
+}
+
+func (b *KustomizeBlock) GetSeparator() string {
+	return ":"
+}
+
+// AddNewTags adds the new tags with their values sanitized to the label value syntax. Values which are still not valid
+// label values are kept as they are, these tags are written as annotations.
+func (b *KustomizeBlock) AddNewTags(newTags []tags.ITag) {
+	sanitizedTags := make([]tags.ITag, 0, len(newTags))
+	for _, tag := range newTags {
+		if !k8sStructure.IsValidLabelValue(tag.GetValue()) {
+			if sanitizedValue := k8sStructure.SanitizeLabelValue(tag.GetValue()); sanitizedValue != "" && k8sStructure.IsValidLabelValue(sanitizedValue) {
+				tag = &tags.Tag{Key: tag.GetKey(), Value: sanitizedValue, Priority: tag.GetPriority()}
+			}
+		}
+		sanitizedTags = append(sanitizedTags, tag)
+	}
+	b.Block.AddNewTags(sanitizedTags)
+}
@@ -0,0 +1,389 @@
+package structure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common"
+	"github.com/bridgecrewio/yor/src/common/logger"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/bridgecrewio/yor/src/common/utils"
+	yamlUtils "github.com/bridgecrewio/yor/src/common/yaml"
+	k8sStructure "github.com/bridgecrewio/yor/src/kubernetes/structure"
+	"gopkg.in/yaml.v2"
+)
+
+const CommonLabelsAttributeName = "commonLabels"
+const CommonAnnotationsAttributeName = "commonAnnotations"
+const LabelsAttributeName = "labels"
+const PairsAttributeName = "pairs"
+const IncludeSelectorsAttributeName = "includeSelectors"
+const KustomizationKind = "Kustomization"
+
+var kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml"}
+
+// kinds of the kustomization files, components are kustomizations which are included by other kustomizations
+var kustomizationKinds = []string{"", KustomizationKind, "Component"}
+
+type KustomizeParser struct {
+	rootDir              string
+	skippedByCommentList []string
+}
+
+// labelsTarget is the mapping of the kustomization which yor writes its labels to
+type labelsTarget struct {
+	Scope structure.Lines
+	Path  []string
+	// EntryIndex is the index of the labels entry of the mapping, -1 for the commonLabels
+	EntryIndex int
+}
+
+func (p *KustomizeParser) Name() string {
+	return "Kustomize"
+}
+
+func (p *KustomizeParser) Init(rootDir string, _ map[string]string) {
+	p.rootDir = rootDir
+}
+
+func (p *KustomizeParser) Close() {}
+
+func (p *KustomizeParser) GetSkippedDirs() []string {
+	return []string{}
+}
+
+func (p *KustomizeParser) GetSupportedFileExtensions() []string {
+	return []string{common.YamlFileType.Extension, common.YmlFileType.Extension}
+}
+
+func (p *KustomizeParser) GetSkipResourcesByComment() []string {
+	return p.skippedByCommentList
+}
+
+func (p *KustomizeParser) ValidFile(filePath string) bool {
+	if !utils.InSlice(kustomizationFileNames, filepath.Base(filePath)) {
+		return false
+	}
+	_, err := readKustomization(filePath)
+	return err == nil
+}
+
+func readKustomization(filePath string) (map[interface{}]interface{}, error) {
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	document := make(map[interface{}]interface{})
+	if err = yaml.Unmarshal(src, &document); err != nil {
+		return nil, err
+	}
+	kind, _ := document["kind"].(string)
+	if !utils.InSlice(kustomizationKinds, kind) {
+		return nil, fmt.Errorf("%s is a %s rather than a kustomization", filePath, kind)
+	}
+	return document, nil
+}
+
+// ParseFile returns a single block for the kustomization, whose tags are the labels it adds to all of its resources and
+// its common annotations
+func (p *KustomizeParser) ParseFile(filePath string) ([]structure.IBlock, error) {
+	document, err := readKustomization(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file %s because %s", filePath, err)
+	}
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s because %s", filePath, err)
+	}
+	fileLines := utils.GetLinesFromBytes(src)
+	fileScope := structure.Lines{Start: 1, End: len(strings.Split(strings.TrimRight(string(src), "\n"), "\n"))}
+
+	existingLabels := make(map[string]string)
+	selectorLabels := make(map[string]string)
+	target, hasTarget := getLabelsTarget(fileLines, fileScope, document)
+	for i, entry := range getLabelsEntries(document) {
+		pairs, _ := yamlUtils.ReadMapPathYAML(entry, []string{PairsAttributeName})
+		if hasTarget && target.EntryIndex == i {
+			existingLabels = pairs
+			continue
+		}
+		for key, value := range pairs {
+			selectorLabels[key] = value
+		}
+	}
+	if commonLabels, ok := yamlUtils.ReadMapPathYAML(document, []string{CommonLabelsAttributeName}); ok {
+		if hasTarget && target.EntryIndex == -1 {
+			existingLabels = commonLabels
+		} else {
+			for key, value := range commonLabels {
+				selectorLabels[key] = value
+			}
+		}
+	}
+	annotations, _ := yamlUtils.ReadMapPathYAML(document, []string{CommonAnnotationsAttributeName})
+
+	var existingTags []tags.ITag
+	for _, labels := range []map[string]string{existingLabels, selectorLabels, annotations} {
+		for _, key := range sortedKeys(labels) {
+			if !isTagKeyIn(key, existingTags) {
+				existingTags = append(existingTags, &tags.Tag{Key: key, Value: labels[key]})
+			}
+		}
+	}
+	tagsLines := structure.Lines{Start: -1, End: -1}
+	if hasTarget {
+		tagsLines, _ = yamlUtils.FindMapPathLinesYAML(fileLines, target.Scope, target.Path)
+	}
+
+	kustomizeBlock := &KustomizeBlock{
+		Block: structure.Block{
+			FilePath:          filePath,
+			ExitingTags:       existingTags,
+			RawBlock:          document,
+			IsTaggable:        true,
+			TagsAttributeName: LabelsAttributeName,
+			Lines:             fileScope,
+			TagLines:          tagsLines,
+			Name:              p.getName(filePath),
+			Type:              KustomizationKind,
+		},
+		ExistingLabels:      existingLabels,
+		ExistingAnnotations: annotations,
+		SelectorLabels:      selectorLabels,
+	}
+	for _, line := range fileLines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			break
+		}
+		if strings.ToUpper(strings.ReplaceAll(line, " ", "")) == "#YOR:SKIP" {
+			p.skippedByCommentList = append(p.skippedByCommentList, kustomizeBlock.GetResourceID())
+		}
+	}
+	return []structure.IBlock{kustomizeBlock}, nil
+}
+
+// getName returns the directory of the file relative to the root directory, which identifies the kustomization
+func (p *KustomizeParser) getName(filePath string) string {
+	dir := filepath.Dir(filePath)
+	if relDir, err := filepath.Rel(p.rootDir, dir); err == nil && relDir != "." && !strings.HasPrefix(relDir, "..") {
+		return filepath.ToSlash(relDir)
+	}
+	absDir, _ := filepath.Abs(dir)
+	return filepath.Base(absDir)
+}
+
+func getLabelsEntries(document map[interface{}]interface{}) []map[interface{}]interface{} {
+	rawEntries, _ := document[LabelsAttributeName].([]interface{})
+	entries := make([]map[interface{}]interface{}, 0, len(rawEntries))
+	for _, rawEntry := range rawEntries {
+		entry, _ := rawEntry.(map[interface{}]interface{})
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// getLabelsTarget returns the mapping which the labels are written to - the pairs of the first labels entry which does
+// not include selectors, or the commonLabels of kustomizations which do not have such an entry. Labels which are added
+// to selectors would change the selectors of existing workloads, which are immutable, on every change of the tags, so
+// the commonLabels are only used by kustomizations which already use them.
+func getLabelsTarget(fileLines []string, fileScope structure.Lines, document map[interface{}]interface{}) (labelsTarget, bool) {
+	entriesLines, _ := findLabelsEntriesLines(fileLines, fileScope)
+	for i, entry := range getLabelsEntries(document) {
+		if includeSelectors, _ := entry[IncludeSelectorsAttributeName].(bool); includeSelectors || i >= len(entriesLines) {
+			continue
+		}
+		return labelsTarget{Scope: entriesLines[i], Path: []string{PairsAttributeName}, EntryIndex: i}, true
+	}
+	if _, ok := document[CommonLabelsAttributeName]; ok {
+		return labelsTarget{Scope: fileScope, Path: []string{CommonLabelsAttributeName}, EntryIndex: -1}, true
+	}
+	return labelsTarget{}, false
+}
+
+// findLabelsEntriesLines returns the 1-based lines of the entries of the labels list, and the indentation of their dash
+func findLabelsEntriesLines(fileLines []string, fileScope structure.Lines) ([]structure.Lines, int) {
+	labelsLines, ok := yamlUtils.FindMapPathLinesYAML(fileLines, fileScope, []string{LabelsAttributeName})
+	if !ok {
+		return nil, -1
+	}
+	var entriesLines []structure.Lines
+	itemIndent := -1
+	// labelsLines.Start is the 1-based line of the key, which is the 0-based index of the line after it
+	for i := labelsLines.Start; i < labelsLines.End; i++ {
+		trimmed := strings.TrimLeft(fileLines[i], " ")
+		if !strings.HasPrefix(trimmed, "-") {
+			continue
+		}
+		indent := len(fileLines[i]) - len(trimmed)
+		if itemIndent == -1 {
+			itemIndent = indent
+		}
+		if indent != itemIndent {
+			continue
+		}
+		if len(entriesLines) > 0 {
+			entriesLines[len(entriesLines)-1].End = i
+		}
+		entriesLines = append(entriesLines, structure.Lines{Start: i + 1, End: labelsLines.End})
+	}
+	return entriesLines, itemIndent
+}
+
+// addLabelsEntry adds a labels entry which does not include selectors, after the existing entries, and returns it as
+// the target of the labels
+func addLabelsEntry(fileLines []string, fileScope structure.Lines) ([]string, labelsTarget) {
+	entryLines := func(itemIndent int) []string {
+		return []string{
+			strings.Repeat(" ", itemIndent) + "- " + IncludeSelectorsAttributeName + ": false",
+			strings.Repeat(" ", itemIndent+len(yamlUtils.SingleIndent)) + PairsAttributeName + ": {}",
+		}
+	}
+	var insertAt int
+	var newLines []string
+	labelsLines, ok := yamlUtils.FindMapPathLinesYAML(fileLines, fileScope, []string{LabelsAttributeName})
+	switch {
+	case !ok:
+		insertAt = fileScope.End
+		newLines = append([]string{LabelsAttributeName + ":"}, entryLines(len(yamlUtils.SingleIndent))...)
+	case labelsLines.Start == labelsLines.End:
+		// the labels are an empty flow sequence, e.g. labels: [], which is replaced with the new entry
+		keyLine := fileLines[labelsLines.Start-1]
+		keyIndent := len(keyLine) - len(strings.TrimLeft(keyLine, " "))
+		fileLines[labelsLines.Start-1] = keyLine[:keyIndent] + LabelsAttributeName + ":"
+		insertAt = labelsLines.Start
+		newLines = entryLines(keyIndent + len(yamlUtils.SingleIndent))
+	default:
+		_, itemIndent := findLabelsEntriesLines(fileLines, fileScope)
+		insertAt = labelsLines.End
+		newLines = entryLines(itemIndent)
+	}
+	result := make([]string, 0, len(fileLines)+len(newLines))
+	result = append(result, fileLines[:insertAt]...)
+	result = append(result, newLines...)
+	result = append(result, fileLines[insertAt:]...)
+	if !ok {
+		// the 0-based index of the new labels key is the 1-based line of the last line before it
+		insertAt++
+	}
+	entryScope := structure.Lines{Start: insertAt + 1, End: insertAt + 2}
+	return result, labelsTarget{Scope: entryScope, Path: []string{PairsAttributeName}}
+}
+
+func isTagKeyIn(key string, tagsList []tags.ITag) bool {
+	for _, tag := range tagsList {
+		if tag.GetKey() == key {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (p *KustomizeParser) WriteFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(readFilePath), "temp.*.yaml")
+	defer func() {
+		_ = os.Remove(tempFile.Name())
+	}()
+	if err != nil {
+		return err
+	}
+	err = p.writeToFile(readFilePath, blocks, tempFile.Name())
+	if err != nil {
+		return err
+	}
+	if _, err = readKustomization(tempFile.Name()); err != nil {
+		return fmt.Errorf("editing file %v resulted in a malformed kustomization, please open a github issue with the relevant details", readFilePath)
+	}
+	return p.writeToFile(readFilePath, blocks, writeFilePath)
+}
+
+func (p *KustomizeParser) writeToFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	document, err := readKustomization(readFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse file %s because %s", readFilePath, err)
+	}
+	// #nosec G304
+	src, err := os.ReadFile(readFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s because %s", readFilePath, err)
+	}
+	fileLines := utils.GetLinesFromBytes(src)
+	fileScope := structure.Lines{Start: 1, End: len(strings.Split(strings.TrimRight(string(src), "\n"), "\n"))}
+	for _, block := range blocks {
+		if !block.IsBlockTaggable() {
+			continue
+		}
+		labelsEdit, annotationsEdit := getBlockEdits(block.(*KustomizeBlock))
+		if len(labelsEdit.Added) > 0 || len(labelsEdit.Updated) > 0 {
+			target, ok := getLabelsTarget(fileLines, fileScope, document)
+			if !ok {
+				fileLines, target = addLabelsEntry(fileLines, fileScope)
+			}
+			labelsEdit.Scope, labelsEdit.Path = target.Scope, target.Path
+			fileLines = yamlUtils.ApplyMapTagsEdits(fileLines, []yamlUtils.MapTagsEdit{labelsEdit})
+		}
+		// the annotations are edited after the labels, as the labels edit changes the lines of the file
+		annotationsEdit.Scope = structure.Lines{Start: 1, End: findLastContentLine(fileLines) + 1}
+		fileLines = yamlUtils.ApplyMapTagsEdits(fileLines, []yamlUtils.MapTagsEdit{annotationsEdit})
+	}
+
+	return os.WriteFile(writeFilePath, []byte(strings.Join(fileLines, "\n")), 0600)
+}
+
+func findLastContentLine(fileLines []string) int {
+	for i := len(fileLines) - 1; i > 0; i-- {
+		if strings.TrimSpace(fileLines[i]) != "" {
+			return i
+		}
+	}
+	return 0
+}
+
+// getBlockEdits splits the tags of the kustomization between its labels and its common annotations - tags which are not
+// valid labels are written as annotations. The selector labels are not updated, changing them would change the
+// selectors of existing workloads.
+func getBlockEdits(block *KustomizeBlock) (yamlUtils.MapTagsEdit, yamlUtils.MapTagsEdit) {
+	labelsEdit := yamlUtils.MapTagsEdit{}
+	annotationsEdit := yamlUtils.MapTagsEdit{Path: []string{CommonAnnotationsAttributeName}}
+	diff := block.CalculateTagsDiff()
+	for _, tag := range diff.Added {
+		switch {
+		case k8sStructure.IsValidLabelKey(tag.GetKey()) && k8sStructure.IsValidLabelValue(tag.GetValue()):
+			labelsEdit.Added = append(labelsEdit.Added, tag)
+		case k8sStructure.IsValidLabelKey(tag.GetKey()):
+			annotationsEdit.Added = append(annotationsEdit.Added, tag)
+		default:
+			logger.Warning(fmt.Sprintf("Skipping tag %v of %v, it is not a valid label or annotation key", tag.GetKey(), block.GetResourceID()))
+		}
+	}
+	for _, tagDiff := range diff.Updated {
+		if _, ok := block.ExistingLabels[tagDiff.Key]; ok {
+			if !k8sStructure.IsValidLabelValue(tagDiff.NewValue) {
+				logger.Warning(fmt.Sprintf("Skipping update of label %v of %v, %q is not a valid label value", tagDiff.Key, block.GetResourceID(), tagDiff.NewValue))
+				continue
+			}
+			labelsEdit.Updated = append(labelsEdit.Updated, tagDiff)
+			continue
+		}
+		if _, ok := block.SelectorLabels[tagDiff.Key]; ok {
+			logger.Debug(fmt.Sprintf("Skipping update of label %v of %v, it is a selector label", tagDiff.Key, block.GetResourceID()))
+			continue
+		}
+		annotationsEdit.Updated = append(annotationsEdit.Updated, tagDiff)
+	}
+	return labelsEdit, annotationsEdit
+}
@@ -0,0 +1,91 @@
+package structure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+const resourcesDir = "../../../tests/kustomize/resources"
+
+func TestKustomizeParser_ValidFile(t *testing.T) {
+	p := KustomizeParser{}
+	p.Init(resourcesDir, nil)
+	assert.True(t, p.ValidFile(filepath.Join(resourcesDir, "labels", "kustomization.yaml")))
+	assert.True(t, p.ValidFile(filepath.Join(resourcesDir, "common_labels", "kustomization.yaml")))
+	assert.False(t, p.ValidFile(filepath.Join(resourcesDir, "labels", "expected.yaml")))
+}
+
+func TestKustomizeParser_ParseFile(t *testing.T) {
+	p := KustomizeParser{}
+	p.Init(resourcesDir, nil)
+	blocks, err := p.ParseFile(filepath.Join(resourcesDir, "labels", "kustomization.yaml"))
+	if err != nil {
+		t.Errorf("ParseFile() error = %v", err)
+		return
+	}
+	assert.Len(t, blocks, 1)
+	kustomizeBlock := blocks[0].(*KustomizeBlock)
+	assert.Equal(t, "labels", kustomizeBlock.GetResourceID())
+	assert.Equal(t, map[string]string{"team": "web", "env": "dev"}, kustomizeBlock.ExistingLabels)
+	assert.Equal(t, map[string]string{"app": "web"}, kustomizeBlock.SelectorLabels)
+	assert.Equal(t, structure.Lines{Start: 12, End: 14}, kustomizeBlock.GetTagsLines())
+	assert.Equal(t, []tags.ITag{
+		&tags.Tag{Key: "env", Value: "dev"},
+		&tags.Tag{Key: "team", Value: "web"},
+		&tags.Tag{Key: "app", Value: "web"},
+		&tags.Tag{Key: "owner", Value: "platform"},
+	}, kustomizeBlock.GetExistingTags())
+}
+
+func TestKustomizeParser_WriteFile(t *testing.T) {
+	newTags := func() []tags.ITag {
+		return []tags.ITag{
+			&tags.Tag{Key: "env", Value: "prod"},
+			&tags.Tag{Key: "app", Value: "frontend"},
+			&tags.Tag{Key: "yor_trace", Value: "5e6f7a8b-9c0d-4e1f-a2b3-c4d5e6f7a8b9"},
+			&tags.Tag{Key: "git_last_modified_at", Value: "2023-01-01 10:00:00"},
+			&tags.Tag{Key: "git_repo", Value: "https://github.com/example/a-repository-with-a-very-long-name-which-is-not-a-label"},
+		}
+	}
+	for _, dir := range []string{"labels", "common_labels", "new_labels"} {
+		t.Run(dir, func(t *testing.T) {
+			p := KustomizeParser{}
+			p.Init(resourcesDir, nil)
+			filePath := filepath.Join(resourcesDir, dir, "kustomization.yaml")
+			blocks, err := p.ParseFile(filePath)
+			if err != nil {
+				t.Errorf("ParseFile() error = %v", err)
+				return
+			}
+			blocks[0].AddNewTags(newTags())
+			f, _ := os.CreateTemp(resourcesDir, "kustomization.*.yaml")
+			defer func() { _ = os.Remove(f.Name()) }()
+			if err = p.WriteFile(filePath, blocks, f.Name()); err != nil {
+				t.Errorf("WriteFile() error = %v", err)
+				return
+			}
+
+			expected, _ := os.ReadFile(filepath.Join(resourcesDir, dir, "expected.yaml"))
+			actual, _ := os.ReadFile(f.Name())
+			assert.Equal(t, string(expected), string(actual))
+
+			// tagging the tagged file again changes nothing, apart from the selector label which is not updated
+			taggedBlocks, err := p.ParseFile(f.Name())
+			if err != nil {
+				t.Errorf("ParseFile() error = %v", err)
+				return
+			}
+			taggedBlocks[0].AddNewTags(newTags())
+			diff := taggedBlocks[0].CalculateTagsDiff()
+			assert.Empty(t, diff.Added)
+			for _, tagDiff := range diff.Updated {
+				assert.Equal(t, "app", tagDiff.Key)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package structure
+
+import (
+	"github.com/bridgecrewio/yor/src/common/structure"
+)
+
+type PulumiBlock struct {
+	structure.Block
+	// Provider is the package of the resource type token, e.g. aws for aws:s3:Bucket
+	Provider string
+}
+
+func (b *PulumiBlock) GetResourceID() string {
+	return b.Name
+}
+
+func (b *PulumiBlock) GetResourceName() string {
+	return b.Name
+}
+
+func (b *PulumiBlock) GetTagsLines() structure.Lines {
+	return b.TagLines
+}
+
+func (b *PulumiBlock) GetSeparator() string {
+	return ":"
+}
+
+// GetCloudProvider returns the cloud of the package of the resource, e.g. azure for both azure and azure-native
+func (b *PulumiBlock) GetCloudProvider() string {
+	return packageToCloudProvider[b.Provider]
+}
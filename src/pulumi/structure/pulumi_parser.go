@@ -0,0 +1,285 @@
+package structure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/bridgecrewio/yor/src/common/types"
+	"github.com/bridgecrewio/yor/src/common/utils"
+	yamlUtils "github.com/bridgecrewio/yor/src/common/yaml"
+	tfStructure "github.com/bridgecrewio/yor/src/terraform/structure"
+	"gopkg.in/yaml.v2"
+)
+
+const ResourcesStartToken = "resources"
+const PropertiesAttributeName = "properties"
+const yamlRuntime = "yaml"
+
+// ProviderToTagsAttribute maps the providers to the property of their resources which holds the tags
+var ProviderToTagsAttribute = map[string]string{
+	"aws":           "tags",
+	"azure":         "tags",
+	"azure-native":  "tags",
+	"gcp":           "labels",
+	"google-native": "labels",
+}
+
+var packageToCloudProvider = map[string]string{
+	"aws":           structure.AWSProvider,
+	"azure":         structure.AzureProvider,
+	"azure-native":  structure.AzureProvider,
+	"gcp":           structure.GCPProvider,
+	"google-native": structure.GCPProvider,
+}
+
+// providerToTerraformPrefix maps providers which are bridged from terraform providers to the prefix of the terraform
+// resource types, which is used to check if a resource type is taggable
+var providerToTerraformPrefix = map[string]string{
+	"aws":   "aws",
+	"azure": "azurerm",
+	"gcp":   "google",
+}
+
+var projectFileNames = []string{"Pulumi.yaml", "Pulumi.yml"}
+var mainFileNames = []string{"Main.yaml", "Main.yml"}
+
+type PulumiParser struct {
+	YamlParser           types.YamlParser
+	skippedByCommentList []string
+}
+
+type pulumiProgram struct {
+	Runtime   interface{}                `yaml:"runtime"`
+	Resources map[string]*pulumiResource `yaml:"resources"`
+}
+
+type pulumiResource struct {
+	Type       string                      `yaml:"type"`
+	Properties map[interface{}]interface{} `yaml:"properties"`
+	// resources with a get section are read from the stack, and are not managed by the program
+	Get interface{} `yaml:"get"`
+}
+
+func (p *PulumiParser) Name() string {
+	return "Pulumi"
+}
+
+func (p *PulumiParser) Init(rootDir string, _ map[string]string) {
+	p.YamlParser.RootDir = rootDir
+}
+
+func (p *PulumiParser) Close() {}
+
+func (p *PulumiParser) GetSkippedDirs() []string {
+	return []string{}
+}
+
+func (p *PulumiParser) GetSupportedFileExtensions() []string {
+	return []string{common.YamlFileType.Extension, common.YmlFileType.Extension}
+}
+
+func (p *PulumiParser) GetSkipResourcesByComment() []string {
+	return p.skippedByCommentList
+}
+
+// ValidFile checks the file is the project file of a Pulumi YAML program, or the Main.yaml of such a program
+func (p *PulumiParser) ValidFile(filePath string) bool {
+	fileName := filepath.Base(filePath)
+	switch {
+	case utils.InSlice(projectFileNames, fileName):
+		return isYAMLRuntimeProject(filePath)
+	case utils.InSlice(mainFileNames, fileName):
+		for _, projectFileName := range projectFileNames {
+			if isYAMLRuntimeProject(filepath.Join(filepath.Dir(filePath), projectFileName)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isYAMLRuntimeProject(projectFilePath string) bool {
+	program, err := readProgram(projectFilePath)
+	if err != nil {
+		return false
+	}
+	switch runtime := program.Runtime.(type) {
+	case string:
+		return runtime == yamlRuntime
+	case map[interface{}]interface{}:
+		return runtime["name"] == yamlRuntime
+	}
+	return false
+}
+
+func readProgram(filePath string) (*pulumiProgram, error) {
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	program := &pulumiProgram{}
+	if err = yaml.Unmarshal(src, program); err != nil {
+		return nil, err
+	}
+	return program, nil
+}
+
+func (p *PulumiParser) ParseFile(filePath string) ([]structure.IBlock, error) {
+	program, err := readProgram(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file %s because %s", filePath, err)
+	}
+	if len(program.Resources) == 0 {
+		return nil, nil
+	}
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s because %s", filePath, err)
+	}
+	fileLines := utils.GetLinesFromBytes(src)
+	resourceNames := make([]string, 0, len(program.Resources))
+	for resourceName := range program.Resources {
+		resourceNames = append(resourceNames, resourceName)
+	}
+	resourceNamesToLines, skipResourcesByComment := yamlUtils.MapResourcesLineYAML(filePath, resourceNames, ResourcesStartToken)
+	p.skippedByCommentList = append(p.skippedByCommentList, skipResourcesByComment...)
+	sort.Slice(resourceNames, func(i, j int) bool {
+		return resourceNamesToLines[resourceNames[i]].Start < resourceNamesToLines[resourceNames[j]].Start
+	})
+
+	parsedBlocks := make([]structure.IBlock, 0)
+	minResourceLine := len(fileLines)
+	maxResourceLine := 0
+	for _, resourceName := range resourceNames {
+		resource := program.Resources[resourceName]
+		if resource == nil || resource.Type == "" {
+			continue
+		}
+		// MapResourcesLineYAML returns 0-based lines, blocks hold 1-based lines
+		lines := structure.Lines{Start: resourceNamesToLines[resourceName].Start + 1, End: resourceNamesToLines[resourceName].End + 1}
+		provider := strings.Split(resource.Type, ":")[0]
+		tagsAttributeName, isSupportedProvider := ProviderToTagsAttribute[provider]
+		var existingTags []tags.ITag
+		hasTags := false
+		tagsLines := structure.Lines{Start: -1, End: -1}
+		if isSupportedProvider {
+			var existingTagsMap map[string]string
+			existingTagsMap, hasTags = yamlUtils.ReadMapPathYAML(resource.Properties, []string{tagsAttributeName})
+			for _, key := range sortedKeys(existingTagsMap) {
+				existingTags = append(existingTags, &tags.Tag{Key: key, Value: existingTagsMap[key]})
+			}
+			tagsLines, _ = yamlUtils.FindMapPathLinesYAML(fileLines, lines, []string{resourceName, PropertiesAttributeName, tagsAttributeName})
+		}
+		pulumiBlock := &PulumiBlock{
+			Block: structure.Block{
+				FilePath:          filePath,
+				ExitingTags:       existingTags,
+				RawBlock:          resource,
+				IsTaggable:        isSupportedProvider && resource.Get == nil && (hasTags || IsTaggableResourceType(resource.Type)),
+				TagsAttributeName: tagsAttributeName,
+				Lines:             lines,
+				TagLines:          tagsLines,
+				Name:              resourceName,
+				Type:              resource.Type,
+			},
+			Provider: provider,
+		}
+		minResourceLine = utils.MinInt(minResourceLine, lines.Start)
+		if lines.End > maxResourceLine {
+			maxResourceLine = lines.End
+		}
+		parsedBlocks = append(parsedBlocks, pulumiBlock)
+	}
+	if len(parsedBlocks) > 0 {
+		p.YamlParser.FileToResourcesLines.Store(filePath, structure.Lines{Start: minResourceLine, End: maxResourceLine})
+	}
+
+	return parsedBlocks, nil
+}
+
+// IsTaggableResourceType checks if resources of the given type token accept tags. Resources of providers bridged from
+// terraform are checked against their terraform resource type, e.g. aws:s3/bucket:Bucket is checked as aws_s3_bucket
+// or aws_bucket. Resources of the native providers are expected to accept tags.
+func IsTaggableResourceType(resourceType string) bool {
+	tokenParts := strings.Split(resourceType, ":")
+	if len(tokenParts) != 3 {
+		return false
+	}
+	terraformPrefix, ok := providerToTerraformPrefix[tokenParts[0]]
+	if !ok {
+		_, ok = ProviderToTagsAttribute[tokenParts[0]]
+		return ok
+	}
+	module := strings.ToLower(strings.Split(tokenParts[1], "/")[0])
+	name := utils.ToSnakeCase(tokenParts[2])
+	for _, candidate := range []string{
+		fmt.Sprintf("%s_%s_%s", terraformPrefix, module, name),
+		fmt.Sprintf("%s_%s", terraformPrefix, name),
+	} {
+		if utils.InSlice(tfStructure.TfTaggableResourceTypes, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (p *PulumiParser) WriteFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(readFilePath), "temp.*.yaml")
+	defer func() {
+		_ = os.Remove(tempFile.Name())
+	}()
+	if err != nil {
+		return err
+	}
+	err = p.writeToFile(readFilePath, blocks, tempFile.Name())
+	if err != nil {
+		return err
+	}
+	tempBlocks, err := p.ParseFile(tempFile.Name())
+	if err != nil || len(tempBlocks) != len(blocks) {
+		return fmt.Errorf("editing file %v resulted in a malformed program, please open a github issue with the relevant details", readFilePath)
+	}
+	return p.writeToFile(readFilePath, blocks, writeFilePath)
+}
+
+func (p *PulumiParser) writeToFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	// #nosec G304
+	src, err := os.ReadFile(readFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s because %s", readFilePath, err)
+	}
+	fileLines := utils.GetLinesFromBytes(src)
+	edits := make([]yamlUtils.MapTagsEdit, 0)
+	for _, block := range blocks {
+		if !block.IsBlockTaggable() {
+			continue
+		}
+		diff := block.CalculateTagsDiff()
+		edits = append(edits, yamlUtils.MapTagsEdit{
+			Scope:   block.GetLines(),
+			Path:    []string{block.GetResourceName(), PropertiesAttributeName, block.GetTagsAttributeName()},
+			Added:   diff.Added,
+			Updated: diff.Updated,
+		})
+	}
+	fileLines = yamlUtils.ApplyMapTagsEdits(fileLines, edits)
+
+	return os.WriteFile(writeFilePath, []byte(strings.Join(fileLines, "\n")), 0600)
+}
@@ -0,0 +1,95 @@
+package structure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPulumiParser_ValidFile(t *testing.T) {
+	directory := "../../../tests/pulumi/resources/yaml_program"
+	p := PulumiParser{}
+	p.Init(directory, nil)
+	assert.True(t, p.ValidFile(filepath.Join(directory, "Pulumi.yaml")))
+	assert.False(t, p.ValidFile(filepath.Join(directory, "expected.yaml")))
+}
+
+func TestPulumiParser_ParseFile(t *testing.T) {
+	directory := "../../../tests/pulumi/resources/yaml_program"
+	p := PulumiParser{}
+	p.Init(directory, nil)
+	blocks, err := p.ParseFile(filepath.Join(directory, "Pulumi.yaml"))
+	if err != nil {
+		t.Errorf("ParseFile() error = %v", err)
+		return
+	}
+	var names []string
+	blocksByName := map[string]*PulumiBlock{}
+	for _, block := range blocks {
+		names = append(names, block.GetResourceID())
+		blocksByName[block.GetResourceID()] = block.(*PulumiBlock)
+	}
+	assert.Equal(t, []string{"site-bucket", "site-role", "logs-bucket", "bucket-policy", "legacy-vpc", "shared-vpc"}, names)
+
+	siteBucket := blocksByName["site-bucket"]
+	assert.Equal(t, "aws", siteBucket.Provider)
+	assert.Equal(t, "tags", siteBucket.GetTagsAttributeName())
+	assert.Equal(t, structure.Lines{Start: 11, End: 18}, siteBucket.GetLines())
+	assert.Equal(t, structure.Lines{Start: 16, End: 18}, siteBucket.GetTagsLines())
+	assert.Equal(t, []tags.ITag{
+		&tags.Tag{Key: "env", Value: "dev"},
+		&tags.Tag{Key: "team", Value: "web"},
+	}, siteBucket.GetExistingTags())
+
+	assert.Equal(t, "labels", blocksByName["logs-bucket"].GetTagsAttributeName())
+	assert.True(t, blocksByName["site-role"].IsBlockTaggable())
+	assert.False(t, blocksByName["bucket-policy"].IsBlockTaggable())
+	assert.False(t, blocksByName["shared-vpc"].IsBlockTaggable())
+	assert.Equal(t, []string{"legacy-vpc"}, p.GetSkipResourcesByComment())
+}
+
+func TestPulumiParser_WriteFile(t *testing.T) {
+	directory := "../../../tests/pulumi/resources/yaml_program"
+	p := PulumiParser{}
+	p.Init(directory, nil)
+	filePath := filepath.Join(directory, "Pulumi.yaml")
+	blocks, err := p.ParseFile(filePath)
+	if err != nil {
+		t.Errorf("ParseFile() error = %v", err)
+		return
+	}
+	for _, block := range blocks {
+		if !block.IsBlockTaggable() || block.GetResourceID() == "legacy-vpc" {
+			continue
+		}
+		block.AddNewTags([]tags.ITag{
+			&tags.Tag{Key: "env", Value: "prod"},
+			&tags.Tag{Key: "yor_trace", Value: "7e8f9a0b-1c2d-4e3f-8a4b-5c6d7e8f9a0b"},
+			&tags.Tag{Key: "git_file", Value: "Pulumi.yaml"},
+		})
+	}
+	f, _ := os.CreateTemp(directory, "Pulumi.*.yaml")
+	defer func() { _ = os.Remove(f.Name()) }()
+	err = p.WriteFile(filePath, blocks, f.Name())
+	if err != nil {
+		t.Errorf("WriteFile() error = %v", err)
+		return
+	}
+
+	expected, _ := os.ReadFile(filepath.Join(directory, "expected.yaml"))
+	actual, _ := os.ReadFile(f.Name())
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestIsTaggableResourceType(t *testing.T) {
+	assert.True(t, IsTaggableResourceType("aws:s3/bucket:Bucket"))
+	assert.True(t, IsTaggableResourceType("aws:ec2:SecurityGroup"))
+	assert.True(t, IsTaggableResourceType("gcp:storage:Bucket"))
+	assert.True(t, IsTaggableResourceType("azure-native:storage:StorageAccount"))
+	assert.False(t, IsTaggableResourceType("aws:s3:BucketPolicy"))
+	assert.False(t, IsTaggableResourceType("kubernetes:apps/v1:Deployment"))
+}
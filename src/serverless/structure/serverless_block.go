@@ -13,6 +13,16 @@ func (b *ServerlessBlock) GetFramework() string {
 	return "Serverless"
 }
 
+// GetCloudProvider returns the provider of the functions, which are only tagged for AWS
+func (b *ServerlessBlock) GetCloudProvider() string {
+	return structure.AWSProvider
+}
+
+// MigrateTags removes and renames the existing tags of the function
+func (b *ServerlessBlock) MigrateTags(migrations *structure.TagMigrations) {
+	b.ApplyTagMigrations(migrations, b.ExitingTags)
+}
+
 func (b *ServerlessBlock) UpdateTags() {
 	if !b.IsTaggable {
 		return
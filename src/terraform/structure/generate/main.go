@@ -0,0 +1,45 @@
+// Command generate writes the taggable resource types of provider schema snapshots, the output of
+// terraform providers schema -json, to tf_taggable.go:
+//
+//	terraform providers schema -json > schemas/aws.json
+//	YOR_PROVIDER_SCHEMAS=$PWD/schemas go generate ./src/terraform/structure
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	tfStructure "github.com/bridgecrewio/yor/src/terraform/structure"
+)
+
+func main() {
+	out := flag.String("out", "tf_taggable.go", "the generated file")
+	flag.Parse()
+	var schemasPaths []string
+	for _, schemasPath := range flag.Args() {
+		// go generate passes an empty argument when YOR_PROVIDER_SCHEMAS is not set
+		if schemasPath != "" {
+			schemasPaths = append(schemasPaths, schemasPath)
+		}
+	}
+	if len(schemasPaths) == 0 {
+		fmt.Fprintf(os.Stderr, "usage: generate -out tf_taggable.go <schemas file or directory>...\n%v is not regenerated\n", *out)
+		return
+	}
+	tagAttributes := make(map[string]string)
+	for _, schemasPath := range schemasPaths {
+		schemaTagAttributes, err := tfStructure.ReadProviderSchemas(schemasPath, tfStructure.ProviderToTagAttribute)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read the provider schemas of %v: %v\n", schemasPath, err)
+			os.Exit(1)
+		}
+		for resourceType, tagAttribute := range schemaTagAttributes {
+			tagAttributes[resourceType] = tagAttribute
+		}
+	}
+	if err := os.WriteFile(*out, tfStructure.GenerateTaggableResourceTypes(tagAttributes), 0600); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write %v: %v\n", *out, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,176 @@
+package structure
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// tf_taggable.go is regenerated from snapshots of the provider schemas when YOR_PROVIDER_SCHEMAS is set, otherwise the
+// generator leaves it as is
+//go:generate go run ./generate -out tf_taggable.go $YOR_PROVIDER_SCHEMAS
+
+// ProviderSchemasEnvKey is the environment variable of the path of the provider schema snapshots, which is used when
+// the provider-schemas option is not set
+const ProviderSchemasEnvKey = "YOR_PROVIDER_SCHEMAS"
+
+// tagsAttributeCandidates are the names of the attributes which hold the tags of the resources whose provider holds
+// its tags in another attribute, e.g. the resource_labels of google_container_cluster
+var tagsAttributeCandidates = []string{"tags", "labels", "freeform_tags", "resource_labels", "user_labels"}
+
+// schemaTagAttributes maps the resource types of the loaded provider schemas to their tags attribute, which is empty
+// for resource types which do not accept tags
+var schemaTagAttributes = map[string]string{}
+var schemaProviders = map[string]bool{}
+var schemasLock sync.RWMutex
+
+// providerSchemas is the output of terraform providers schema -json
+type providerSchemas struct {
+	ProviderSchemas map[string]struct {
+		ResourceSchemas map[string]struct {
+			Block struct {
+				Attributes map[string]schemaAttribute `json:"attributes"`
+				// BlockTypes are the nested blocks of the resource, such as the tag blocks of aws_autoscaling_group
+				BlockTypes map[string]struct {
+					Block struct {
+						Attributes map[string]schemaAttribute `json:"attributes"`
+					} `json:"block"`
+				} `json:"block_types"`
+			} `json:"block"`
+		} `json:"resource_schemas"`
+	} `json:"provider_schemas"`
+}
+
+type schemaAttribute struct {
+	// Type is a type name, e.g. "string", or a list of the collection type and the element type, e.g. ["map","string"]
+	Type     interface{} `json:"type"`
+	Optional bool        `json:"optional"`
+	Required bool        `json:"required"`
+}
+
+// acceptsTags checks if the attribute can be configured with tags of the given format, i.e. a map of strings, or a list
+// or set of strings for providers which hold their tags as lists
+func (a schemaAttribute) acceptsTags(format TagsFormat) bool {
+	collectionType, ok := a.Type.([]interface{})
+	if !a.Optional && !a.Required || !ok || len(collectionType) != 2 || collectionType[1] != "string" {
+		return false
+	}
+	if format == ListTagsFormat {
+		return collectionType[0] == "list" || collectionType[0] == "set"
+	}
+	return collectionType[0] == "map"
+}
+
+// ReadProviderSchemas reads the snapshots of the provider schemas in the path, which is a file or a directory of JSON
+// files, and returns the tags attribute of each of their resource types, or the name of their tag blocks for the
+// resource types of ResourceTypeToTagBlockName. Resource types which do not accept tags are mapped to an empty
+// attribute. The providers are the built-in providers and the providers of the config file.
+func ReadProviderSchemas(schemasPath string, providerTagAttributes map[string]*ProviderTagAttribute) (map[string]string, error) {
+	info, err := os.Stat(schemasPath)
+	if err != nil {
+		return nil, err
+	}
+	schemaFiles := []string{schemasPath}
+	if info.IsDir() {
+		schemaFiles, err = filepath.Glob(filepath.Join(schemasPath, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(schemaFiles)
+	}
+	tagAttributes := make(map[string]string)
+	for _, schemaFile := range schemaFiles {
+		// #nosec G304
+		src, err := os.ReadFile(schemaFile)
+		if err != nil {
+			return nil, err
+		}
+		schemas := providerSchemas{}
+		if err = json.Unmarshal(src, &schemas); err != nil {
+			return nil, fmt.Errorf("failed to parse the provider schemas of %v: %v", schemaFile, err)
+		}
+		for providerSource, providerSchema := range schemas.ProviderSchemas {
+			tagAttribute, ok := providerTagAttributes[path.Base(providerSource)]
+			for resourceType, resourceSchema := range providerSchema.ResourceSchemas {
+				tagAttributes[resourceType] = ""
+				if !ok {
+					continue
+				}
+				if tagBlockName, isTagBlock := ResourceTypeToTagBlockName[resourceType]; isTagBlock {
+					if tagBlock, exists := resourceSchema.Block.BlockTypes[tagBlockName]; exists {
+						_, hasKey := tagBlock.Block.Attributes["key"]
+						_, hasValue := tagBlock.Block.Attributes["value"]
+						if hasKey && hasValue {
+							tagAttributes[resourceType] = tagBlockName
+						}
+					}
+					continue
+				}
+				for _, attributeName := range append([]string{tagAttribute.Name}, tagsAttributeCandidates...) {
+					if attribute, exists := resourceSchema.Block.Attributes[attributeName]; exists && attribute.acceptsTags(tagAttribute.Format) {
+						tagAttributes[resourceType] = attributeName
+						break
+					}
+				}
+			}
+		}
+	}
+	return tagAttributes, nil
+}
+
+// LoadProviderSchemas loads the snapshots of the provider schemas in the path, which resolve the taggability and the
+// tags attribute of the resources of their providers without downloading and launching the provider plugins
+func LoadProviderSchemas(schemasPath string, providerTagAttributes map[string]*ProviderTagAttribute) error {
+	tagAttributes, err := ReadProviderSchemas(schemasPath, providerTagAttributes)
+	if err != nil {
+		return err
+	}
+	schemasLock.Lock()
+	defer schemasLock.Unlock()
+	for resourceType, tagAttribute := range tagAttributes {
+		schemaTagAttributes[resourceType] = tagAttribute
+		schemaProviders[getProviderFromResourceType(resourceType)] = true
+	}
+	return nil
+}
+
+// getSchemaTagAttribute returns the tags attribute of the resource type by the loaded provider schemas. The second
+// value is false if the schemas do not include the resource type.
+func getSchemaTagAttribute(resourceType string) (string, bool) {
+	schemasLock.RLock()
+	defer schemasLock.RUnlock()
+	tagAttribute, ok := schemaTagAttributes[resourceType]
+	return tagAttribute, ok
+}
+
+// isProviderInSchemas checks if the loaded provider schemas include the provider, whose plugin is then not required
+func isProviderInSchemas(provider string) bool {
+	schemasLock.RLock()
+	defer schemasLock.RUnlock()
+	return schemaProviders[provider]
+}
+
+// GenerateTaggableResourceTypes returns the source of tf_taggable.go, which lists the taggable resource types of the
+// provider schemas
+func GenerateTaggableResourceTypes(tagAttributes map[string]string) []byte {
+	resourceTypes := make([]string, 0, len(tagAttributes))
+	for resourceType, tagAttribute := range tagAttributes {
+		if tagAttribute != "" {
+			resourceTypes = append(resourceTypes, resourceType)
+		}
+	}
+	sort.Strings(resourceTypes)
+	var sb strings.Builder
+	sb.WriteString("// Code generated by go generate from the provider schema snapshots; DO NOT EDIT.\n\n")
+	sb.WriteString("package structure\n\nvar TfTaggableResourceTypes = []string{\n")
+	for _, resourceType := range resourceTypes {
+		sb.WriteString(fmt.Sprintf("\t%q,\n", resourceType))
+	}
+	sb.WriteString("}\n")
+	return []byte(sb.String())
+}
@@ -0,0 +1,116 @@
+package structure
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func resetProviderSchemas() {
+	schemasLock.Lock()
+	defer schemasLock.Unlock()
+	schemaTagAttributes = map[string]string{}
+	schemaProviders = map[string]bool{}
+}
+
+func TestReadProviderSchemas(t *testing.T) {
+	schemasPath := "../../../tests/terraform/resources/provider_schemas/schemas"
+	tagAttributes, err := ReadProviderSchemas(schemasPath, ProviderToTagAttribute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{
+		"aws_autoscaling_group":    "tag",
+		"aws_s3_bucket":            "tags",
+		"aws_s3_bucket_policy":     "",
+		"google_container_cluster": "resource_labels",
+		"digitalocean_droplet":     "tags",
+		"digitalocean_ssh_key":     "",
+	}, tagAttributes)
+
+	_, err = ReadProviderSchemas("../../../tests/terraform/resources/provider_schemas/main.tf", ProviderToTagAttribute)
+	assert.Error(t, err)
+
+	t.Run("providers of the config file", func(t *testing.T) {
+		providerTagAttributes := map[string]*ProviderTagAttribute{"google": {Name: "labels"}}
+		tagAttributes, err := ReadProviderSchemas(schemasPath, providerTagAttributes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "", tagAttributes["digitalocean_droplet"])
+		providerTagAttributes["digitalocean"] = &ProviderTagAttribute{Name: "tags", Format: ListTagsFormat}
+		tagAttributes, err = ReadProviderSchemas(schemasPath, providerTagAttributes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "tags", tagAttributes["digitalocean_droplet"])
+	})
+}
+
+func TestGenerateTaggableResourceTypes(t *testing.T) {
+	generated := GenerateTaggableResourceTypes(map[string]string{
+		"google_container_cluster": "resource_labels",
+		"aws_s3_bucket":            "tags",
+		"aws_s3_bucket_policy":     "",
+	})
+	assert.Equal(t, `// Code generated by go generate from the provider schema snapshots; DO NOT EDIT.
+
+package structure
+
+var TfTaggableResourceTypes = []string{
+	"aws_s3_bucket",
+	"google_container_cluster",
+}
+`, string(generated))
+}
+
+func TestTerraformParser_ProviderSchemas(t *testing.T) {
+	rootDir := "../../../tests/terraform/resources/provider_schemas"
+	defer resetProviderSchemas()
+	p := &TerraformParser{}
+	p.Init(rootDir, map[string]string{"provider-schemas": filepath.Join(rootDir, "schemas")})
+	assert.True(t, isProviderInSchemas("digitalocean"))
+	assert.False(t, isProviderInSchemas("azurerm"))
+
+	parsedBlocks, err := p.ParseFile(filepath.Join(rootDir, "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	taggable := map[string]bool{}
+	tagsAttributeNames := map[string]string{}
+	for _, block := range parsedBlocks {
+		taggable[block.GetResourceID()] = block.IsBlockTaggable()
+		tagsAttributeNames[block.GetResourceID()] = block.GetTagsAttributeName()
+	}
+	assert.Equal(t, map[string]bool{
+		"aws_s3_bucket.bucket":             true,
+		"aws_s3_bucket_policy.policy":      false,
+		"aws_sqs_queue.queue":              true,
+		"google_container_cluster.cluster": true,
+		"digitalocean_droplet.web":         true,
+		"digitalocean_ssh_key.key":         false,
+	}, taggable)
+	assert.Equal(t, "resource_labels", tagsAttributeNames["google_container_cluster.cluster"])
+	assert.Equal(t, "tags", tagsAttributeNames["digitalocean_droplet.web"])
+}
+
+func TestTerraformParser_ProviderSchemasTagBlocks(t *testing.T) {
+	rootDir := "../../../tests/terraform/resources/asg"
+	defer resetProviderSchemas()
+	p := &TerraformParser{}
+	p.Init(rootDir, map[string]string{"provider-schemas": "../../../tests/terraform/resources/provider_schemas/schemas"})
+	parsedBlocks, err := p.ParseFile(filepath.Join(rootDir, "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	autoscalingGroups := 0
+	for _, block := range parsedBlocks {
+		if block.GetResourceType() == "aws_autoscaling_group" {
+			autoscalingGroups++
+			assert.True(t, block.IsBlockTaggable(), block.GetResourceID())
+			assert.Equal(t, "tag", block.GetTagsAttributeName(), block.GetResourceID())
+		}
+	}
+	assert.Equal(t, 2, autoscalingGroups)
+}
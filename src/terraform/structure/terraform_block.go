@@ -1,19 +1,196 @@
 package structure
 
 import (
+	"fmt"
+	"os"
+	"regexp"
 	"strings"
 
+	"github.com/bridgecrewio/yor/src/common"
+	"github.com/bridgecrewio/yor/src/common/logger"
 	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"gopkg.in/yaml.v2"
 )
 
 type TerraformBlock struct {
 	structure.Block
 	HclSyntaxBlock *hclsyntax.Block
+	// ProviderDefaultTags are the tags the provider configuration of the resource applies through its default tags
+	ProviderDefaultTags []tags.ITag
+	providerConfig      *providerConfig
+	// tagAttribute describes how the provider of the resource holds its tags
+	tagAttribute *ProviderTagAttribute
+	// MetaArgument is the count or for_each meta-argument of blocks with multiple instances
+	MetaArgument string
+	// nestedTagsBlocks are the tags of the nested resources of the block, e.g. root_block_device.tags
+	nestedTagsBlocks []*TerraformBlock
+	// jsonLiteralTagKeys are the keys of the tags written in the tags object of blocks of .tf.json files
+	jsonLiteralTagKeys map[string]bool
+	// ModuleSource is the source of module calls
+	ModuleSource string
+	// UntaggableReason is the reason a module call with a remote source is not tagged
+	UntaggableReason string
 }
 
-var ProviderToTagAttribute = map[string]string{"aws": "tags", "azurerm": "tags", "google": "labels", "oci": "freeform_tags", "alicloud": "tags"}
+// TagsFormat is the shape of the tags attribute of the resources of a provider
+type TagsFormat string
+
+const (
+	// MapTagsFormat tags are a map of the tag keys to their values, e.g. tags = { env = "prod" }
+	MapTagsFormat TagsFormat = "map"
+	// ListTagsFormat tags are a list of strings of the tag keys and values, e.g. tags = ["env:prod"]
+	ListTagsFormat TagsFormat = "list"
+)
+
+// ProviderTagAttribute describes how the resources of a provider hold their tags
+type ProviderTagAttribute struct {
+	Name   string     `yaml:"tags_attribute"`
+	Format TagsFormat `yaml:"tags_format"`
+	// Separator separates the key from the value of tags in the list format
+	Separator string `yaml:"separator"`
+	// InvalidKeyCharacters and InvalidValueCharacters are patterns of the characters which the provider does not accept
+	// in the keys and values of tags, these characters are replaced with an underscore
+	InvalidKeyCharacters   string `yaml:"invalid_key_characters"`
+	InvalidValueCharacters string `yaml:"invalid_value_characters"`
+	invalidKeyRegex        *regexp.Regexp
+	invalidValueRegex      *regexp.Regexp
+}
+
+// ProviderToTagAttribute maps the built-in providers to the attribute which holds the tags of their resources. Each
+// parser copies it and adds the providers of its config file. vsphere is not supported, the tags of its resources are
+// the IDs of vsphere_tag resources rather than keys and values.
+var ProviderToTagAttribute = map[string]*ProviderTagAttribute{
+	"aws":          {Name: "tags"},
+	"azurerm":      {Name: "tags"},
+	"google":       {Name: "labels"},
+	"oci":          {Name: "freeform_tags"},
+	"alicloud":     {Name: "tags"},
+	"tencentcloud": {Name: "tags"},
+	"exoscale":     {Name: "labels"},
+	"huaweicloud": {
+		Name:                   "tags",
+		InvalidKeyCharacters:   `[^\p{L}\p{N}_\-]`,
+		InvalidValueCharacters: `[^\p{L}\p{N}_.\-]`,
+	},
+	"ibm": {
+		Name:                   "tags",
+		Format:                 ListTagsFormat,
+		Separator:              ":",
+		InvalidKeyCharacters:   `[^A-Za-z0-9 _.\-]`,
+		InvalidValueCharacters: `[^A-Za-z0-9 _.:\-]`,
+	},
+	"digitalocean": {
+		Name:                   "tags",
+		Format:                 ListTagsFormat,
+		Separator:              ":",
+		InvalidKeyCharacters:   `[^A-Za-z0-9_\-]`,
+		InvalidValueCharacters: `[^A-Za-z0-9_:\-]`,
+	},
+	"linode": {
+		Name:      "tags",
+		Format:    ListTagsFormat,
+		Separator: ":",
+	},
+}
+
+// IsListFormat checks if the tags of the resources of the provider are a list of strings
+func (a *ProviderTagAttribute) IsListFormat() bool {
+	return a != nil && a.Format == ListTagsFormat
+}
+
+// FormatListTag returns the element of a tags list which holds the tag
+func (a *ProviderTagAttribute) FormatListTag(tag tags.ITag) string {
+	if tag.GetValue() == "" {
+		return tag.GetKey()
+	}
+	return tag.GetKey() + a.Separator + tag.GetValue()
+}
+
+// ParseListTag returns the tag an element of a tags list holds, elements without a separator are tags without a value
+func (a *ProviderTagAttribute) ParseListTag(element string) tags.ITag {
+	if a.Separator != "" {
+		if index := strings.Index(element, a.Separator); index > 0 {
+			return tags.Init(element[:index], element[index+len(a.Separator):])
+		}
+	}
+	return tags.Init(element, "")
+}
+
+func (a *ProviderTagAttribute) compile() error {
+	var err error
+	if a.InvalidKeyCharacters != "" {
+		if a.invalidKeyRegex, err = regexp.Compile(a.InvalidKeyCharacters); err != nil {
+			return err
+		}
+	}
+	if a.InvalidValueCharacters != "" {
+		if a.invalidValueRegex, err = regexp.Compile(a.InvalidValueCharacters); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	for _, tagAttribute := range ProviderToTagAttribute {
+		_ = tagAttribute.compile()
+	}
+}
+
+// UnsupportedProviders maps the providers whose resources are not tagged to the reason they are not supported
+var UnsupportedProviders = map[string]string{
+	"vsphere": "the tags of vsphere resources are the IDs of vsphere_tag resources rather than keys and values",
+}
+
+// LoadProviderTagAttributes adds the providers of the terraform_providers section of the config file to the provider
+// tag attributes, or overrides the providers with the same name
+func LoadProviderTagAttributes(configFilePath string, providerTagAttributes map[string]*ProviderTagAttribute) error {
+	// #nosec G304
+	confBytes, err := os.ReadFile(configFilePath)
+	if err != nil {
+		return err
+	}
+	config := struct {
+		TerraformProviders []struct {
+			ProviderName         string `yaml:"name"`
+			ProviderTagAttribute `yaml:",inline"`
+		} `yaml:"terraform_providers"`
+	}{}
+	if err = yaml.Unmarshal(confBytes, &config); err != nil {
+		return err
+	}
+	for _, provider := range config.TerraformProviders {
+		tagAttribute := provider.ProviderTagAttribute
+		if provider.ProviderName == "" || tagAttribute.Name == "" {
+			return fmt.Errorf("terraform providers must have a name and a tags_attribute")
+		}
+		switch tagAttribute.Format {
+		case "":
+			tagAttribute.Format = MapTagsFormat
+		case ListTagsFormat:
+			if tagAttribute.Separator == "" {
+				tagAttribute.Separator = ":"
+			}
+		case MapTagsFormat:
+		default:
+			return fmt.Errorf("unknown tags_format %v of terraform provider %v", tagAttribute.Format, provider.ProviderName)
+		}
+		if err = tagAttribute.compile(); err != nil {
+			return fmt.Errorf("invalid characters pattern of terraform provider %v: %v", provider.ProviderName, err)
+		}
+		providerTagAttributes[provider.ProviderName] = &tagAttribute
+	}
+	return nil
+}
+
+// ResourceTypeToTagBlockName maps the resource types which take their tags as repeated blocks, rather than as a map, to
+// the type of these blocks
+var ResourceTypeToTagBlockName = map[string]string{"aws_autoscaling_group": "tag"}
 
 const ResourceBlockType = "resource"
 const ModuleBlockType = "module"
@@ -29,6 +206,99 @@ func (b *TerraformBlock) GetResourceID() string {
 	return strings.Join(b.HclSyntaxBlock.Labels, ".")
 }
 
+// AddNewTags adds the new tags which the provider configuration of the resource does not already apply. Tags the
+// resource sets itself are still updated, as they take precedence over the default tags. Template values which refer to each or count are only added to blocks with for_each or count.
+func (b *TerraformBlock) AddNewTags(newTags []tags.ITag) {
+	var filteredTags []tags.ITag
+	for _, tag := range newTags {
+		if isTemplateTag(tag) && !isTemplateValid(tag.GetValue(), b.MetaArgument) {
+			logger.Debug(fmt.Sprintf("Skipping tag %v of %v, its value %v refers to a meta-argument the block does not have", tag.GetKey(), b.GetResourceID(), tag.GetValue()))
+			continue
+		}
+		if isTagKeyIn(tag.GetKey(), b.ExitingTags) || !isDefaultTag(tag, b.ProviderDefaultTags) {
+			filteredTags = append(filteredTags, tag)
+		}
+	}
+	b.Block.AddNewTags(filteredTags)
+}
+
+// MigrateTags removes and renames the existing tags written literally in the tags attribute or the tag blocks of the
+// resource. Tags of referenced locals and variables are not migrated, as other resources may use them as well.
+func (b *TerraformBlock) MigrateTags(migrations *structure.TagMigrations) {
+	literalTagKeys := b.getLiteralTagKeys()
+	var migratableTags []tags.ITag
+	for _, tag := range b.ExitingTags {
+		if literalTagKeys[tag.GetKey()] {
+			migratableTags = append(migratableTags, tag)
+		} else if !migrations.IsEmpty() && migrations.IsMigrated(tag.GetKey()) {
+			logger.Warning(fmt.Sprintf("Tag %v of %v in %v is not migrated, it is not written literally in its %v", tag.GetKey(), b.GetResourceID(), b.GetFilePath(), b.GetTagsAttributeName()))
+		}
+	}
+	b.ApplyTagMigrations(migrations, migratableTags)
+}
+
+// getLiteralTagKeys returns the keys of the tags written in the maps of the tags attribute of the block, or in its tag
+// blocks. In .tf.json files, these are the keys of the tags object of the block. The tags of data sources and list
+// formatted tags are not returned.
+func (b *TerraformBlock) getLiteralTagKeys() map[string]bool {
+	literalTagKeys := make(map[string]bool)
+	if strings.HasSuffix(b.FilePath, common.TfJSONFileType.Extension) {
+		for key := range b.jsonLiteralTagKeys {
+			literalTagKeys[key] = true
+		}
+		return literalTagKeys
+	}
+	if b.HclSyntaxBlock == nil || b.HclSyntaxBlock.Type == DataBlockType {
+		return literalTagKeys
+	}
+	if b.tagAttribute.IsListFormat() {
+		return literalTagKeys
+	}
+	addStringValue := func(expr hcl.Expression) {
+		if value, diagnostics := expr.Value(nil); !diagnostics.HasErrors() && value.Type() == cty.String && value.IsKnown() && !value.IsNull() {
+			literalTagKeys[value.AsString()] = true
+		}
+	}
+	if tagBlockName, ok := ResourceTypeToTagBlockName[b.GetResourceType()]; ok {
+		for _, tagBlock := range b.HclSyntaxBlock.Body.Blocks {
+			if keyAttribute, ok := tagBlock.Body.Attributes["key"]; ok && tagBlock.Type == tagBlockName {
+				addStringValue(keyAttribute.Expr)
+			}
+		}
+		return literalTagKeys
+	}
+	tagsAttribute, ok := b.HclSyntaxBlock.Body.Attributes[b.TagsAttributeName]
+	if !ok {
+		return literalTagKeys
+	}
+	_ = hclsyntax.VisitAll(tagsAttribute.Expr, func(node hclsyntax.Node) hcl.Diagnostics {
+		if objectExpr, ok := node.(*hclsyntax.ObjectConsExpr); ok {
+			for _, item := range objectExpr.Items {
+				addStringValue(item.KeyExpr)
+			}
+		}
+		return nil
+	})
+	return literalTagKeys
+}
+
+func isDefaultTag(tag tags.ITag, defaultTags []tags.ITag) bool {
+	for _, defaultTag := range defaultTags {
+		if defaultTag.GetKey() == tag.GetKey() && defaultTag.GetValue() == tag.GetValue() {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *TerraformBlock) GetModuleSource() string {
+	return b.ModuleSource
+}
+
+func (b *TerraformBlock) GetUntaggableReason() string {
+	return b.UntaggableReason
+}
+
 func (b *TerraformBlock) AddHclSyntaxBlock(hclSyntaxBlock *hclsyntax.Block) {
 	b.HclSyntaxBlock = hclSyntaxBlock
 }
@@ -55,18 +325,55 @@ func (b *TerraformBlock) GetTagsLines() structure.Lines {
 			return structure.Lines{Start: attr.SrcRange.Start.Line, End: attr.SrcRange.End.Line}
 		}
 	}
-	return structure.Lines{Start: -1, End: -1}
+	tagsLines := structure.Lines{Start: -1, End: -1}
+	for _, block := range b.HclSyntaxBlock.Body.Blocks {
+		if block.Type != b.TagsAttributeName {
+			continue
+		}
+		if tagsLines.Start == -1 {
+			tagsLines.Start = block.Range().Start.Line
+		}
+		tagsLines.End = block.Range().End.Line
+	}
+	return tagsLines
 }
 func (b *TerraformBlock) GetSeparator() string {
 	return "="
 }
 
 func (b *TerraformBlock) IsGCPBlock() bool {
-	return strings.HasPrefix(b.GetResourceID(), "google_") || b.GetTagsAttributeName() == ProviderToTagAttribute["google"]
+	if b.tagAttribute != nil {
+		// other providers, such as exoscale, hold their tags in labels too
+		return b.GetTagProvider() == "google"
+	}
+	return strings.HasPrefix(b.GetResourceID(), "google_") || b.GetTagsAttributeName() == ProviderToTagAttribute["google"].Name
+}
+
+// GetTagProvider returns the terraform provider of the resource
+func (b *TerraformBlock) GetTagProvider() string {
+	return getProviderFromResourceType(b.GetResourceType())
 }
 
+// GetInvalidTagCharacters returns the patterns of the characters the terraform provider of the resource does not accept
+// in the keys and values of tags, which are replaced with an underscore along with the tag constraints of its cloud
+func (b *TerraformBlock) GetInvalidTagCharacters() (*regexp.Regexp, *regexp.Regexp) {
+	if b.tagAttribute == nil {
+		return nil, nil
+	}
+	return b.tagAttribute.invalidKeyRegex, b.tagAttribute.invalidValueRegex
+}
+
+// GetResourceName returns the name of the block, the names of blocks with count or for_each are templates of the name of
+// each instance, e.g. "bucket-${each.key}"
 func (b *TerraformBlock) GetResourceName() string {
 	resourceID := b.GetResourceID()
 	resourceType := b.GetResourceType()
-	return strings.ReplaceAll(resourceID, strings.Join([]string{resourceType, ""}, "."), "")
+	resourceName := strings.ReplaceAll(resourceID, strings.Join([]string{resourceType, ""}, "."), "")
+	switch b.MetaArgument {
+	case ForEachMetaArgument:
+		return resourceName + "-${each.key}"
+	case CountMetaArgument:
+		return resourceName + "-${count.index}"
+	}
+	return resourceName
 }
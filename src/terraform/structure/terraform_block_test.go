@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging"
 	"github.com/bridgecrewio/yor/src/common/tagging/code2cloud"
 	"github.com/bridgecrewio/yor/src/common/tagging/gittag"
 	"github.com/bridgecrewio/yor/src/common/tagging/tags"
@@ -215,3 +216,71 @@ func TestTerraformBlock(t *testing.T) {
 		assert.True(t, gcpBlock.IsGCPBlock())
 	})
 }
+
+func TestProviderTagAttribute(t *testing.T) {
+	t.Run("list tags", func(t *testing.T) {
+		tagAttribute := ProviderToTagAttribute["digitalocean"]
+		assert.True(t, tagAttribute.IsListFormat())
+		assert.False(t, ProviderToTagAttribute["aws"].IsListFormat())
+		assert.Equal(t, tags.Init("env", "prod:eu"), tagAttribute.ParseListTag("env:prod:eu"))
+		assert.Equal(t, tags.Init("web", ""), tagAttribute.ParseListTag("web"))
+		assert.Equal(t, "env:prod", tagAttribute.FormatListTag(tags.Init("env", "prod")))
+		assert.Equal(t, "web", tagAttribute.FormatListTag(tags.Init("web", "")))
+	})
+
+	t.Run("sanitize tags with the tag constraints", func(t *testing.T) {
+		block := &TerraformBlock{
+			Block:          structure.Block{Type: "digitalocean_droplet"},
+			HclSyntaxBlock: &hclsyntax.Block{Labels: []string{"digitalocean_droplet", "web"}},
+			tagAttribute:   ProviderToTagAttribute["digitalocean"],
+		}
+		block.AddNewTags([]tags.ITag{
+			&tags.Tag{Key: "git_last_modified_at", Value: "2021-01-08 00:00:00"},
+			&tags.Tag{Key: "version", Value: "v1.0-${var.build}", IsTemplate: true},
+		})
+		tagging.ValidateNewTags(block)
+		assert.Equal(t, []tags.ITag{
+			&tags.Tag{Key: "version", Value: "v1_0-${var.build}", IsTemplate: true},
+			&tags.Tag{Key: "git_last_modified_at", Value: "2021-01-08_00:00:00"},
+		}, block.GetNewTags())
+		assert.Equal(t, []structure.TagViolation{
+			{Provider: "digitalocean", Key: "version", Value: "v1.0-${var.build}", Violation: "value has invalid characters", Resolution: "sanitized to version=v1_0-${var.build}"},
+			{Provider: "digitalocean", Key: "git_last_modified_at", Value: "2021-01-08 00:00:00", Violation: "value has invalid characters", Resolution: "sanitized to git_last_modified_at=2021-01-08_00:00:00"},
+		}, block.GetTagViolations())
+
+		awsBlock := &TerraformBlock{
+			Block:          structure.Block{Type: "aws_s3_bucket"},
+			HclSyntaxBlock: &hclsyntax.Block{Labels: []string{"aws_s3_bucket", "data"}},
+			tagAttribute:   ProviderToTagAttribute["aws"],
+		}
+		awsBlock.AddNewTags([]tags.ITag{&tags.Tag{Key: "git:file", Value: "main.tf"}})
+		tagging.ValidateNewTags(awsBlock)
+		assert.Equal(t, []tags.ITag{&tags.Tag{Key: "git:file", Value: "main.tf"}}, awsBlock.GetNewTags())
+	})
+}
+
+func TestTemplateValues(t *testing.T) {
+	t.Run("detect template values", func(t *testing.T) {
+		assert.True(t, IsTemplateValue("${each.key}"))
+		assert.True(t, IsTemplateValue("logs-${count.index}"))
+		assert.False(t, IsTemplateValue("logs"))
+		assert.False(t, IsTemplateValue("${"))
+		assert.False(t, IsTemplateValue("$${each.key}"))
+	})
+
+	t.Run("templates of instances require the meta-argument", func(t *testing.T) {
+		assert.True(t, isTemplateValid("${each.key}", ForEachMetaArgument))
+		assert.False(t, isTemplateValid("${each.key}", CountMetaArgument))
+		assert.False(t, isTemplateValid("${count.index}", ""))
+		assert.True(t, isTemplateValid("${var.owner}", ""))
+	})
+
+	t.Run("read quoted values", func(t *testing.T) {
+		value, _ := parseQuotedValue(`"${lookup(var.owners, "web")}"`)
+		assert.Equal(t, `${lookup(var.owners, "web")}`, value)
+		value, _ = parseQuotedValue(`"$${path.cwd}\"@example.com"`)
+		assert.Equal(t, `${path.cwd}"@example.com`, value)
+		_, ok := parseQuotedValue("var.owner")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,219 @@
+package structure
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+const mergeFunctionName = "merge"
+
+// terraformFunctions are the functions of the Terraform language which tags expressions commonly use
+var terraformFunctions = map[string]function.Function{
+	"coalesce":  stdlib.CoalesceFunc,
+	"concat":    stdlib.ConcatFunc,
+	"format":    stdlib.FormatFunc,
+	"join":      stdlib.JoinFunc,
+	"keys":      stdlib.KeysFunc,
+	"lookup":    stdlib.LookupFunc,
+	"lower":     stdlib.LowerFunc,
+	"merge":     stdlib.MergeFunc,
+	"replace":   stdlib.ReplaceFunc,
+	"title":     stdlib.TitleFunc,
+	"tomap":     stdlib.MakeToFunc(cty.Map(cty.DynamicPseudoType)),
+	"tostring":  stdlib.MakeToFunc(cty.String),
+	"trimspace": stdlib.TrimSpaceFunc,
+	"upper":     stdlib.UpperFunc,
+	"values":    stdlib.ValuesFunc,
+	"zipmap":    stdlib.ZipmapFunc,
+}
+
+var moduleValuesSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: VariableBlockType, LabelNames: []string{"name"}},
+		{Type: "locals"},
+	},
+}
+
+var variableSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{Name: "default"}},
+}
+
+// getEvalContext returns the evaluation context of the module in the directory, with the default values of its
+// variables and the values of its locals. Values which depend on anything else, e.g. variables without defaults or
+// data sources, are unknown.
+func (p *TerraformParser) getEvalContext(dir string) *hcl.EvalContext {
+	p.evalContextsLock.Lock()
+	defer p.evalContextsLock.Unlock()
+	evalContext, ok := p.evalContextsByDir[dir]
+	if !ok {
+		evalContext = readModuleEvalContext(dir)
+		p.evalContextsByDir[dir] = evalContext
+	}
+	return evalContext
+}
+
+func readModuleEvalContext(dir string) *hcl.EvalContext {
+	variables := make(map[string]cty.Value)
+	localExprs := make(map[string]hcl.Expression)
+	files, _ := os.ReadDir(dir)
+	parser := hclparse.NewParser()
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		filePath := filepath.Join(dir, file.Name())
+		var hclFile *hcl.File
+		var diagnostics hcl.Diagnostics
+		switch {
+		case strings.HasSuffix(file.Name(), common.TfJSONFileType.Extension):
+			hclFile, diagnostics = parser.ParseJSONFile(filePath)
+		case strings.HasSuffix(file.Name(), common.TfFileType.Extension):
+			hclFile, diagnostics = parser.ParseHCLFile(filePath)
+		default:
+			continue
+		}
+		if diagnostics.HasErrors() || hclFile == nil {
+			continue
+		}
+		content, _, _ := hclFile.Body.PartialContent(moduleValuesSchema)
+		for _, block := range content.Blocks {
+			if block.Type == VariableBlockType {
+				variables[block.Labels[0]] = getVariableDefault(block)
+				continue
+			}
+			attributes, _ := block.Body.JustAttributes()
+			for name, attribute := range attributes {
+				localExprs[name] = attribute.Expr
+			}
+		}
+	}
+
+	evalContext := &hcl.EvalContext{
+		Variables: map[string]cty.Value{VarBlockType: cty.ObjectVal(variables)},
+		Functions: terraformFunctions,
+	}
+	// locals may refer to other locals, so they are evaluated until none of their values changes
+	localNames := make([]string, 0, len(localExprs))
+	locals := make(map[string]cty.Value, len(localExprs))
+	for name := range localExprs {
+		localNames = append(localNames, name)
+		locals[name] = cty.DynamicVal
+	}
+	sort.Strings(localNames)
+	for i := 0; i <= len(localNames); i++ {
+		evalContext.Variables[LocalBlockType] = cty.ObjectVal(locals)
+		changed := false
+		for _, name := range localNames {
+			value := evaluateExpression(localExprs[name], evalContext)
+			if !value.RawEquals(locals[name]) {
+				locals[name] = value
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	evalContext.Variables[LocalBlockType] = cty.ObjectVal(locals)
+	return evalContext
+}
+
+func getVariableDefault(block *hcl.Block) cty.Value {
+	content, _, diagnostics := block.Body.PartialContent(variableSchema)
+	if diagnostics.HasErrors() {
+		return cty.DynamicVal
+	}
+	defaultAttribute, ok := content.Attributes["default"]
+	if !ok {
+		return cty.DynamicVal
+	}
+	value, diagnostics := defaultAttribute.Expr.Value(nil)
+	if diagnostics.HasErrors() {
+		return cty.DynamicVal
+	}
+	return value
+}
+
+// evaluateExpression evaluates the expression in the context, with unknown values for the objects the context does not
+// define, e.g. data sources and resources. It returns an unknown value if the expression cannot be evaluated.
+func evaluateExpression(expr hcl.Expression, evalContext *hcl.EvalContext) cty.Value {
+	unknownRoots := make(map[string]cty.Value)
+	for _, traversal := range expr.Variables() {
+		if _, ok := evalContext.Variables[traversal.RootName()]; !ok {
+			unknownRoots[traversal.RootName()] = cty.DynamicVal
+		}
+	}
+	if len(unknownRoots) > 0 {
+		childContext := evalContext.NewChild()
+		childContext.Variables = unknownRoots
+		evalContext = childContext
+	}
+	value, diagnostics := expr.Value(evalContext)
+	if diagnostics.HasErrors() {
+		return cty.DynamicVal
+	}
+	return value
+}
+
+// getEvaluatedTags returns the tags the expression of a tags attribute evaluates to, apart from its literal maps which
+// parseTagAttribute reads as they are written. The known arguments of merge calls are evaluated even if the other
+// arguments are unknown, e.g. merge(data.x.tags, local.tags).
+func getEvaluatedTags(expr hclsyntax.Expression, evalContext *hcl.EvalContext) map[string]string {
+	evaluatedTags := make(map[string]string)
+	switch e := expr.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		return evaluatedTags
+	case *hclsyntax.FunctionCallExpr:
+		if e.Name != mergeFunctionName {
+			break
+		}
+		for _, arg := range e.Args {
+			for key, value := range getEvaluatedTags(arg, evalContext) {
+				evaluatedTags[key] = value
+			}
+		}
+		return evaluatedTags
+	}
+	value := evaluateExpression(expr, evalContext)
+	if !value.IsKnown() || value.IsNull() || !(value.Type().IsMapType() || value.Type().IsObjectType()) {
+		return evaluatedTags
+	}
+	for it := value.ElementIterator(); it.Next(); {
+		key, element := it.Element()
+		if !element.IsKnown() || element.IsNull() {
+			continue
+		}
+		if stringElement, err := convert.Convert(element, cty.String); err == nil {
+			evaluatedTags[key.AsString()] = stringElement.AsString()
+		}
+	}
+	return evaluatedTags
+}
+
+// getEffectiveTags returns the tags of a tags attribute, including the tags of the locals and the variable defaults
+// it refers to. The tags written in the attribute itself take precedence, as the writer updates them in place.
+func (p *TerraformParser) getEffectiveTags(tagsTokens hclwrite.Tokens, filePath string) map[string]string {
+	parsedTags := p.parseTagAttribute(tagsTokens)
+	expr, diagnostics := hclsyntax.ParseExpression(tagsTokens.Bytes(), filePath, hcl.InitialPos)
+	if diagnostics.HasErrors() || len(expr.Variables()) == 0 {
+		return parsedTags
+	}
+	for key, value := range getEvaluatedTags(expr, p.getEvalContext(filepath.Dir(filePath))) {
+		if _, ok := parsedTags[key]; !ok {
+			parsedTags[key] = value
+		}
+	}
+	return parsedTags
+}
@@ -0,0 +1,408 @@
+package structure
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common/json"
+	"github.com/bridgecrewio/yor/src/common/logger"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/bridgecrewio/yor/src/common/utils"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// tfJSONSchema is the schema of the blocks yor reads from files in the terraform JSON syntax, in which resources are
+// nested by their type and name, e.g. {"resource": {"aws_s3_bucket": {"logs": {...}}}}
+var tfJSONSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: ResourceBlockType, LabelNames: []string{"type", "name"}},
+		{Type: ModuleBlockType, LabelNames: []string{"name"}},
+		{Type: VariableBlockType, LabelNames: []string{"name"}},
+	},
+}
+
+type tfJSONBlock struct {
+	*hcl.Block
+	attributes hcl.Attributes
+	// scope is the range of the object of the block, from its opening to its closing brace
+	scope hcl.Range
+}
+
+func (b *tfJSONBlock) getID() string {
+	return strings.Join(append([]string{b.Type}, b.Labels...), ".")
+}
+
+func readTfJSONBlocks(src []byte, filePath string) ([]*tfJSONBlock, error) {
+	file, diagnostics := hcljson.Parse(src, filePath)
+	if diagnostics.HasErrors() {
+		return nil, fmt.Errorf("failed to parse terraform json file %s because of errors %s", filePath, diagnostics.Errs())
+	}
+	content, _, diagnostics := file.Body.PartialContent(tfJSONSchema)
+	if diagnostics.HasErrors() {
+		return nil, fmt.Errorf("failed to parse terraform json file %s because of errors %s", filePath, diagnostics.Errs())
+	}
+	jsonBlocks := make([]*tfJSONBlock, 0, len(content.Blocks))
+	for _, block := range content.Blocks {
+		attributes, _ := block.Body.JustAttributes()
+		jsonBlocks = append(jsonBlocks, &tfJSONBlock{
+			Block:      block,
+			attributes: attributes,
+			scope:      hcl.RangeBetween(block.DefRange, block.Body.MissingItemRange()),
+		})
+	}
+	return jsonBlocks, nil
+}
+
+// toHclSyntaxBlock describes the block in the native syntax model, which holds the labels and lines of terraform blocks
+func (b *tfJSONBlock) toHclSyntaxBlock() *hclsyntax.Block {
+	attributes := make(hclsyntax.Attributes, len(b.attributes))
+	for name, attribute := range b.attributes {
+		attributes[name] = &hclsyntax.Attribute{
+			Name:      name,
+			SrcRange:  attribute.Range,
+			NameRange: attribute.NameRange,
+		}
+	}
+	return &hclsyntax.Block{
+		Type:   b.Type,
+		Labels: b.Labels,
+		Body: &hclsyntax.Body{
+			Attributes: attributes,
+			SrcRange:   b.scope,
+			EndRange:   b.Body.MissingItemRange(),
+		},
+		TypeRange:       b.TypeRange,
+		LabelRanges:     b.LabelRanges,
+		OpenBraceRange:  b.DefRange,
+		CloseBraceRange: b.Body.MissingItemRange(),
+	}
+}
+
+func (p *TerraformParser) parseJSONFile(filePath string) ([]structure.IBlock, error) {
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s because %s", filePath, err)
+	}
+	jsonBlocks, err := readTfJSONBlocks(src, filePath)
+	if err != nil {
+		return nil, err
+	}
+	parsedBlocks := make([]structure.IBlock, 0)
+	for _, jsonBlock := range jsonBlocks {
+		blockID := strings.Join(jsonBlock.Labels, ".")
+		terraformBlock, err := p.parseJSONBlock(jsonBlock, src, filePath)
+		if err != nil {
+			if strings.HasPrefix(err.Error(), "resource belongs to skipped") || strings.HasPrefix(err.Error(), "could not find client") {
+				logger.Info(fmt.Sprintf("skipping block %s because the provider %s does not exist locally or does not support tags",
+					blockID, strings.Split(blockID, "_")[0]))
+			} else {
+				logger.Warning(fmt.Sprintf("failed to parse terraform block because %s", err.Error()))
+			}
+			continue
+		}
+		terraformBlock.Init(filePath, jsonBlock)
+		terraformBlock.AddHclSyntaxBlock(jsonBlock.toHclSyntaxBlock())
+		parsedBlocks = append(parsedBlocks, terraformBlock)
+	}
+
+	return parsedBlocks, nil
+}
+
+func (p *TerraformParser) parseJSONBlock(jsonBlock *tfJSONBlock, src []byte, filePath string) (*TerraformBlock, error) {
+	var existingTags []tags.ITag
+	isTaggable := false
+	var tagsAttributeName string
+	var resourceType string
+	var moduleSource string
+	var untaggableReason string
+	var literalTagKeys map[string]bool
+	var err error
+
+	switch jsonBlock.Type {
+	case ResourceBlockType:
+		resourceType = jsonBlock.Labels[0]
+		providerName := getProviderFromResourceType(resourceType)
+		if utils.InSlice(SkippedProviders, providerName) {
+			return nil, fmt.Errorf("resource belongs to skipped provider %s", providerName)
+		}
+		tagsAttributeName, err = p.getTagAttributeByResourceType(resourceType)
+		if err != nil {
+			return nil, err
+		}
+		if attribute, ok := jsonBlock.attributes[tagsAttributeName]; ok {
+			existingTags = p.getJSONAttributeTags(src, attribute)
+			literalTagKeys = getJSONAttributeKeys(src, attribute)
+		}
+		if _, ok := ResourceTypeToTagBlockName[resourceType]; ok {
+			// tag blocks are not supported in the JSON syntax
+			logger.Debug(fmt.Sprintf("skipping block %v, its tags are blocks", strings.Join(jsonBlock.Labels, ".")))
+			break
+		}
+		if p.providerTagAttributes[providerName].IsListFormat() {
+			// tags lists are not supported in the JSON syntax
+			logger.Debug(fmt.Sprintf("skipping block %v, its tags are a list", strings.Join(jsonBlock.Labels, ".")))
+			break
+		}
+		isTaggable, err = p.isResourceTypeTaggable(resourceType)
+		if err != nil {
+			return nil, err
+		}
+	case ModuleBlockType:
+		resourceType = "module"
+		moduleSource = getJSONAttributeString(src, jsonBlock.attributes["source"])
+		untaggableReason = p.getModuleSourceUntaggableReason(moduleSource)
+		if p.isTaggableModuleSource(moduleSource) {
+			possibleTagAttributeNames := p.getModuleTagsAttributeNames(moduleSource)
+			for _, tan := range possibleTagAttributeNames {
+				if attribute, ok := jsonBlock.attributes[tan]; ok {
+					existingTags = p.getJSONAttributeTags(src, attribute)
+					literalTagKeys = getJSONAttributeKeys(src, attribute)
+					isTaggable = true
+					tagsAttributeName = tan
+					break
+				}
+			}
+			if !isTaggable {
+				moduleDir := ExtractSubdirFromRemoteModuleSrc(moduleSource)
+				isTaggable, tagsAttributeName, untaggableReason = p.isModuleTaggable(filePath, strings.Join(jsonBlock.Labels, "."), moduleDir, possibleTagAttributeNames)
+				untaggableReason = p.getModuleUntaggableReason(moduleSource, untaggableReason)
+			}
+		}
+	}
+
+	terraformBlock := &TerraformBlock{
+		Block: structure.Block{
+			ExitingTags:       existingTags,
+			IsTaggable:        isTaggable,
+			TagsAttributeName: tagsAttributeName,
+			Type:              resourceType,
+		},
+		jsonLiteralTagKeys: literalTagKeys,
+	}
+	if jsonBlock.Type == ResourceBlockType {
+		terraformBlock.tagAttribute = p.providerTagAttributes[getProviderFromResourceType(resourceType)]
+		p.setProviderConfig(terraformBlock, filePath, getJSONAttributeString(src, jsonBlock.attributes["provider"]))
+	}
+	if jsonBlock.Type == ResourceBlockType || jsonBlock.Type == ModuleBlockType {
+		terraformBlock.MetaArgument = getMetaArgument(func(name string) bool { _, ok := jsonBlock.attributes[name]; return ok })
+	}
+	if jsonBlock.Type == ModuleBlockType {
+		terraformBlock.ModuleSource = moduleSource
+		terraformBlock.UntaggableReason = untaggableReason
+	}
+	return terraformBlock, nil
+}
+
+func getJSONAttributeValue(src []byte, attribute *hcl.Attribute) interface{} {
+	if attribute == nil {
+		return nil
+	}
+	exprRange := attribute.Expr.Range()
+	var value interface{}
+	if err := stdjson.Unmarshal(src[exprRange.Start.Byte:exprRange.End.Byte], &value); err != nil {
+		return nil
+	}
+	return value
+}
+
+func getJSONAttributeString(src []byte, attribute *hcl.Attribute) string {
+	value, _ := getJSONAttributeValue(src, attribute).(string)
+	return value
+}
+
+// getJSONAttributeTags returns the tags of an object, or the tags of the maps in a tags expression such as
+// "${merge(var.tags, {"env" = "dev"})}"
+func (p *TerraformParser) getJSONAttributeTags(src []byte, attribute *hcl.Attribute) []tags.ITag {
+	existingTags := make([]tags.ITag, 0)
+	parsedTags := make(map[string]string)
+	switch value := getJSONAttributeValue(src, attribute).(type) {
+	case map[string]interface{}:
+		for key, tagValue := range value {
+			strValue, ok := tagValue.(string)
+			if !ok {
+				valueBytes, _ := stdjson.Marshal(tagValue)
+				strValue = string(valueBytes)
+			}
+			parsedTags[key] = strValue
+		}
+	case string:
+		expression, ok := getTemplateExpression(value)
+		if !ok {
+			return existingTags
+		}
+		hclFile, diagnostics := hclwrite.ParseConfig([]byte(fmt.Sprintf("tags = %s\n", expression)), "", hcl.InitialPos)
+		if diagnostics.HasErrors() {
+			return existingTags
+		}
+		tagsTokens := hclFile.Body().GetAttribute("tags").Expr().BuildTokens(hclwrite.Tokens{})
+		parsedTags = p.parseTagAttribute(tagsTokens)
+	}
+	keys := make([]string, 0, len(parsedTags))
+	for key := range parsedTags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		existingTags = append(existingTags, tags.Init(key, parsedTags[key]))
+	}
+	return existingTags
+}
+
+// getJSONAttributeKeys returns the keys of an object, the keys of a tags expression are not returned as they cannot be
+// edited in place
+func getJSONAttributeKeys(src []byte, attribute *hcl.Attribute) map[string]bool {
+	keys := make(map[string]bool)
+	if value, ok := getJSONAttributeValue(src, attribute).(map[string]interface{}); ok {
+		for key := range value {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// getTemplateExpression returns the expression of a string which holds a single interpolation, e.g. var.tags for
+// "${var.tags}"
+func getTemplateExpression(value string) (string, bool) {
+	template, diagnostics := hclsyntax.ParseTemplate([]byte(value), "", hcl.InitialPos)
+	if diagnostics.HasErrors() {
+		return "", false
+	}
+	wrapExpr, ok := template.(*hclsyntax.TemplateWrapExpr)
+	if !ok {
+		return "", false
+	}
+	exprRange := wrapExpr.Wrapped.Range()
+	return value[exprRange.Start.Byte:exprRange.End.Byte], true
+}
+
+// getMergedTagsExpression returns the tags expression merged with a map of the given tags. The map is added as the
+// last argument of an existing merge call, so the new tags override the tags of the expression.
+func getMergedTagsExpression(expression string, newTags []tags.ITag) string {
+	entries := make([]string, 0, len(newTags))
+	for _, tag := range newTags {
+		key := hclwrite.TokensForValue(cty.StringVal(tag.GetKey())).Bytes()
+		value := hclwrite.TokensForValue(cty.StringVal(tag.GetValue())).Bytes()
+		entries = append(entries, fmt.Sprintf("%s = %s", key, value))
+	}
+	tagsMap := fmt.Sprintf("{%s}", strings.Join(entries, ", "))
+	parsedExpr, diagnostics := hclsyntax.ParseExpression([]byte(expression), "", hcl.InitialPos)
+	if !diagnostics.HasErrors() {
+		if call, ok := parsedExpr.(*hclsyntax.FunctionCallExpr); ok && call.Name == "merge" && len(call.Args) > 0 {
+			closeParen := call.CloseParenRange.Start.Byte
+			return fmt.Sprintf("%s, %s%s", strings.TrimRight(expression[:closeParen], " \t\r\n,"), tagsMap, expression[closeParen:])
+		}
+	}
+	return fmt.Sprintf("merge(%s, %s)", expression, tagsMap)
+}
+
+func (p *TerraformParser) writeJSONFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(readFilePath), "temp.*.tf.json")
+	defer func() {
+		_ = os.Remove(tempFile.Name())
+	}()
+	if err != nil {
+		return err
+	}
+	err = p.writeJSONToFile(readFilePath, blocks, tempFile.Name())
+	if err != nil {
+		return err
+	}
+	// #nosec G304
+	tempSrc, err := os.ReadFile(tempFile.Name())
+	if err != nil {
+		return err
+	}
+	tempBlocks, err := readTfJSONBlocks(tempSrc, tempFile.Name())
+	if err != nil || len(tempBlocks) != len(blocks) {
+		return fmt.Errorf("editing file %v resulted in malformed terraform, please open a github issue with the relevant details", readFilePath)
+	}
+	return p.writeJSONToFile(readFilePath, blocks, writeFilePath)
+}
+
+func (p *TerraformParser) writeJSONToFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	// #nosec G304
+	src, err := os.ReadFile(readFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s because %s", readFilePath, err)
+	}
+	jsonBlocks, err := readTfJSONBlocks(src, readFilePath)
+	if err != nil {
+		return err
+	}
+	jsonBlocksByID := make(map[string]*tfJSONBlock, len(jsonBlocks))
+	for _, jsonBlock := range jsonBlocks {
+		jsonBlocksByID[jsonBlock.getID()] = jsonBlock
+	}
+
+	srcStr := string(src)
+	type edit struct {
+		start int
+		end   int
+		text  string
+	}
+	var edits []edit
+	for _, block := range blocks {
+		terraformBlock := block.(*TerraformBlock)
+		if !terraformBlock.IsBlockTaggable() {
+			continue
+		}
+		diff := terraformBlock.CalculateTagsDiff()
+		if diff.IsEmpty() {
+			continue
+		}
+		jsonBlock, ok := jsonBlocksByID[strings.Join(append([]string{terraformBlock.HclSyntaxBlock.Type}, terraformBlock.HclSyntaxBlock.Labels...), ".")]
+		if !ok {
+			logger.Warning(fmt.Sprintf("failed to find block %v in %v", terraformBlock.GetResourceID(), readFilePath))
+			continue
+		}
+		attribute, ok := jsonBlock.attributes[terraformBlock.GetTagsAttributeName()]
+		if !ok {
+			objectScope := json.BracketPair{
+				Open:  json.Brackets{CharIndex: jsonBlock.scope.Start.Byte, Line: jsonBlock.scope.Start.Line},
+				Close: json.Brackets{CharIndex: jsonBlock.scope.End.Byte - 1, Line: jsonBlock.scope.End.Line},
+			}
+			insertAt, text := json.GetMapPropertyAddition(srcStr, objectScope, terraformBlock.GetTagsAttributeName(), diff.Added)
+			edits = append(edits, edit{start: insertAt, end: insertAt, text: text})
+			continue
+		}
+		exprRange := attribute.Expr.Range()
+		switch value := getJSONAttributeValue(src, attribute).(type) {
+		case map[string]interface{}:
+			// tags are migrated first, as the updates of renamed tags are by their new keys
+			text := json.AddTagsToMapStr(json.MigrateTagsStr(srcStr[exprRange.Start.Byte:exprRange.End.Byte], diff), diff)
+			edits = append(edits, edit{start: exprRange.Start.Byte, end: exprRange.End.Byte, text: text})
+		case string:
+			expression, ok := getTemplateExpression(value)
+			if !ok {
+				logger.Warning(fmt.Sprintf("failed to tag %v in %v, its %v are not an expression", terraformBlock.GetResourceID(), readFilePath, terraformBlock.GetTagsAttributeName()))
+				continue
+			}
+			newTags := diff.Added
+			for _, updated := range diff.Updated {
+				newTags = append(newTags, &tags.Tag{Key: updated.Key, Value: updated.NewValue})
+			}
+			text := json.MarshalJSONString(fmt.Sprintf("${%s}", getMergedTagsExpression(expression, newTags)))
+			edits = append(edits, edit{start: exprRange.Start.Byte, end: exprRange.End.Byte, text: text})
+		}
+	}
+
+	// apply the edits from the end of the file, so the offsets of the next edits are not affected
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start > edits[j].start
+	})
+	for _, e := range edits {
+		srcStr = srcStr[:e.start] + e.text + srcStr[e.end:]
+	}
+
+	return os.WriteFile(writeFilePath, []byte(srcStr), 0600)
+}
@@ -0,0 +1,145 @@
+package structure
+
+import (
+	"fmt"
+
+	"github.com/bridgecrewio/yor/src/common/logger"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const concatFunctionName = "concat"
+
+// getListTags returns the tags of a list of strings, e.g. ["env:prod"], or of the lists concatenated in a tags
+// expression such as concat(var.tags, ["env:prod"])
+func getListTags(tagsTokens hclwrite.Tokens, tagAttribute *ProviderTagAttribute) []tags.ITag {
+	existingTags := make([]tags.ITag, 0)
+	expr, diagnostics := hclsyntax.ParseExpression(tagsTokens.Bytes(), "", hcl.InitialPos)
+	if diagnostics.HasErrors() {
+		return existingTags
+	}
+	switch e := expr.(type) {
+	case *hclsyntax.TupleConsExpr:
+		existingTags = append(existingTags, getTupleTags(e, tagAttribute)...)
+	case *hclsyntax.FunctionCallExpr:
+		if e.Name != concatFunctionName {
+			break
+		}
+		for _, arg := range e.Args {
+			if tuple, ok := arg.(*hclsyntax.TupleConsExpr); ok {
+				existingTags = append(existingTags, getTupleTags(tuple, tagAttribute)...)
+			}
+		}
+	}
+	return existingTags
+}
+
+// getTupleTags returns the tags of the string literals of a list, the other elements are ignored
+func getTupleTags(tuple *hclsyntax.TupleConsExpr, tagAttribute *ProviderTagAttribute) []tags.ITag {
+	var tupleTags []tags.ITag
+	for _, element := range tuple.Exprs {
+		value, diagnostics := element.Value(nil)
+		if diagnostics.HasErrors() || !value.IsKnown() || value.IsNull() || value.Type() != cty.String {
+			continue
+		}
+		tupleTags = append(tupleTags, tagAttribute.ParseListTag(value.AsString()))
+	}
+	return tupleTags
+}
+
+func (p *TerraformParser) getListAttributeTags(hclBlock *hclwrite.Block, tagsAttributeName string, tagAttribute *ProviderTagAttribute) ([]tags.ITag, bool) {
+	tagsAttribute := hclBlock.Body().GetAttribute(tagsAttributeName)
+	if tagsAttribute == nil {
+		return make([]tags.ITag, 0), false
+	}
+	isTaggable, _ := p.isBlockTaggable(hclBlock)
+	return getListTags(tagsAttribute.Expr().BuildTokens(hclwrite.Tokens{}), tagAttribute), isTaggable
+}
+
+// modifyListTags writes the tags of a resource which holds its tags as a list of strings. A list of string literals is
+// rewritten with the merged tags, any other expression is concatenated with a list of the added tags.
+func (p *TerraformParser) modifyListTags(rawBlock *hclwrite.Block, parsedBlock structure.IBlock, tagAttribute *ProviderTagAttribute) {
+	tagsAttributeName := parsedBlock.GetTagsAttributeName()
+	tagsAttribute := rawBlock.Body().GetAttribute(tagsAttributeName)
+	if tagsAttribute == nil {
+		rawBlock.Body().SetAttributeValue(tagsAttributeName, buildListTagsValue(getOrderedMergedTags(parsedBlock), tagAttribute))
+		return
+	}
+	rawTagsTokens := tagsAttribute.Expr().BuildTokens(hclwrite.Tokens{})
+	expr, diagnostics := hclsyntax.ParseExpression(rawTagsTokens.Bytes(), "", hcl.InitialPos)
+	if diagnostics.HasErrors() {
+		logger.Warning(fmt.Sprintf("failed to parse the %v of %v", tagsAttributeName, parsedBlock.GetResourceID()))
+		return
+	}
+	if tuple, ok := expr.(*hclsyntax.TupleConsExpr); ok && isLiteralTuple(tuple) {
+		rawBlock.Body().SetAttributeValue(tagsAttributeName, buildListTagsValue(getOrderedMergedTags(parsedBlock), tagAttribute))
+		return
+	}
+	diff := parsedBlock.CalculateTagsDiff()
+	if len(diff.Updated) > 0 {
+		logger.Debug(fmt.Sprintf("Not updating the tags of %v, its %v are not a list of strings", parsedBlock.GetResourceID(), tagsAttributeName))
+	}
+	if len(diff.Added) == 0 {
+		return
+	}
+	addedTokens := hclwrite.TokensForValue(buildListTagsValue(diff.Added, tagAttribute))
+	if call, ok := expr.(*hclsyntax.FunctionCallExpr); ok && call.Name == concatFunctionName && len(call.Args) > 0 {
+		// add the list as the last argument of the existing concat call
+		closeParenIndex := len(rawTagsTokens) - 1
+		for closeParenIndex > 0 && rawTagsTokens[closeParenIndex].Type != hclsyntax.TokenCParen {
+			closeParenIndex--
+		}
+		newTokens := append(hclwrite.Tokens{}, rawTagsTokens[:closeParenIndex]...)
+		newTokens = append(newTokens, &hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")})
+		newTokens = append(newTokens, addedTokens...)
+		rawTagsTokens = append(newTokens, rawTagsTokens[closeParenIndex:]...)
+	} else {
+		newTokens := hclwrite.Tokens{
+			{Type: hclsyntax.TokenIdent, Bytes: []byte(concatFunctionName)},
+			{Type: hclsyntax.TokenOParen, Bytes: []byte("(")},
+		}
+		newTokens = append(newTokens, rawTagsTokens...)
+		newTokens = append(newTokens, &hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")})
+		newTokens = append(newTokens, addedTokens...)
+		rawTagsTokens = append(newTokens, &hclwrite.Token{Type: hclsyntax.TokenCParen, Bytes: []byte(")")})
+	}
+	rawBlock.Body().SetAttributeRaw(tagsAttributeName, rawTagsTokens)
+}
+
+// getOrderedMergedTags returns the merged tags of the block in the order of its existing tags, followed by the added tags
+func getOrderedMergedTags(block structure.IBlock) []tags.ITag {
+	mergedTagsByKey := make(map[string]tags.ITag)
+	for _, tag := range block.MergeTags() {
+		mergedTagsByKey[tag.GetKey()] = tag
+	}
+	orderedTags := make([]tags.ITag, 0, len(mergedTagsByKey))
+	for _, tag := range block.GetExistingTags() {
+		orderedTags = append(orderedTags, mergedTagsByKey[tag.GetKey()])
+	}
+	return append(orderedTags, block.CalculateTagsDiff().Added...)
+}
+
+func isLiteralTuple(tuple *hclsyntax.TupleConsExpr) bool {
+	for _, element := range tuple.Exprs {
+		value, diagnostics := element.Value(nil)
+		if diagnostics.HasErrors() || !value.IsKnown() || value.IsNull() || value.Type() != cty.String {
+			return false
+		}
+	}
+	return true
+}
+
+func buildListTagsValue(listTags []tags.ITag, tagAttribute *ProviderTagAttribute) cty.Value {
+	if len(listTags) == 0 {
+		return cty.ListValEmpty(cty.String)
+	}
+	elements := make([]cty.Value, 0, len(listTags))
+	for _, tag := range listTags {
+		elements = append(elements, cty.StringVal(tagAttribute.FormatListTag(tag)))
+	}
+	return cty.ListVal(elements)
+}
@@ -27,15 +27,17 @@ type TerraformModule struct {
 	tfModule            *tfconfig.Module
 	rootDir             string
 	ProvidersInstallDir string
+	// providerTagAttributes are the providers whose resources are tagged and whose registry modules are recognized
+	providerTagAttributes map[string]*ProviderTagAttribute
 }
 
-func NewTerraformModule(rootDir string) *TerraformModule {
+func NewTerraformModule(rootDir string, providerTagAttributes map[string]*ProviderTagAttribute) *TerraformModule {
 	tfModule, diagnostics := tfconfig.LoadModule(rootDir)
 	if diagnostics != nil && diagnostics.HasErrors() {
 		logger.Warning(diagnostics.Error())
 		return nil
 	}
-	terraformModule := &TerraformModule{tfModule: tfModule, rootDir: rootDir}
+	terraformModule := &TerraformModule{tfModule: tfModule, rootDir: rootDir, providerTagAttributes: providerTagAttributes}
 	if strings.ToUpper(os.Getenv("YOR_SKIP_PROVIDER_DOWNLOAD")) != "TRUE" {
 		// download terraform plugin into local folder if it doesn't exist
 		homeDir, _ := os.UserHomeDir()
@@ -47,7 +49,7 @@ func NewTerraformModule(rootDir string) *TerraformModule {
 }
 
 func (t *TerraformModule) InitProvider() {
-	moduleDependencies := getProviderDependencies(t.tfModule)
+	moduleDependencies := getProviderDependencies(t.tfModule, t.providerTagAttributes)
 	providers := moduleDependencies.AllPluginRequirements()
 	providerInstaller := &discovery.ProviderInstaller{
 		Dir:                   t.ProvidersInstallDir,
@@ -56,7 +58,7 @@ func (t *TerraformModule) InitProvider() {
 		Ui:                    &cli.MockUi{},
 	}
 	for provider, constraints := range providers {
-		if utils.InSlice(SkippedProviders, provider) {
+		if utils.InSlice(SkippedProviders, provider) || isProviderInSchemas(provider) {
 			continue
 		}
 		if providerExists(t.ProvidersInstallDir, provider) {
@@ -96,9 +98,9 @@ func (t *TerraformModule) GetModulesDirectories() []string {
 	modulesDirectories := []string{t.rootDir}
 
 	for _, moduleCall := range t.tfModule.ModuleCalls {
-		if !isRemoteModule(moduleCall.Source) && !isTerraformRegistryModule(moduleCall.Source) {
+		if !isRemoteModule(moduleCall.Source) && !isTerraformRegistryModule(moduleCall.Source, t.providerTagAttributes) {
 			childModuleDir := path.Join(t.rootDir, moduleCall.Source)
-			childModule := NewTerraformModule(childModuleDir)
+			childModule := NewTerraformModule(childModuleDir, t.providerTagAttributes)
 			childModulesDirectories := childModule.GetModulesDirectories()
 			for _, childDirPath := range childModulesDirectories {
 				if _, err := os.Stat(childDirPath); !os.IsNotExist(err) && !utils.InSlice(modulesDirectories, childDirPath) {
@@ -112,7 +114,7 @@ func (t *TerraformModule) GetModulesDirectories() []string {
 	return modulesDirectories
 }
 
-func getProviderDependencies(tfModule *tfconfig.Module) *moduledeps.Module {
+func getProviderDependencies(tfModule *tfconfig.Module, providerTagAttributes map[string]*ProviderTagAttribute) *moduledeps.Module {
 	moduleDependencies := &moduledeps.Module{}
 	providers := make(moduledeps.Providers)
 
@@ -136,7 +138,12 @@ func getProviderDependencies(tfModule *tfconfig.Module) *moduledeps.Module {
 		}
 	}
 
-	for name := range ProviderToTagAttribute {
+	// the taggable providers of the module resources are required even if the module does not declare them
+	for _, resource := range tfModule.ManagedResources {
+		name := resource.Provider.Name
+		if _, ok := providerTagAttributes[name]; !ok {
+			continue
+		}
 		inst := moduledeps.ProviderInstance(name)
 		if _, ok := providers[inst]; !ok {
 			providers[inst] = moduledeps.ProviderDependency{
@@ -148,7 +155,7 @@ func getProviderDependencies(tfModule *tfconfig.Module) *moduledeps.Module {
 	moduleDependencies.Providers = providers
 
 	for _, moduleCall := range tfModule.ModuleCalls {
-		if isRemoteModule(moduleCall.Source) || isTerraformRegistryModule(moduleCall.Source) {
+		if isRemoteModule(moduleCall.Source) || isTerraformRegistryModule(moduleCall.Source, providerTagAttributes) {
 			logger.Info("Skipping remote git module", moduleCall.Source)
 			continue
 		}
@@ -158,7 +165,7 @@ func getProviderDependencies(tfModule *tfconfig.Module) *moduledeps.Module {
 			hclErrors := diagnostics.Error()
 			logger.Warning(fmt.Sprintf("failed to parse hcl module in directory %s because of errors %s", path.Join(childModulePath, moduleCall.Source), hclErrors))
 		} else {
-			child := getProviderDependencies(tfChildModule)
+			child := getProviderDependencies(tfChildModule, providerTagAttributes)
 			moduleDependencies.Children = append(moduleDependencies.Children, child)
 		}
 	}
@@ -173,13 +180,13 @@ func isRemoteModule(s string) bool {
 		strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "git@")
 }
 
-func isTerraformRegistryModule(source string) bool {
+func isTerraformRegistryModule(source string, providerTagAttributes map[string]*ProviderTagAttribute) bool {
 	matches := utils.FindSubMatchByGroup(RegistryModuleRegex, source)
 	if matches == nil {
 		return false
 	}
 	if provider, ok := matches["PROVIDER"]; ok {
-		if _, okTag := ProviderToTagAttribute[provider]; okTag {
+		if _, okTag := providerTagAttributes[provider]; okTag {
 			return true
 		}
 	}
@@ -0,0 +1,205 @@
+package structure
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// The reasons module calls are not tagged, which are listed in the report
+const (
+	ModuleNoTagsInputReason         = "no tags input"
+	ModuleNotDownloadedReason       = "remote module not downloaded"
+	ModuleUnsupportedProviderReason = "unsupported provider"
+)
+
+var moduleInspectionSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: VariableBlockType, LabelNames: []string{"name"}},
+		{Type: "locals"},
+		{Type: ResourceBlockType, LabelNames: []string{"type", "name"}},
+		{Type: ProviderBlockType, LabelNames: []string{"name"}},
+	},
+}
+
+// isLocalModuleSource checks if the module source is a path, which the registry module pattern may match as well
+func isLocalModuleSource(moduleSource string) bool {
+	return strings.HasPrefix(moduleSource, "./") || strings.HasPrefix(moduleSource, "../") || filepath.IsAbs(moduleSource)
+}
+
+// getModuleSourceUntaggableReason returns the reason a module call is not tagged because of its source, which is only
+// the case for registry modules of providers which do not support tags
+func (p *TerraformParser) getModuleSourceUntaggableReason(moduleSource string) string {
+	if p.isTaggableModuleSource(moduleSource) || isLocalModuleSource(moduleSource) {
+		return ""
+	}
+	if RegistryModuleRegex.MatchString(moduleSource) {
+		return ModuleUnsupportedProviderReason
+	}
+	return ""
+}
+
+// getModuleUntaggableReason returns the reason a module call with a remote source whose module has no tags input is
+// not tagged
+func (p *TerraformParser) getModuleUntaggableReason(moduleSource string, reason string) string {
+	if reason != ModuleNoTagsInputReason {
+		return reason
+	}
+	if provider := extractProviderFromModuleSrc(moduleSource, p.providerTagAttributes); provider != "" {
+		if _, ok := p.providerTagAttributes[provider]; !ok {
+			return ModuleUnsupportedProviderReason
+		}
+	}
+	return reason
+}
+
+// inspectModuleTagsInput returns the input variable of the downloaded module in the directory which its resources and
+// provider configurations use as their tags, directly or through locals. The candidates are preferred in their order,
+// followed by any other variable which carries tags. If none does, it returns the reason the module is not taggable.
+func (p *TerraformParser) inspectModuleTagsInput(moduleDir string, candidates []string) (string, string) {
+	variables := make(map[string]bool)
+	localExprs := make(map[string]hcl.Expression)
+	var tagsExprs []hcl.Expression
+	supportedResources, unsupportedResources := 0, 0
+
+	files, _ := os.ReadDir(moduleDir)
+	parser := hclparse.NewParser()
+	for _, file := range files {
+		filePath := filepath.Join(moduleDir, file.Name())
+		var hclFile *hcl.File
+		var diagnostics hcl.Diagnostics
+		switch {
+		case file.IsDir():
+			continue
+		case strings.HasSuffix(file.Name(), common.TfJSONFileType.Extension):
+			hclFile, diagnostics = parser.ParseJSONFile(filePath)
+		case strings.HasSuffix(file.Name(), common.TfFileType.Extension):
+			hclFile, diagnostics = parser.ParseHCLFile(filePath)
+		default:
+			continue
+		}
+		if diagnostics.HasErrors() || hclFile == nil {
+			continue
+		}
+		content, _, _ := hclFile.Body.PartialContent(moduleInspectionSchema)
+		for _, block := range content.Blocks {
+			switch block.Type {
+			case VariableBlockType:
+				variables[block.Labels[0]] = true
+			case "locals":
+				attributes, _ := block.Body.JustAttributes()
+				for name, attribute := range attributes {
+					localExprs[name] = attribute.Expr
+				}
+			case ResourceBlockType:
+				tagsAttributeName, err := p.getTagAttributeByResourceType(block.Labels[0])
+				if err != nil {
+					unsupportedResources++
+					continue
+				}
+				supportedResources++
+				if tagsExpr := getBodyAttributeExpr(block.Body, tagsAttributeName); tagsExpr != nil {
+					tagsExprs = append(tagsExprs, tagsExpr)
+				}
+			case ProviderBlockType:
+				defaultTagsBlockType, ok := ProviderToDefaultTagsBlock[block.Labels[0]]
+				if !ok {
+					continue
+				}
+				providerContent, _, _ := block.Body.PartialContent(&hcl.BodySchema{Blocks: []hcl.BlockHeaderSchema{{Type: defaultTagsBlockType}}})
+				for _, defaultTagsBlock := range providerContent.Blocks {
+					if tagsExpr := getBodyAttributeExpr(defaultTagsBlock.Body, "tags"); tagsExpr != nil {
+						tagsExprs = append(tagsExprs, tagsExpr)
+					}
+				}
+			}
+		}
+	}
+
+	tagsVariables := make(map[string]bool)
+	for _, tagsExpr := range tagsExprs {
+		collectReferencedVariables(tagsExpr, localExprs, tagsVariables, make(map[string]bool))
+	}
+	for _, candidate := range candidates {
+		if variables[candidate] && tagsVariables[candidate] {
+			return candidate, ""
+		}
+	}
+	var otherVariables []string
+	for name := range tagsVariables {
+		if variables[name] {
+			otherVariables = append(otherVariables, name)
+		}
+	}
+	if len(otherVariables) > 0 {
+		sort.Strings(otherVariables)
+		return otherVariables[0], ""
+	}
+	if supportedResources == 0 && unsupportedResources > 0 {
+		return "", ModuleUnsupportedProviderReason
+	}
+	return "", ModuleNoTagsInputReason
+}
+
+func getBodyAttributeExpr(body hcl.Body, attributeName string) hcl.Expression {
+	content, _, _ := body.PartialContent(&hcl.BodySchema{Attributes: []hcl.AttributeSchema{{Name: attributeName}}})
+	if content == nil {
+		return nil
+	}
+	if attribute, ok := content.Attributes[attributeName]; ok {
+		return attribute.Expr
+	}
+	return nil
+}
+
+// collectReferencedVariables collects the variables which the expression uses as a whole map of tags, directly or
+// through locals, e.g. var.tags in merge(var.tags, { Name = var.name }) but not var.name
+func collectReferencedVariables(expr hcl.Expression, localExprs map[string]hcl.Expression, variables map[string]bool, visitedLocals map[string]bool) {
+	switch e := expr.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		return
+	case *hclsyntax.FunctionCallExpr:
+		for _, arg := range e.Args {
+			collectReferencedVariables(arg, localExprs, variables, visitedLocals)
+		}
+		return
+	case *hclsyntax.ConditionalExpr:
+		collectReferencedVariables(e.TrueResult, localExprs, variables, visitedLocals)
+		collectReferencedVariables(e.FalseResult, localExprs, variables, visitedLocals)
+		return
+	case *hclsyntax.ParenthesesExpr:
+		collectReferencedVariables(e.Expression, localExprs, variables, visitedLocals)
+		return
+	case *hclsyntax.ScopeTraversalExpr:
+		// a reference to a whole variable or local
+	default:
+		// the expressions of the JSON syntax are only known by their references
+		if _, isNativeSyntax := expr.(hclsyntax.Expression); isNativeSyntax {
+			return
+		}
+	}
+	for _, traversal := range expr.Variables() {
+		if len(traversal) < 2 {
+			continue
+		}
+		attr, ok := traversal[1].(hcl.TraverseAttr)
+		if !ok {
+			continue
+		}
+		switch traversal.RootName() {
+		case VarBlockType:
+			variables[attr.Name] = true
+		case LocalBlockType:
+			if localExpr, ok := localExprs[attr.Name]; ok && !visitedLocals[attr.Name] {
+				visitedLocals[attr.Name] = true
+				collectReferencedVariables(localExpr, localExprs, variables, visitedLocals)
+			}
+		}
+	}
+}
@@ -30,7 +30,7 @@ func TestTerraformModule(t *testing.T) {
 		localPath := "test/local/path"
 		isRemote := isRemoteModule(localPath)
 		assert.False(t, isRemote)
-		isRegistry := isTerraformRegistryModule(localPath)
+		isRegistry := isTerraformRegistryModule(localPath, ProviderToTagAttribute)
 		assert.False(t, isRegistry)
 	})
 
@@ -41,27 +41,27 @@ func TestTerraformModule(t *testing.T) {
 	})
 
 	t.Run("Test TF Registry Module logic", func(t *testing.T) {
-		isRegistry := isTerraformRegistryModule("terraform-aws-modules/security-group/aws")
+		isRegistry := isTerraformRegistryModule("terraform-aws-modules/security-group/aws", ProviderToTagAttribute)
 		assert.True(t, isRegistry)
 	})
 
 	t.Run("Test TF Private Registry Module logic", func(t *testing.T) {
-		isRegistry := isTerraformRegistryModule("some.private.registry/namespace/name/aws")
+		isRegistry := isTerraformRegistryModule("some.private.registry/namespace/name/aws", ProviderToTagAttribute)
 		assert.True(t, isRegistry)
 	})
 
 	t.Run("Test TF Private scalr Registry Module logic", func(t *testing.T) {
-		isRegistry := isTerraformRegistryModule("jameswoolfenden.scalr.io/acc-u1ksa0vgdflusgo/cloudfront/aws")
+		isRegistry := isTerraformRegistryModule("jameswoolfenden.scalr.io/acc-u1ksa0vgdflusgo/cloudfront/aws", ProviderToTagAttribute)
 		assert.True(t, isRegistry)
 	})
 
 	t.Run("Test TF Registry Module OCI logic", func(t *testing.T) {
-		isRegistry := isTerraformRegistryModule("oracle-terraform-modules/bastion/oci")
+		isRegistry := isTerraformRegistryModule("oracle-terraform-modules/bastion/oci", ProviderToTagAttribute)
 		assert.True(t, isRegistry)
 	})
 
 	t.Run("Test TF registry with inner path", func(t *testing.T) {
-		isRegistry := isTerraformRegistryModule("claranet/run-common/azurerm//modules/logs")
+		isRegistry := isTerraformRegistryModule("claranet/run-common/azurerm//modules/logs", ProviderToTagAttribute)
 		assert.True(t, isRegistry)
 	})
 
@@ -70,7 +70,7 @@ func TestTerraformModule(t *testing.T) {
 		providersDir, _ := filepath.Abs(currentDir + "../../../../tests/terraform/providers")
 		output := utils.CaptureOutput(func() {
 			logger.Logger.SetLogLevel("ERROR")
-			_ = NewTerraformModule(providersDir)
+			_ = NewTerraformModule(providersDir, ProviderToTagAttribute)
 			logger.Logger.SetLogLevel("WARNING")
 		})
 
@@ -0,0 +1,167 @@
+package structure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common/logger"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+const dynamicBlockType = "dynamic"
+const dynamicContentBlockType = "content"
+
+// ResourceTypeToNestedTagPaths maps the resource types which create other resources, such as volumes and node pools,
+// to the tags attributes of the nested blocks of these resources, e.g. root_block_device.tags. The nested resources
+// get the tags of their parent, including its yor_trace.
+var ResourceTypeToNestedTagPaths = map[string][]string{
+	"aws_instance":               {"root_block_device.tags", "ebs_block_device.tags"},
+	"aws_spot_instance_request":  {"root_block_device.tags", "ebs_block_device.tags"},
+	"aws_launch_template":        {"tag_specifications.tags"},
+	"azurerm_kubernetes_cluster": {"default_node_pool.tags"},
+}
+
+// nestedTagsConflictingAttributes maps the resource types to the attributes which tag the nested resources instead of
+// their nested tags, the provider rejects resources which set both, e.g. volume_tags and root_block_device.tags
+var nestedTagsConflictingAttributes = map[string]string{
+	"aws_instance":              "volume_tags",
+	"aws_spot_instance_request": "volume_tags",
+}
+
+// parseNestedTagsBlocks returns the blocks of the tags attributes of the nested blocks of the resource, by the nested tag
+// paths of its type. Only existing nested blocks are tagged, including the content of dynamic blocks.
+func (p *TerraformParser) parseNestedTagsBlocks(hclBlock *hclwrite.Block, terraformBlock *TerraformBlock, filePath string) []*TerraformBlock {
+	resourceType := terraformBlock.GetResourceType()
+	nestedTagPaths, ok := ResourceTypeToNestedTagPaths[resourceType]
+	if !ok || hclBlock.Type() != ResourceBlockType {
+		return nil
+	}
+	if conflictingAttribute, ok := nestedTagsConflictingAttributes[resourceType]; ok && hclBlock.Body().GetAttribute(conflictingAttribute) != nil {
+		logger.Debug(fmt.Sprintf("Not tagging the nested blocks of %v, it sets %v", strings.Join(hclBlock.Labels(), "."), conflictingAttribute))
+		return nil
+	}
+	var nestedTagsBlocks []*TerraformBlock
+	for _, nestedTagPath := range nestedTagPaths {
+		pathParts := strings.Split(nestedTagPath, ".")
+		tagsAttributeName := pathParts[len(pathParts)-1]
+		blockPath := strings.Join(pathParts[:len(pathParts)-1], ".")
+		nestedBlocks := getNestedBlocks(hclBlock.Body(), pathParts[:len(pathParts)-1])
+		for i, nestedBlock := range nestedBlocks {
+			// repeated nested blocks are identified by their index, e.g. tag_specifications[1].tags
+			nestedLabel := nestedTagPath
+			if len(nestedBlocks) > 1 {
+				nestedLabel = fmt.Sprintf("%v[%d].%v", blockPath, i, tagsAttributeName)
+			}
+			nestedTagsBlocks = append(nestedTagsBlocks, &TerraformBlock{
+				Block: structure.Block{
+					FilePath:          filePath,
+					ExitingTags:       p.getNestedExistingTags(nestedBlock, tagsAttributeName),
+					IsTaggable:        true,
+					TagsAttributeName: tagsAttributeName,
+					Type:              resourceType,
+				},
+				HclSyntaxBlock: &hclsyntax.Block{Type: ResourceBlockType, Labels: append(append([]string{}, hclBlock.Labels()...), nestedLabel)},
+				MetaArgument:   terraformBlock.MetaArgument,
+				tagAttribute:   terraformBlock.tagAttribute,
+			})
+		}
+	}
+	return nestedTagsBlocks
+}
+
+// GetNestedBlocks returns the blocks of the tags of the nested resources of the block, such as its volumes, with the new
+// tags of the block. They are calculated again from the current new tags of the block on every call.
+func (b *TerraformBlock) GetNestedBlocks() []structure.IBlock {
+	nestedNewTags := getNestedNewTags(b)
+	nestedBlocks := make([]structure.IBlock, 0, len(b.nestedTagsBlocks))
+	for _, nestedTagsBlock := range b.nestedTagsBlocks {
+		nestedTagsBlock.NewTags = nil
+		nestedTagsBlock.TagViolations = nil
+		nestedTagsBlock.BlockedTagUpdates = nil
+		nestedTagsBlock.AddNewTags(nestedNewTags)
+		nestedBlocks = append(nestedBlocks, nestedTagsBlock)
+	}
+	return nestedBlocks
+}
+
+// modifyNestedTags merges the tags of the resource into the tags of its nested blocks, the raw nested blocks are in the
+// order of the parsed ones as they are read from the same file
+func (p *TerraformParser) modifyNestedTags(rawBlock *hclwrite.Block, parsedBlock structure.IBlock) {
+	nestedTagsBlocks := parsedBlock.(*TerraformBlock).GetNestedBlocks()
+	if len(nestedTagsBlocks) == 0 {
+		return
+	}
+	var rawNestedBlocks []*hclwrite.Block
+	for _, nestedTagPath := range ResourceTypeToNestedTagPaths[parsedBlock.GetResourceType()] {
+		pathParts := strings.Split(nestedTagPath, ".")
+		rawNestedBlocks = append(rawNestedBlocks, getNestedBlocks(rawBlock.Body(), pathParts[:len(pathParts)-1])...)
+	}
+	if len(rawNestedBlocks) != len(nestedTagsBlocks) {
+		logger.Warning(fmt.Sprintf("Not tagging the nested blocks of %v, they changed since the file was parsed", parsedBlock.GetResourceID()))
+		return
+	}
+	for i, nestedTagsBlock := range nestedTagsBlocks {
+		diff := nestedTagsBlock.CalculateTagsDiff()
+		if len(diff.Added) == 0 && len(diff.Updated) == 0 {
+			continue
+		}
+		p.modifyBlockTags(rawNestedBlocks[i], nestedTagsBlock)
+	}
+}
+
+// getNestedNewTags returns the new tags of the resource for its nested blocks, with the yor_trace of the resource even
+// if it is already traced, so the nested resources share its lineage
+func getNestedNewTags(parsedBlock structure.IBlock) []tags.ITag {
+	nestedNewTags := make([]tags.ITag, 0, len(parsedBlock.GetNewTags())+1)
+	for _, tag := range parsedBlock.GetNewTags() {
+		if tag.GetKey() != tags.YorTraceTagKey {
+			nestedNewTags = append(nestedNewTags, tag)
+		}
+	}
+	if traceID := parsedBlock.GetTraceID(); traceID != "" {
+		nestedNewTags = append(nestedNewTags, tags.Init(tags.YorTraceTagKey, traceID))
+	}
+	return nestedNewTags
+}
+
+// getNestedBlocks returns the blocks of the body in the path of block types, and the content blocks of the dynamic
+// blocks in the path
+func getNestedBlocks(body *hclwrite.Body, blockTypes []string) []*hclwrite.Block {
+	if len(blockTypes) == 0 {
+		return nil
+	}
+	var nestedBlocks []*hclwrite.Block
+	for _, block := range body.Blocks() {
+		var nestedBlock *hclwrite.Block
+		if block.Type() == blockTypes[0] {
+			nestedBlock = block
+		} else if block.Type() == dynamicBlockType && len(block.Labels()) == 1 && block.Labels()[0] == blockTypes[0] {
+			nestedBlock = block.Body().FirstMatchingBlock(dynamicContentBlockType, nil)
+		}
+		if nestedBlock == nil {
+			continue
+		}
+		if len(blockTypes) == 1 {
+			nestedBlocks = append(nestedBlocks, nestedBlock)
+		} else {
+			nestedBlocks = append(nestedBlocks, getNestedBlocks(nestedBlock.Body(), blockTypes[1:])...)
+		}
+	}
+	return nestedBlocks
+}
+
+func (p *TerraformParser) getNestedExistingTags(nestedBlock *hclwrite.Block, tagsAttributeName string) []tags.ITag {
+	existingTags := make([]tags.ITag, 0)
+	tagsAttribute := nestedBlock.Body().GetAttribute(tagsAttributeName)
+	if tagsAttribute == nil {
+		return existingTags
+	}
+	parsedTags := p.parseTagAttribute(tagsAttribute.Expr().BuildTokens(hclwrite.Tokens{}))
+	for key := range parsedTags {
+		existingTags = append(existingTags, tags.Init(key, parsedTags[key]))
+	}
+	return existingTags
+}
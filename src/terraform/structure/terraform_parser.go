@@ -26,7 +26,6 @@ import (
 
 var ignoredDirs = []string{".git", ".DS_Store", ".idea", ".terraform"}
 var unsupportedTerraformBlocks = []string{
-	"aws_autoscaling_group",                  // This resource specifically supports tags with a different structure, see: https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/autoscaling_group#tag-and-tags
 	"aws_lb_listener",                        // This resource does not support tags, although docs state otherwise.
 	"aws_lb_listener_rule",                   // This resource does not support tags, although docs state otherwise.
 	"aws_cloudwatch_log_destination",         // This resource does not support tags, although docs state otherwise.
@@ -49,6 +48,20 @@ type TerraformParser struct {
 	downloadedPaths        []string
 	tfClientLock           sync.Mutex
 	skippedByCommentList   []string
+	// propagateAtLaunch is the propagate_at_launch value of the tag blocks added to auto scaling groups
+	propagateAtLaunch bool
+	// useDefaultTags writes the static tags to the default tags of the provider configurations instead of each resource
+	useDefaultTags       bool
+	staticTags           []tags.ITag
+	providerConfigsByDir map[string]map[string]*providerConfig
+	providerConfigsLock  sync.Mutex
+	// evalContextsByDir are the evaluation contexts of the locals and variable defaults of each module directory
+	evalContextsByDir map[string]*hcl.EvalContext
+	evalContextsLock  sync.Mutex
+	// inspectModules decides the tags input of the downloaded modules by the tags of their resources
+	inspectModules bool
+	// providerTagAttributes are the built-in providers and the providers of the config file
+	providerTagAttributes map[string]*ProviderTagAttribute
 }
 
 func (p *TerraformParser) Name() string {
@@ -60,7 +73,30 @@ func (p *TerraformParser) Init(rootDir string, args map[string]string) {
 	p.taggableResourcesCache = make(map[string]bool)
 	p.tagModules = true
 	p.tagLocalModules = false
-	p.terraformModule = NewTerraformModule(rootDir)
+	p.inspectModules = false
+	p.propagateAtLaunch = true
+	p.useDefaultTags = false
+	p.providerConfigsByDir = make(map[string]map[string]*providerConfig)
+	p.evalContextsByDir = make(map[string]*hcl.EvalContext)
+	p.providerTagAttributes = make(map[string]*ProviderTagAttribute, len(ProviderToTagAttribute))
+	for providerName, tagAttribute := range ProviderToTagAttribute {
+		p.providerTagAttributes[providerName] = tagAttribute
+	}
+	if configFilePath := args["config-file"]; configFilePath != "" {
+		if err := LoadProviderTagAttributes(configFilePath, p.providerTagAttributes); err != nil {
+			logger.Warning(fmt.Sprintf("failed to load the terraform providers of %v: %v", configFilePath, err))
+		}
+	}
+	schemasPath := args["provider-schemas"]
+	if schemasPath == "" {
+		schemasPath = os.Getenv(ProviderSchemasEnvKey)
+	}
+	if schemasPath != "" {
+		if err := LoadProviderSchemas(schemasPath, p.providerTagAttributes); err != nil {
+			logger.Warning(fmt.Sprintf("failed to load the provider schemas of %v: %v", schemasPath, err))
+		}
+	}
+	p.terraformModule = NewTerraformModule(rootDir, p.providerTagAttributes)
 	if argTagModule, ok := args["tag-modules"]; ok {
 		p.tagModules, _ = strconv.ParseBool(argTagModule)
 	}
@@ -69,12 +105,27 @@ func (p *TerraformParser) Init(rootDir string, args map[string]string) {
 		p.tagLocalModules, _ = strconv.ParseBool(argTagLocalModule)
 	}
 
+	if argInspectModules, ok := args["inspect-modules"]; ok {
+		p.inspectModules, _ = strconv.ParseBool(argInspectModules)
+	}
+
+	if argPropagateAtLaunch, ok := args["asg-propagate-at-launch"]; ok {
+		p.propagateAtLaunch, _ = strconv.ParseBool(argPropagateAtLaunch)
+	}
+
+	if argUseDefaultTags, ok := args["use-default-tags"]; ok {
+		p.useDefaultTags, _ = strconv.ParseBool(argUseDefaultTags)
+	}
+
 	p.moduleImporter = &command.GetCommand{Meta: command.Meta{Color: false, Ui: customTfLogger{}}}
 	pwd, _ := os.Getwd()
 	p.moduleInstallDir = filepath.Join(pwd, ".terraform", "modules")
 }
 
 func (p *TerraformParser) Close() {
+	if p.useDefaultTags {
+		p.writeProvidersDefaultTags()
+	}
 	logger.MuteOutputBlock(func() {
 		p.providerToClientMap.Range(func(_, iClient interface{}) bool {
 			client := iClient.(tfschema.Client)
@@ -89,7 +140,7 @@ func (p *TerraformParser) GetSkippedDirs() []string {
 }
 
 func (p *TerraformParser) GetSupportedFileExtensions() []string {
-	return []string{common.TfFileType.Extension}
+	return []string{common.TfFileType.Extension, common.TfJSONFileType.Extension}
 }
 
 func (p *TerraformParser) GetSourceFiles(directory string) ([]string, error) {
@@ -109,7 +160,7 @@ func (p *TerraformParser) GetSourceFiles(directory string) ([]string, error) {
 			if err != nil {
 				return err
 			}
-			if !info.IsDir() && strings.HasSuffix(info.Name(), ".tf") {
+			if !info.IsDir() && (strings.HasSuffix(info.Name(), common.TfFileType.Extension) || strings.HasSuffix(info.Name(), common.TfJSONFileType.Extension)) {
 				files = append(files, path)
 			}
 			return nil
@@ -127,6 +178,9 @@ func (p *TerraformParser) ValidFile(_ string) bool {
 }
 
 func (p *TerraformParser) ParseFile(filePath string) ([]structure.IBlock, error) {
+	if strings.HasSuffix(filePath, common.TfJSONFileType.Extension) {
+		return p.parseJSONFile(filePath)
+	}
 	// #nosec G304
 	// read file bytes
 	src, err := os.ReadFile(filePath)
@@ -198,6 +252,10 @@ func (p *TerraformParser) GetSkipResourcesByComment() []string {
 }
 
 func (p *TerraformParser) WriteFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	p.markProviderConfigsUsed(blocks)
+	if strings.HasSuffix(readFilePath, common.TfJSONFileType.Extension) {
+		return p.writeJSONFile(readFilePath, blocks, writeFilePath)
+	}
 	// #nosec G304
 	// read file bytes
 	src, err := os.ReadFile(readFilePath)
@@ -224,6 +282,7 @@ func (p *TerraformParser) WriteFile(readFilePath string, blocks []structure.IBlo
 				parsedBlockLabels := parsedBlock.(*TerraformBlock).HclSyntaxBlock.Labels
 				if reflect.DeepEqual(parsedBlockLabels, rawBlockLabels) {
 					p.modifyBlockTags(rawBlock, parsedBlock)
+					p.modifyNestedTags(rawBlock, parsedBlock)
 				}
 			}
 		}
@@ -288,6 +347,16 @@ func (p *TerraformParser) modifyBlockTags(rawBlock *hclwrite.Block, parsedBlock
 		return
 	}
 
+	if tagBlockName, ok := ResourceTypeToTagBlockName[parsedBlock.GetResourceType()]; ok {
+		p.modifyTagBlocks(rawBlock, parsedBlock, tagBlockName)
+		return
+	}
+
+	if tagAttribute := parsedBlock.(*TerraformBlock).tagAttribute; tagAttribute.IsListFormat() {
+		p.modifyListTags(rawBlock, parsedBlock, tagAttribute)
+		return
+	}
+
 	if tagsAttribute == nil {
 		mergedTagsTokens := buildTagsTokens(mergedTags)
 		if mergedTagsTokens != nil {
@@ -295,6 +364,11 @@ func (p *TerraformParser) modifyBlockTags(rawBlock *hclwrite.Block, parsedBlock
 		}
 	} else {
 		rawTagsTokens := tagsAttribute.Expr().BuildTokens(hclwrite.Tokens{})
+		diff := parsedBlock.CalculateTagsDiff()
+		isMigrated := len(diff.Removed) > 0 || len(diff.Renamed) > 0
+		if isMigrated {
+			rawTagsTokens = migrateTagsTokens(rawTagsTokens, diff)
+		}
 		isMergeOpExists := false
 		isRenderedAttribute := false
 		existingParsedTags := p.parseTagAttribute(rawTagsTokens)
@@ -313,17 +387,31 @@ func (p *TerraformParser) modifyBlockTags(rawBlock *hclwrite.Block, parsedBlock
 		var replacedTags []tags.ITag
 		var newTags []tags.ITag
 		possibleTagKeys := p.extractTagKeysFromRawTokens(rawTagsTokens)
+		// tags of the locals and variables the attribute refers to are not added again with the same values
+		existingTagsByKey := make(map[string]string)
+		for _, tag := range parsedBlock.GetExistingTags() {
+			existingTagsByKey[tag.GetKey()] = tag.GetValue()
+		}
+		for _, rename := range diff.Renamed {
+			existingTagsByKey[rename.NewKey] = rename.Value
+		}
 		for _, tag := range mergedTags {
 			tagReplaced := false
 			strippedTagKey := strings.ReplaceAll(tag.GetKey(), `"`, "")
+			_, isExistingTag := existingTagsByKey[tag.GetKey()]
+			_, isLiteralTag := existingParsedTags[tag.GetKey()]
 			for _, t := range possibleTagKeys {
+				if isExistingTag && !isLiteralTag {
+					// the tag is defined by a referenced local or variable, it is overridden rather than replaced
+					break
+				}
 				if t == tag.GetKey() || t == strippedTagKey || strings.Contains(t, strippedTagKey) {
 					replacedTags = append(replacedTags, tag)
 					tagReplaced = true
 					break
 				}
 			}
-			if !tagReplaced {
+			if existingValue, ok := existingTagsByKey[tag.GetKey()]; !tagReplaced && (!ok || existingValue != tag.GetValue()) {
 				newTags = append(newTags, tag)
 			}
 		}
@@ -348,6 +436,10 @@ func (p *TerraformParser) modifyBlockTags(rawBlock *hclwrite.Block, parsedBlock
 		}
 
 		if len(newTags) == 0 {
+			if isMigrated {
+				rawBlock.Body().SetAttributeRaw(tagsAttributeName, rawTagsTokens)
+				return
+			}
 			logger.Debug(fmt.Sprintf("Nothing to update for block %v (%v)", parsedBlock.GetResourceID(), parsedBlock.GetFilePath()))
 			return
 		}
@@ -403,6 +495,64 @@ func (p *TerraformParser) modifyBlockTags(rawBlock *hclwrite.Block, parsedBlock
 	}
 }
 
+// ModifyTagsAttribute writes the tags of the parsed block to its tags attribute in the body of the raw block. It lets
+// parsers of other HCL configurations which hold tags like Terraform resources, such as Terragrunt inputs, edit their
+// tags the way Terraform resources are edited.
+func (p *TerraformParser) ModifyTagsAttribute(rawBlock *hclwrite.Block, parsedBlock *TerraformBlock) {
+	p.modifyBlockTags(rawBlock, parsedBlock)
+}
+
+// ParseTagsAttribute returns the tags written in the tokens of a tags attribute
+func (p *TerraformParser) ParseTagsAttribute(tokens hclwrite.Tokens) map[string]string {
+	return p.parseTagAttribute(tokens)
+}
+
+// modifyTagBlocks removes and renames the migrated tag blocks of the resource, updates the values of its tag blocks and
+// appends a tag block for each new tag. Dynamic tag blocks are left untouched.
+func (p *TerraformParser) modifyTagBlocks(rawBlock *hclwrite.Block, parsedBlock structure.IBlock, tagBlockName string) {
+	diff := parsedBlock.CalculateTagsDiff()
+	hclWriteLock.Lock()
+	defer hclWriteLock.Unlock()
+	for _, tagBlock := range rawBlock.Body().Blocks() {
+		if tagBlock.Type() != tagBlockName {
+			continue
+		}
+		tagKey := getBodyAttributeValue(tagBlock.Body(), "key")
+		if isTagKeyIn(tagKey, diff.Removed) {
+			rawBlock.Body().RemoveBlock(tagBlock)
+			continue
+		}
+		for _, rename := range diff.Renamed {
+			if rename.PrevKey == tagKey {
+				tagKey = rename.NewKey
+				tagBlock.Body().SetAttributeValue("key", cty.StringVal(tagKey))
+			}
+		}
+		for _, updatedTag := range diff.Updated {
+			if updatedTag.Key == tagKey {
+				tagBlock.Body().SetAttributeValue("value", cty.StringVal(updatedTag.NewValue))
+			}
+		}
+	}
+	for _, tag := range diff.Added {
+		rawBlock.Body().AppendNewline()
+		tagBlock := rawBlock.Body().AppendNewBlock(tagBlockName, nil)
+		tagBlock.Body().SetAttributeValue("key", cty.StringVal(tag.GetKey()))
+		tagBlock.Body().SetAttributeValue("value", cty.StringVal(tag.GetValue()))
+		tagBlock.Body().SetAttributeValue("propagate_at_launch", cty.BoolVal(p.propagateAtLaunch))
+	}
+}
+
+func getBodyAttributeValue(body *hclwrite.Body, attributeName string) string {
+	attribute := body.GetAttribute(attributeName)
+	if attribute == nil {
+		return ""
+	}
+	value := strings.TrimSpace(string(attribute.Expr().BuildTokens(hclwrite.Tokens{}).Bytes()))
+	_ = json.Unmarshal([]byte(value), &value)
+	return value
+}
+
 func (p *TerraformParser) extractTagKeysFromRawTokens(rawTagsTokens hclwrite.Tokens) []string {
 	var tokens []string
 	for _, t := range rawTagsTokens {
@@ -449,7 +599,7 @@ func buildTagsTokens(tags []tags.ITag) hclwrite.Tokens {
 	if len(tagsMap) > 0 {
 		hclWriteLock.Lock()
 		defer hclWriteLock.Unlock()
-		return hclwrite.TokensForValue(cty.MapVal(tagsMap))
+		return renderTemplateTokens(hclwrite.TokensForValue(cty.MapVal(tagsMap)), tags)
 	}
 	return nil
 }
@@ -485,6 +635,7 @@ func (p *TerraformParser) parseBlock(hclBlock *hclwrite.Block, filePath string)
 	isTaggable := false
 	var tagsAttributeName string
 	var resourceType string
+	var untaggableReason string
 	var err error
 
 	switch hclBlock.Type() {
@@ -499,7 +650,13 @@ func (p *TerraformParser) parseBlock(hclBlock *hclwrite.Block, filePath string)
 		if err != nil {
 			return nil, err
 		}
-		existingTags, isTaggable = p.getExistingTags(hclBlock, tagsAttributeName)
+		if _, ok := ResourceTypeToTagBlockName[resourceType]; ok {
+			existingTags = p.getTagBlocksTags(hclBlock, tagsAttributeName)
+		} else if tagAttribute := p.providerTagAttributes[providerName]; tagAttribute.IsListFormat() {
+			existingTags, isTaggable = p.getListAttributeTags(hclBlock, tagsAttributeName, tagAttribute)
+		} else {
+			existingTags, isTaggable = p.getExistingTags(hclBlock, tagsAttributeName, filePath)
+		}
 
 		if !isTaggable {
 			isTaggable, err = p.isBlockTaggable(hclBlock)
@@ -515,7 +672,7 @@ func (p *TerraformParser) parseBlock(hclBlock *hclwrite.Block, filePath string)
 				err = fmt.Errorf("failed to parse module.%v", strings.Join(hclBlock.Labels(), "."))
 			}
 		}()
-		isTaggable, existingTags, tagsAttributeName = p.extractTagsFromModule(hclBlock, filePath, isTaggable, existingTags, tagsAttributeName)
+		isTaggable, existingTags, tagsAttributeName, untaggableReason = p.extractTagsFromModule(hclBlock, filePath, isTaggable, existingTags, tagsAttributeName)
 	}
 
 	terraformBlock := TerraformBlock{
@@ -526,27 +683,37 @@ func (p *TerraformParser) parseBlock(hclBlock *hclwrite.Block, filePath string)
 			Type:              resourceType,
 		},
 	}
+	if hclBlock.Type() == ResourceBlockType {
+		terraformBlock.tagAttribute = p.providerTagAttributes[getProviderFromResourceType(resourceType)]
+		p.setProviderConfig(&terraformBlock, filePath, getBodyAttributeValue(hclBlock.Body(), "provider"))
+	}
+	if hclBlock.Type() == ResourceBlockType || hclBlock.Type() == ModuleBlockType {
+		terraformBlock.MetaArgument = getMetaArgument(func(name string) bool { return hclBlock.Body().GetAttribute(name) != nil })
+	}
+	if hclBlock.Type() == ModuleBlockType {
+		terraformBlock.ModuleSource = getModuleSource(hclBlock)
+		terraformBlock.UntaggableReason = untaggableReason
+	}
+	if isTaggable {
+		terraformBlock.nestedTagsBlocks = p.parseNestedTagsBlocks(hclBlock, &terraformBlock, filePath)
+	}
 
 	return &terraformBlock, err
 }
 
-func (p *TerraformParser) extractTagsFromModule(hclBlock *hclwrite.Block, filePath string, isTaggable bool, existingTags []tags.ITag, tagsAttributeName string) (bool, []tags.ITag, string) {
-	moduleSource := string(hclBlock.Body().GetAttribute("source").Expr().BuildTokens(hclwrite.Tokens{}).Bytes())
-	// source is always wrapped in " front and back
-	moduleSource = strings.Trim(moduleSource, "\" ")
+func (p *TerraformParser) extractTagsFromModule(hclBlock *hclwrite.Block, filePath string, isTaggable bool, existingTags []tags.ITag, tagsAttributeName string) (bool, []tags.ITag, string, string) {
+	moduleSource := getModuleSource(hclBlock)
+	untaggableReason := ""
 
-	if !isRemoteModule(moduleSource) && !isTerraformRegistryModule(moduleSource) && !p.tagLocalModules {
+	if !p.isTaggableModuleSource(moduleSource) {
 		// Don't use the tags label on local modules - the underlying resources will be tagged by themselves
 		isTaggable = false
+		untaggableReason = p.getModuleSourceUntaggableReason(moduleSource)
 	} else {
 		// This is a remote module - if it has tags attribute, tag it!
-		moduleProvider := ExtractProviderFromModuleSrc(moduleSource)
-		possibleTagAttributeNames := []string{"extra_tags", "tags", "common_tags", "labels"}
-		if val, ok := ProviderToTagAttribute[moduleProvider]; ok {
-			possibleTagAttributeNames = append(possibleTagAttributeNames, val)
-		}
+		possibleTagAttributeNames := p.getModuleTagsAttributeNames(moduleSource)
 		for _, tan := range possibleTagAttributeNames {
-			existingTags, isTaggable = p.getModuleTags(hclBlock, tan)
+			existingTags, isTaggable = p.getModuleTags(hclBlock, tan, filePath)
 
 			if isTaggable {
 				tagsAttributeName = tan
@@ -555,10 +722,31 @@ func (p *TerraformParser) extractTagsFromModule(hclBlock *hclwrite.Block, filePa
 		}
 		if !isTaggable {
 			moduleDir := ExtractSubdirFromRemoteModuleSrc(moduleSource)
-			isTaggable, tagsAttributeName = p.isModuleTaggable(filePath, strings.Join(hclBlock.Labels(), "."), moduleDir, possibleTagAttributeNames)
+			isTaggable, tagsAttributeName, untaggableReason = p.isModuleTaggable(filePath, strings.Join(hclBlock.Labels(), "."), moduleDir, possibleTagAttributeNames)
+			untaggableReason = p.getModuleUntaggableReason(moduleSource, untaggableReason)
 		}
 	}
-	return isTaggable, existingTags, tagsAttributeName
+	return isTaggable, existingTags, tagsAttributeName, untaggableReason
+}
+
+func getModuleSource(hclBlock *hclwrite.Block) string {
+	moduleSource := string(hclBlock.Body().GetAttribute("source").Expr().BuildTokens(hclwrite.Tokens{}).Bytes())
+	// source is always wrapped in " front and back
+	return strings.Trim(moduleSource, "\" ")
+}
+
+func (p *TerraformParser) isTaggableModuleSource(moduleSource string) bool {
+	return isRemoteModule(moduleSource) || isTerraformRegistryModule(moduleSource, p.providerTagAttributes) || p.tagLocalModules
+}
+
+// getModuleTagsAttributeNames returns the names of the module variables which may hold the tags of a module
+func (p *TerraformParser) getModuleTagsAttributeNames(moduleSource string) []string {
+	moduleProvider := extractProviderFromModuleSrc(moduleSource, p.providerTagAttributes)
+	possibleTagAttributeNames := []string{"extra_tags", "tags", "common_tags", "labels", "default_tags"}
+	if val, ok := p.providerTagAttributes[moduleProvider]; ok {
+		possibleTagAttributeNames = append(possibleTagAttributeNames, val.Name)
+	}
+	return possibleTagAttributeNames
 }
 
 func ExtractSubdirFromRemoteModuleSrc(raw string) string {
@@ -576,12 +764,18 @@ func ExtractSubdirFromRemoteModuleSrc(raw string) string {
 }
 
 func ExtractProviderFromModuleSrc(source string) string {
+	return extractProviderFromModuleSrc(source, ProviderToTagAttribute)
+}
+
+// extractProviderFromModuleSrc returns the provider of a module source, the registry modules of the given providers
+// are recognized by their provider
+func extractProviderFromModuleSrc(source string, providerTagAttributes map[string]*ProviderTagAttribute) string {
 	if strings.HasPrefix(source, "app.terraform.io") {
 		// Terraform modules in private registry follow this structure: <HOSTNAME>/<ORGANIZATION>/<MODULE NAME>/<PROVIDER>
 		// https://www.terraform.io/docs/cloud/registry/using.html
 		return strings.Split(source, "/")[3]
 	}
-	if isTerraformRegistryModule(source) {
+	if isTerraformRegistryModule(source, providerTagAttributes) {
 		matches := utils.FindSubMatchByGroup(RegistryModuleRegex, source)
 		val := matches["PROVIDER"]
 		return val
@@ -596,7 +790,10 @@ func ExtractProviderFromModuleSrc(source string) string {
 	return ""
 }
 
-func (p *TerraformParser) isModuleTaggable(fp string, moduleName string, moduleDir string, tagAtts []string) (bool, string) {
+// isModuleTaggable checks if the downloaded module has an input of its tags, and returns the name of the input or the
+// reason the module is not taggable. With inspect-modules, the input is the variable which the resources and provider
+// configurations of the module use as their tags, rather than the first variable with one of the expected names.
+func (p *TerraformParser) isModuleTaggable(fp string, moduleName string, moduleDir string, tagAtts []string) (bool, string, string) {
 	logger.Info(fmt.Sprintf("Searching module %v for %v", moduleName, tagAtts))
 	actualPath, _ := filepath.Rel(p.rootDir, filepath.Dir(fp))
 	absRootPath, _ := filepath.Abs(p.rootDir)
@@ -610,18 +807,22 @@ func (p *TerraformParser) isModuleTaggable(fp string, moduleName string, moduleD
 	}
 	expectedModuleDir := filepath.Join(p.moduleInstallDir, moduleName, moduleDir)
 	if _, err := os.Stat(expectedModuleDir); os.IsNotExist(err) {
-		return false, ""
+		return false, "", ModuleNotDownloadedReason
+	}
+	if p.inspectModules {
+		tagsInput, reason := p.inspectModuleTagsInput(expectedModuleDir, tagAtts)
+		return tagsInput != "", tagsInput, reason
 	}
 
 	files, _ := os.ReadDir(expectedModuleDir)
 	for _, f := range files {
-		if strings.HasSuffix(f.Name(), ".tf") {
+		if strings.HasSuffix(f.Name(), common.TfFileType.Extension) || strings.HasSuffix(f.Name(), common.TfJSONFileType.Extension) {
 			blocks, _ := p.ParseFile(filepath.Join(expectedModuleDir, f.Name()))
 			for _, b := range blocks {
 				if b.(*TerraformBlock).HclSyntaxBlock.Type == VariableBlockType {
 					for _, tagAtt := range tagAtts {
 						if b.GetResourceID() == tagAtt {
-							return true, tagAtt
+							return true, tagAtt, ""
 						}
 					}
 				}
@@ -629,12 +830,12 @@ func (p *TerraformParser) isModuleTaggable(fp string, moduleName string, moduleD
 		}
 	}
 
-	return false, ""
+	return false, "", ModuleNoTagsInputReason
 }
 
 func (p *TerraformParser) getTagsAttributeName(hclBlock *hclwrite.Block) (string, error) {
 	resourceType := hclBlock.Labels()[0]
-	tagsAttributeName, err := getTagAttributeByResourceType(resourceType)
+	tagsAttributeName, err := p.getTagAttributeByResourceType(resourceType)
 	if err != nil {
 		return "", err
 	}
@@ -647,16 +848,25 @@ func getProviderFromResourceType(resourceType string) string {
 	return provider
 }
 
-func getTagAttributeByResourceType(resourceType string) (string, error) {
-	prefix := ProviderToTagAttribute[getProviderFromResourceType(resourceType)]
-	if prefix == "" {
+func (p *TerraformParser) getTagAttributeByResourceType(resourceType string) (string, error) {
+	if reason, ok := UnsupportedProviders[getProviderFromResourceType(resourceType)]; ok {
+		return "", fmt.Errorf("resource type %s is not supported, %s", resourceType, reason)
+	}
+	if tagBlockName, ok := ResourceTypeToTagBlockName[resourceType]; ok {
+		return tagBlockName, nil
+	}
+	if schemaTagAttribute, ok := getSchemaTagAttribute(resourceType); ok && schemaTagAttribute != "" {
+		return schemaTagAttribute, nil
+	}
+	tagAttribute, ok := p.providerTagAttributes[getProviderFromResourceType(resourceType)]
+	if !ok {
 		return "", fmt.Errorf("failed to find tags attribute name for resource type %s", resourceType)
 	}
 
-	return prefix, nil
+	return tagAttribute.Name, nil
 }
 
-func (p *TerraformParser) getExistingTags(hclBlock *hclwrite.Block, tagsAttributeName string) ([]tags.ITag, bool) {
+func (p *TerraformParser) getExistingTags(hclBlock *hclwrite.Block, tagsAttributeName string, filePath string) ([]tags.ITag, bool) {
 	isTaggable := false
 	existingTags := make([]tags.ITag, 0)
 
@@ -665,7 +875,7 @@ func (p *TerraformParser) getExistingTags(hclBlock *hclwrite.Block, tagsAttribut
 		// if tags exists in resource
 		isTaggable, _ = p.isBlockTaggable(hclBlock)
 		tagsTokens := tagsAttribute.Expr().BuildTokens(hclwrite.Tokens{})
-		parsedTags := p.parseTagAttribute(tagsTokens)
+		parsedTags := p.getEffectiveTags(tagsTokens, filePath)
 		for key := range parsedTags {
 			iTag := tags.Init(key, parsedTags[key])
 			existingTags = append(existingTags, iTag)
@@ -675,11 +885,36 @@ func (p *TerraformParser) getExistingTags(hclBlock *hclwrite.Block, tagsAttribut
 	return existingTags, isTaggable
 }
 
+// getTagBlocksTags returns the tags of the tag blocks of the resource, the tags of dynamic tag blocks are unknown
+func (p *TerraformParser) getTagBlocksTags(hclBlock *hclwrite.Block, tagBlockName string) []tags.ITag {
+	existingTags := make([]tags.ITag, 0)
+	for _, tagBlock := range hclBlock.Body().Blocks() {
+		if tagBlock.Type() != tagBlockName {
+			continue
+		}
+		tagKey := getBodyAttributeValue(tagBlock.Body(), "key")
+		if tagKey == "" {
+			continue
+		}
+		existingTags = append(existingTags, tags.Init(tagKey, getBodyAttributeValue(tagBlock.Body(), "value")))
+	}
+	return existingTags
+}
+
 func (p *TerraformParser) isBlockTaggable(hclBlock *hclwrite.Block) (bool, error) {
-	resourceType := hclBlock.Labels()[0]
+	return p.isResourceTypeTaggable(hclBlock.Labels()[0])
+}
+
+func (p *TerraformParser) isResourceTypeTaggable(resourceType string) (bool, error) {
 	if utils.InSlice(unsupportedTerraformBlocks, resourceType) {
 		return false, nil
 	}
+	if _, ok := ResourceTypeToTagBlockName[resourceType]; ok {
+		return true, nil
+	}
+	if schemaTagAttribute, ok := getSchemaTagAttribute(resourceType); ok {
+		return schemaTagAttribute != "", nil
+	}
 	if utils.InSlice(TfTaggableResourceTypes, resourceType) {
 		return true, nil
 	}
@@ -689,7 +924,7 @@ func (p *TerraformParser) isBlockTaggable(hclBlock *hclwrite.Block) (bool, error
 	if ok {
 		return val, nil
 	}
-	tagAtt, err := getTagAttributeByResourceType(resourceType)
+	tagAtt, err := p.getTagAttributeByResourceType(resourceType)
 	if err != nil {
 		return false, err
 	}
@@ -739,6 +974,115 @@ func (p *TerraformParser) getHclMapsContents(tokens hclwrite.Tokens) []hclwrite.
 	return hclMaps
 }
 
+// migrateTagsTokens removes and renames the tags of the diff which are written in the maps of the tokens of a tags
+// attribute. Removed tags are removed with their separating comma and line, renamed tags keep their values.
+func migrateTagsTokens(tokens hclwrite.Tokens, diff *structure.TagDiff) hclwrite.Tokens {
+	renamedKeys := make(map[string]string)
+	for _, rename := range diff.Renamed {
+		renamedKeys[rename.PrevKey] = rename.NewKey
+	}
+	migratedTokens := make(hclwrite.Tokens, 0, len(tokens))
+	var openBrackets []hclsyntax.TokenType
+	isEntryStart := false
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if isEntryStart && isInTagsMap(openBrackets) && token.Type != hclsyntax.TokenNewline && token.Type != hclsyntax.TokenComment {
+			isEntryStart = false
+			if keyEnd, entryEnd := findTagEntryEnd(tokens, i); keyEnd > i {
+				key := strings.TrimSpace(string(tokens[i:keyEnd].Bytes()))
+				_ = json.Unmarshal([]byte(key), &key)
+				if isTagKeyIn(key, diff.Removed) {
+					// skip the entry and its separating comma, and its line break if it is the only entry of its line. The
+					// comma before the last entry of a line is removed instead.
+					isAfterComma := len(migratedTokens) > 0 && migratedTokens[len(migratedTokens)-1].Type == hclsyntax.TokenComma
+					i = entryEnd
+					if i < len(tokens) && tokens[i].Type == hclsyntax.TokenComma {
+						i++
+					} else if isAfterComma {
+						migratedTokens = migratedTokens[:len(migratedTokens)-1]
+					}
+					if !isAfterComma && i < len(tokens) && (tokens[i].Type == hclsyntax.TokenNewline || tokens[i].Type == hclsyntax.TokenComment) {
+						i++
+					}
+					i--
+					isEntryStart = true
+					continue
+				}
+				if newKey, ok := renamedKeys[key]; ok {
+					migratedTokens = append(migratedTokens, buildTagKeyTokens(newKey, token.Type != hclsyntax.TokenIdent, token.SpacesBefore)...)
+					i = keyEnd
+					token = tokens[i]
+				}
+			}
+		}
+		switch token.Type {
+		case hclsyntax.TokenOBrace, hclsyntax.TokenOParen, hclsyntax.TokenOBrack, hclsyntax.TokenTemplateInterp, hclsyntax.TokenTemplateControl:
+			openBrackets = append(openBrackets, token.Type)
+		case hclsyntax.TokenCBrace, hclsyntax.TokenCParen, hclsyntax.TokenCBrack, hclsyntax.TokenTemplateSeqEnd:
+			if len(openBrackets) > 0 {
+				openBrackets = openBrackets[:len(openBrackets)-1]
+			}
+		}
+		if isInTagsMap(openBrackets) && utils.InSlice([]hclsyntax.TokenType{hclsyntax.TokenOBrace, hclsyntax.TokenComma, hclsyntax.TokenNewline, hclsyntax.TokenComment}, token.Type) {
+			isEntryStart = true
+		}
+		migratedTokens = append(migratedTokens, token)
+	}
+	return migratedTokens
+}
+
+// isInTagsMap returns true if the innermost open bracket is the brace of a map, which is not nested in another map
+func isInTagsMap(openBrackets []hclsyntax.TokenType) bool {
+	if len(openBrackets) == 0 || openBrackets[len(openBrackets)-1] != hclsyntax.TokenOBrace {
+		return false
+	}
+	for _, bracket := range openBrackets[:len(openBrackets)-1] {
+		if bracket == hclsyntax.TokenOBrace {
+			return false
+		}
+	}
+	return true
+}
+
+// findTagEntryEnd returns the index of the equal sign of the map entry which starts at the start index of the tokens,
+// and the index of the token after the entry, which is its separator or the closing brace of the map
+func findTagEntryEnd(tokens hclwrite.Tokens, start int) (int, int) {
+	keyEnd := -1
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		switch tokens[i].Type {
+		case hclsyntax.TokenOBrace, hclsyntax.TokenOParen, hclsyntax.TokenOBrack, hclsyntax.TokenTemplateInterp, hclsyntax.TokenTemplateControl:
+			depth++
+		case hclsyntax.TokenCBrace, hclsyntax.TokenCParen, hclsyntax.TokenCBrack, hclsyntax.TokenTemplateSeqEnd:
+			if depth == 0 {
+				return keyEnd, i
+			}
+			depth--
+		case hclsyntax.TokenEqual, hclsyntax.TokenColon:
+			if depth == 0 && keyEnd == -1 {
+				keyEnd = i
+			}
+		case hclsyntax.TokenComma, hclsyntax.TokenNewline, hclsyntax.TokenComment:
+			if depth == 0 {
+				return keyEnd, i
+			}
+		}
+	}
+	return keyEnd, len(tokens)
+}
+
+// buildTagKeyTokens returns the tokens of a tag key, quoted if it was quoted or is not a valid identifier
+func buildTagKeyTokens(key string, isQuoted bool, spacesBefore int) hclwrite.Tokens {
+	keyTokens := hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte(key)}}
+	if isQuoted || !hclsyntax.ValidIdentifier(key) {
+		hclWriteLock.Lock()
+		keyTokens = hclwrite.TokensForValue(cty.StringVal(key))
+		hclWriteLock.Unlock()
+	}
+	keyTokens[0].SpacesBefore = spacesBefore
+	return keyTokens
+}
+
 func (p *TerraformParser) extractTagPairs(tokens hclwrite.Tokens) []hclwrite.Tokens {
 	// The function gets tokens and returns an array of tokens that represent key and value
 	// example: tokens: "a=1\n b=2, c=3", returns: ["a=1", "b=2", "c=3"]
@@ -820,7 +1164,11 @@ func (p *TerraformParser) parseTagAttribute(tokens hclwrite.Tokens) map[string]s
 		value := string(entry[eqIndex:].Bytes())
 		value = strings.TrimPrefix(strings.TrimSuffix(value, " "), " ")
 		_ = json.Unmarshal([]byte(key), &key)
-		_ = json.Unmarshal([]byte(value), &value)
+		if quotedValue, ok := parseQuotedValue(value); ok {
+			value = quotedValue
+		} else {
+			_ = json.Unmarshal([]byte(value), &value)
+		}
 		parsedTags[key] = value
 	}
 
@@ -828,7 +1176,7 @@ func (p *TerraformParser) parseTagAttribute(tokens hclwrite.Tokens) map[string]s
 }
 
 func (p *TerraformParser) getClient(providerName string) tfschema.Client {
-	if utils.InSlice(SkippedProviders, providerName) {
+	if utils.InSlice(SkippedProviders, providerName) || isProviderInSchemas(providerName) {
 		return nil
 	}
 
@@ -869,7 +1217,7 @@ func (p *TerraformParser) getClient(providerName string) tfschema.Client {
 	return newClient
 }
 
-func (p *TerraformParser) getModuleTags(hclBlock *hclwrite.Block, tagsAttributeName string) ([]tags.ITag, bool) {
+func (p *TerraformParser) getModuleTags(hclBlock *hclwrite.Block, tagsAttributeName string, filePath string) ([]tags.ITag, bool) {
 	isTaggable := false
 	existingTags := make([]tags.ITag, 0)
 
@@ -878,7 +1226,7 @@ func (p *TerraformParser) getModuleTags(hclBlock *hclwrite.Block, tagsAttributeN
 		// if tags exists in module
 		isTaggable = true
 		tagsTokens := tagsAttribute.Expr().BuildTokens(hclwrite.Tokens{})
-		parsedTags := p.parseTagAttribute(tagsTokens)
+		parsedTags := p.getEffectiveTags(tagsTokens, filePath)
 		for key := range parsedTags {
 			iTag := tags.Init(key, parsedTags[key])
 			existingTags = append(existingTags, iTag)
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/bridgecrewio/yor/src/common/gitservice"
 	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging"
 	"github.com/bridgecrewio/yor/src/common/tagging/code2cloud"
 	"github.com/bridgecrewio/yor/src/common/tagging/gittag"
 	"github.com/bridgecrewio/yor/src/common/tagging/tags"
@@ -131,13 +134,14 @@ func TestTerraformParser_ParseFile(t *testing.T) {
 		filePath := "../../../tests/terraform/resources/complex_tags.tf"
 		expectedTags := map[string]map[string]string{
 			"vpc_tags_one_line":                         {"Name": "tag-for-s3", "Environment": "prod"},
-			"bucket_var_tags":                           {},
+			"bucket_var_tags":                           {"Name": "tag-for-s3", "Environment": "prod"},
 			"alb_with_merged_tags":                      {"Name": "tag-for-alb", "Environment": "prod", "yor_trace": "4329587194", "git_org": "bana"},
 			"many_instance_tags":                        {"Name": "tag-for-instance", "Environment": "prod", "Owner": "bridgecrew", "yor_trace": "4329587194", "git_org": "bana"},
-			"instance_merged_var":                       {"yor_trace": "4329587194", "git_org": "bana"},
+			"instance_merged_var":                       {"Name": "tag-for-s3", "Environment": "prod", "yor_trace": "4329587194", "git_org": "bana"},
 			"instance_merged_override":                  {"Environment": "new_env"},
 			"aurora_cluster_bastion_auto_scaling_group": {"git_org": "bridgecrewio", "git_repo": "platform", "yor_trace": "48564943-4cfc-403c-88cd-cbb207e0d33e", "Name": "bc-aurora-bastion"},
 			"instance_null_tags":                        nil,
+			"autoscaling_group_tagged":                  {"Name": "Mine"},
 		}
 
 		parsedBlocks, err := p.ParseFile(filePath)
@@ -230,7 +234,7 @@ func TestTerraformParser_Module(t *testing.T) {
 
 		for _, block := range parsedBlocks {
 			if utils.InSlice([]string{"aws_autoscaling_group.autoscaling_group", "aws_autoscaling_group.autoscaling_group_tagged"}, block.GetResourceID()) {
-				assert.False(t, block.IsBlockTaggable())
+				assert.True(t, block.IsBlockTaggable())
 			}
 			if block.IsBlockTaggable() {
 				_ = tagGroup.CreateTagsForBlock(block)
@@ -293,7 +297,7 @@ func TestTerraformParser_Module(t *testing.T) {
 
 		for _, block := range parsedBlocks {
 			if utils.InSlice([]string{"aws_autoscaling_group.autoscaling_group", "aws_autoscaling_group.autoscaling_group_tagged"}, block.GetResourceID()) {
-				assert.False(t, block.IsBlockTaggable())
+				assert.True(t, block.IsBlockTaggable())
 			}
 			if block.IsBlockTaggable() {
 				_ = tagGroup.CreateTagsForBlock(block)
@@ -583,6 +587,61 @@ func TestTerraformParser_Module(t *testing.T) {
 		}
 	})
 
+	t.Run("Report the reasons module calls are not taggable", func(t *testing.T) {
+		t.Setenv("YOR_DISABLE_TF_MODULE_DOWNLOAD", "TRUE")
+		directory := "../../../tests/terraform/resources/module_inspection"
+		parseModules := func(inspectModules bool) map[string]*TerraformBlock {
+			p := &TerraformParser{}
+			p.Init(directory, map[string]string{"inspect-modules": strconv.FormatBool(inspectModules)})
+			defer p.Close()
+			p.moduleInstallDir = "../../../tests/terraform/module/inspected_modules"
+			blocks, err := p.ParseFile(filepath.Join(directory, "main.tf"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			modules := make(map[string]*TerraformBlock)
+			for _, block := range blocks {
+				modules[block.GetResourceID()] = block.(*TerraformBlock)
+			}
+			return modules
+		}
+		type expectedModule struct {
+			tagsAttributeName string
+			untaggableReason  string
+		}
+		assertModules := func(modules map[string]*TerraformBlock, expected map[string]expectedModule) {
+			assert.Equal(t, len(expected), len(modules))
+			for moduleID, expectedModule := range expected {
+				module := modules[moduleID]
+				assert.Equal(t, expectedModule.tagsAttributeName != "", module.IsBlockTaggable(), moduleID)
+				if expectedModule.tagsAttributeName != "" {
+					assert.Equal(t, expectedModule.tagsAttributeName, module.GetTagsAttributeName(), moduleID)
+				}
+				assert.Equal(t, expectedModule.untaggableReason, module.GetUntaggableReason(), moduleID)
+			}
+		}
+
+		assertModules(parseModules(false), map[string]expectedModule{
+			"tagged":  {tagsAttributeName: "tags"},
+			"labels":  {tagsAttributeName: "tags"},
+			"no_tags": {untaggableReason: ModuleNoTagsInputReason},
+			"k8s":     {tagsAttributeName: "tags"},
+			"missing": {untaggableReason: ModuleNotDownloadedReason},
+			"helm":    {untaggableReason: ModuleUnsupportedProviderReason},
+			"local":   {},
+		})
+		// the resources of the modules decide their tags input
+		assertModules(parseModules(true), map[string]expectedModule{
+			"tagged":  {tagsAttributeName: "tags"},
+			"labels":  {tagsAttributeName: "labels"},
+			"no_tags": {untaggableReason: ModuleNoTagsInputReason},
+			"k8s":     {untaggableReason: ModuleUnsupportedProviderReason},
+			"missing": {untaggableReason: ModuleNotDownloadedReason},
+			"helm":    {untaggableReason: ModuleUnsupportedProviderReason},
+			"local":   {},
+		})
+	})
+
 	t.Run("Test isModuleTaggable on remote modules", func(t *testing.T) {
 		directory := "../../../tests/terraform/module/provider_modules"
 		terraformParser := TerraformParser{}
@@ -651,6 +710,406 @@ func TestTerraformParser_Module(t *testing.T) {
 	})
 }
 
+func TestTerraformParser_JSONFile(t *testing.T) {
+	rootDir := "../../../tests/terraform/resources/tf_json"
+	filePath := "../../../tests/terraform/resources/tf_json/main.tf.json"
+
+	t.Run("parse resources nested by type and name, and modules", func(t *testing.T) {
+		p := &TerraformParser{}
+		p.Init(rootDir, nil)
+		parsedBlocks, err := p.ParseFile(filePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blocksByID := map[string]structure.IBlock{}
+		for _, block := range parsedBlocks {
+			blocksByID[block.GetResourceID()] = block
+		}
+		bucket := blocksByID["aws_s3_bucket.logs"]
+		assert.True(t, bucket.IsBlockTaggable())
+		assert.Equal(t, "aws_s3_bucket", bucket.GetResourceType())
+		assert.Equal(t, "logs", bucket.GetResourceName())
+		assert.Equal(t, structure.Lines{Start: 11, End: 17}, bucket.GetLines())
+		assert.Equal(t, structure.Lines{Start: 13, End: 16}, bucket.GetTagsLines())
+		assert.Equal(t, []tags.ITag{
+			&tags.Tag{Key: "env", Value: "dev"},
+			&tags.Tag{Key: "team", Value: "platform"},
+		}, bucket.GetExistingTags())
+
+		assert.True(t, blocksByID["aws_instance.web"].IsBlockTaggable())
+		assert.False(t, blocksByID["aws_iam_role_policy.web"].IsBlockTaggable())
+		assert.Equal(t, "labels", blocksByID["google_storage_bucket.assets"].GetTagsAttributeName())
+
+		vpc := blocksByID["vpc"]
+		assert.True(t, vpc.IsBlockTaggable())
+		assert.Equal(t, "tags", vpc.GetTagsAttributeName())
+		assert.Equal(t, []tags.ITag{&tags.Tag{Key: "owner", Value: "network"}}, vpc.GetExistingTags())
+	})
+
+	t.Run("write tags to objects and expressions", func(t *testing.T) {
+		p := &TerraformParser{}
+		p.Init(rootDir, nil)
+		parsedBlocks, err := p.ParseFile(filePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, block := range parsedBlocks {
+			if block.IsBlockTaggable() {
+				block.AddNewTags([]tags.ITag{
+					&tags.Tag{Key: "yor_trace", Value: "abc"},
+					&tags.Tag{Key: "env", Value: "prod"},
+					&tags.Tag{Key: "owner", Value: "yor"},
+				})
+			}
+		}
+		f, _ := os.CreateTemp(rootDir, "temp.*.tf.json")
+		defer func() {
+			_ = os.Remove(f.Name())
+		}()
+		err = p.WriteFile(filePath, parsedBlocks, f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := os.ReadFile(filepath.Join(rootDir, "expected.tf.json"))
+		actual, _ := os.ReadFile(f.Name())
+		assert.Equal(t, string(expected), string(actual))
+
+		taggedBlocks, err := p.ParseFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, block := range taggedBlocks {
+			if block.GetResourceID() == "google_storage_bucket.assets" {
+				assert.Equal(t, []tags.ITag{
+					&tags.Tag{Key: "env", Value: "prod"},
+					&tags.Tag{Key: "owner", Value: "yor"},
+					&tags.Tag{Key: "yor_trace", Value: "abc"},
+				}, block.GetExistingTags())
+			}
+		}
+	})
+}
+
+func TestTerraformParser_TagBlocks(t *testing.T) {
+	rootDir := "../../../tests/terraform/resources/asg"
+	filePath := "../../../tests/terraform/resources/asg/main.tf"
+	p := &TerraformParser{}
+	p.Init(rootDir, map[string]string{"asg-propagate-at-launch": "false"})
+	parsedBlocks, err := p.ParseFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(parsedBlocks))
+	web := parsedBlocks[0]
+	assert.True(t, web.IsBlockTaggable())
+	assert.Equal(t, "tag", web.GetTagsAttributeName())
+	assert.Equal(t, structure.Lines{Start: 6, End: 16}, web.GetTagsLines())
+	assert.Equal(t, []tags.ITag{
+		&tags.Tag{Key: "Name", Value: "web"},
+		&tags.Tag{Key: "env", Value: "dev"},
+	}, web.GetExistingTags())
+	assert.True(t, parsedBlocks[1].IsBlockTaggable())
+	assert.Equal(t, structure.Lines{Start: -1, End: -1}, parsedBlocks[1].GetTagsLines())
+
+	for _, block := range parsedBlocks {
+		block.AddNewTags([]tags.ITag{
+			&tags.Tag{Key: "yor_trace", Value: "abc"},
+			&tags.Tag{Key: "env", Value: "prod"},
+		})
+	}
+	f, _ := os.CreateTemp(rootDir, "temp.*.tf")
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	err = p.WriteFile(filePath, parsedBlocks, f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := os.ReadFile(filepath.Join(rootDir, "expected.tf"))
+	actual, _ := os.ReadFile(f.Name())
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestTerraformParser_Providers(t *testing.T) {
+	rootDir := "../../../tests/terraform/resources/providers"
+	filePath := filepath.Join(rootDir, "main.tf")
+	p := &TerraformParser{}
+	p.Init(rootDir, map[string]string{"config-file": filepath.Join(rootDir, "yor_config.yml")})
+	assert.Equal(t, &ProviderTagAttribute{Name: "labels", Format: ListTagsFormat, Separator: "=", InvalidValueCharacters: "[^a-z0-9_]", invalidValueRegex: regexp.MustCompile("[^a-z0-9_]")}, p.providerTagAttributes["mycloud"])
+	// the providers of the config file are not added to the built-in providers, which other parsers use
+	assert.NotContains(t, ProviderToTagAttribute, "mycloud")
+	// the provider schemas are not downloaded in the tests
+	for _, resourceType := range []string{"digitalocean_droplet", "linode_instance", "ibm_is_vpc", "huaweicloud_vpc", "exoscale_compute_instance", "mycloud_server"} {
+		p.taggableResourcesCache[resourceType] = true
+	}
+	parsedBlocks, err := p.ParseFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// vsphere resources are skipped with a warning
+	assert.Equal(t, 6, len(parsedBlocks))
+	_, err = p.getTagAttributeByResourceType("vsphere_virtual_machine")
+	assert.EqualError(t, err, "resource type vsphere_virtual_machine is not supported, the tags of vsphere resources are the IDs of vsphere_tag resources rather than keys and values")
+	expectedTags := map[string][]tags.ITag{
+		"digitalocean_droplet.web":      {tags.Init("env", "dev"), tags.Init("web", "")},
+		"linode_instance.web":           {tags.Init("team", "web")},
+		"ibm_is_vpc.vpc":                {},
+		"huaweicloud_vpc.vpc":           {tags.Init("env", "dev")},
+		"exoscale_compute_instance.web": {tags.Init("env", "dev")},
+		"mycloud_server.web":            {tags.Init("env", "dev")},
+	}
+	for _, block := range parsedBlocks {
+		assert.True(t, block.IsBlockTaggable(), block.GetResourceID())
+		assert.Equal(t, expectedTags[block.GetResourceID()], block.GetExistingTags(), block.GetResourceID())
+		block.AddNewTags([]tags.ITag{
+			&tags.Tag{Key: "yor_trace", Value: "abc"},
+			&tags.Tag{Key: "git_file", Value: "src/main.tf"},
+			&tags.Tag{Key: "env", Value: "prod"},
+		})
+		// the characters the providers do not accept are replaced along with the tag constraints
+		tagging.ValidateNewTags(block)
+	}
+	f, _ := os.CreateTemp(rootDir, "temp.*.tf")
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	err = p.WriteFile(filePath, parsedBlocks, f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := os.ReadFile(filepath.Join(rootDir, "expected.tf"))
+	actual, _ := os.ReadFile(f.Name())
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestTerraformParser_MetaArguments(t *testing.T) {
+	rootDir := "../../../tests/terraform/resources/meta_arguments"
+	filePath := filepath.Join(rootDir, "main.tf")
+	p := &TerraformParser{}
+	p.Init(rootDir, nil)
+	parsedBlocks, err := p.ParseFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 3, len(parsedBlocks))
+	assert.Equal(t, ForEachMetaArgument, parsedBlocks[0].(*TerraformBlock).MetaArgument)
+	assert.Equal(t, CountMetaArgument, parsedBlocks[1].(*TerraformBlock).MetaArgument)
+	assert.Equal(t, "", parsedBlocks[2].(*TerraformBlock).MetaArgument)
+
+	yorNameTag := &code2cloud.YorNameTag{}
+	yorNameTag.Init()
+	for _, block := range parsedBlocks {
+		yorName, _ := yorNameTag.CalculateValue(block)
+		block.AddNewTags([]tags.ITag{
+			yorName,
+			&tags.Tag{Key: "team", Value: "${each.key}-team", IsTemplate: true},
+			&tags.Tag{Key: "owner", Value: `${lookup(var.owners, "web")}`, IsTemplate: true},
+			// computed values are written as literals, even if they look like templates
+			&tags.Tag{Key: "git_last_modified_by", Value: `${path.cwd}"@example.com`, Priority: tags.GitTagPriority},
+			&tags.Tag{Key: "git_modifiers", Value: "${each.key}", Priority: tags.GitTagPriority},
+		})
+	}
+	assert.Equal(t, []tags.ITag{
+		&tags.Tag{Key: "yor_name", Value: "buckets-${each.key}", Priority: tags.YorNameTagPriority, IsTemplate: true},
+		&tags.Tag{Key: "team", Value: "${each.key}-team", IsTemplate: true},
+		&tags.Tag{Key: "owner", Value: `${lookup(var.owners, "web")}`, IsTemplate: true},
+		&tags.Tag{Key: "git_modifiers", Value: "${each.key}", Priority: tags.GitTagPriority},
+		&tags.Tag{Key: "git_last_modified_by", Value: `${path.cwd}"@example.com`, Priority: tags.GitTagPriority},
+	}, parsedBlocks[0].GetNewTags())
+	assert.Equal(t, []tags.ITag{
+		&tags.Tag{Key: "yor_name", Value: "web-${count.index}", Priority: tags.YorNameTagPriority, IsTemplate: true},
+		&tags.Tag{Key: "owner", Value: `${lookup(var.owners, "web")}`, IsTemplate: true},
+		&tags.Tag{Key: "git_modifiers", Value: "${each.key}", Priority: tags.GitTagPriority},
+		&tags.Tag{Key: "git_last_modified_by", Value: `${path.cwd}"@example.com`, Priority: tags.GitTagPriority},
+	}, parsedBlocks[1].GetNewTags())
+
+	f, _ := os.CreateTemp(rootDir, "temp.*.tf")
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	err = p.WriteFile(filePath, parsedBlocks, f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := os.ReadFile(filepath.Join(rootDir, "expected.tf"))
+	actual, _ := os.ReadFile(f.Name())
+	assert.Equal(t, string(expected), string(actual))
+
+	// the written templates are read as the same values, so they are not updated again
+	writtenBlocks, err := p.ParseFile(filepath.Join(rootDir, "expected.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, block := range writtenBlocks {
+		block.AddNewTags(parsedBlocks[i].GetNewTags())
+		diff := block.CalculateTagsDiff()
+		assert.Empty(t, diff.Added, block.GetResourceID())
+		assert.Empty(t, diff.Updated, block.GetResourceID())
+	}
+}
+
+func TestTerraformParser_NestedTags(t *testing.T) {
+	rootDir := "../../../tests/terraform/resources/nested_tags"
+	p := &TerraformParser{}
+	p.Init(rootDir, nil)
+	tagResources := func(readFilePath string, traceID string) string {
+		parsedBlocks, err := p.ParseFile(readFilePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 4, len(parsedBlocks))
+		for _, block := range parsedBlocks {
+			block.AddNewTags([]tags.ITag{
+				&tags.Tag{Key: tags.YorTraceTagKey, Value: traceID},
+				&tags.Tag{Key: "team", Value: "platform"},
+			})
+		}
+		f, _ := os.CreateTemp(rootDir, "temp.*.tf")
+		defer func() {
+			_ = os.Remove(f.Name())
+		}()
+		if err = p.WriteFile(readFilePath, parsedBlocks, f.Name()); err != nil {
+			t.Fatal(err)
+		}
+		actual, _ := os.ReadFile(f.Name())
+		return string(actual)
+	}
+
+	expected, _ := os.ReadFile(filepath.Join(rootDir, "expected.tf"))
+	assert.Equal(t, string(expected), tagResources(filepath.Join(rootDir, "main.tf"), "trace-id"))
+	// the nested blocks keep the yor_trace of their resource
+	assert.Equal(t, string(expected), tagResources(filepath.Join(rootDir, "expected.tf"), "other-trace-id"))
+}
+
+func TestTerraformParser_LocalsTags(t *testing.T) {
+	rootDir := "../../../tests/terraform/resources/locals_tags"
+	filePath := filepath.Join(rootDir, "main.tf")
+	p := &TerraformParser{}
+	p.Init(rootDir, nil)
+	parsedBlocks, err := p.ParseFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedTags := map[string]map[string]string{
+		"aws_s3_bucket.locals":  {"owner": "bridgecrew", "env": "prod", "team": "platform"},
+		"aws_s3_bucket.merged":  {"env": "prod", "Name": "merged"},
+		"aws_s3_bucket.unknown": {},
+	}
+	assert.Equal(t, len(expectedTags), len(parsedBlocks))
+	for _, block := range parsedBlocks {
+		actualTags := make(map[string]string)
+		for _, tag := range block.GetExistingTags() {
+			actualTags[tag.GetKey()] = tag.GetValue()
+		}
+		assert.Equal(t, expectedTags[block.GetResourceID()], actualTags, block.GetResourceID())
+		block.AddNewTags([]tags.ITag{
+			&tags.Tag{Key: "team", Value: "platform"},
+			&tags.Tag{Key: "env", Value: "dev"},
+			&tags.Tag{Key: tags.YorTraceTagKey, Value: "trace-id"},
+		})
+	}
+
+	f, _ := os.CreateTemp(t.TempDir(), "temp.*.tf")
+	err = p.WriteFile(filePath, parsedBlocks, f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := os.ReadFile(filepath.Join(rootDir, "expected", "main.tf"))
+	actual, _ := os.ReadFile(f.Name())
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func copyDefaultTagsTestHelper(t *testing.T) string {
+	rootDir := t.TempDir()
+	for _, fileName := range []string{"main.tf", "providers.tf"} {
+		content, err := os.ReadFile(filepath.Join("../../../tests/terraform/resources/default_tags", fileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(filepath.Join(rootDir, fileName), content, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rootDir
+}
+
+func TestTerraformParser_ProviderDefaultTags(t *testing.T) {
+	t.Run("tags of the provider default tags are not added", func(t *testing.T) {
+		rootDir := copyDefaultTagsTestHelper(t)
+		p := &TerraformParser{}
+		p.Init(rootDir, nil)
+		defer p.Close()
+		parsedBlocks, err := p.ParseFile(filepath.Join(rootDir, "main.tf"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 3, len(parsedBlocks))
+		defaultTags := []tags.ITag{tags.Init("env", "dev"), tags.Init("team", "platform")}
+		assert.Equal(t, defaultTags, parsedBlocks[0].(*TerraformBlock).ProviderDefaultTags)
+		assert.Empty(t, parsedBlocks[1].(*TerraformBlock).ProviderDefaultTags)
+		assert.Equal(t, defaultTags, parsedBlocks[2].(*TerraformBlock).ProviderDefaultTags)
+
+		for _, block := range parsedBlocks {
+			block.AddNewTags([]tags.ITag{
+				&tags.Tag{Key: "yor_trace", Value: "abc"},
+				&tags.Tag{Key: "env", Value: "dev"},
+				&tags.Tag{Key: "team", Value: "platform"},
+			})
+		}
+		// the default provider applies the env and team tags
+		assert.Equal(t, []tags.ITag{&tags.Tag{Key: "yor_trace", Value: "abc"}}, parsedBlocks[0].GetNewTags())
+		// the aliased provider has no default tags
+		assert.Equal(t, 3, len(parsedBlocks[1].GetNewTags()))
+		// the env tag of the resource overrides the default tag, so it is updated
+		assert.Equal(t, []tags.ITag{
+			&tags.Tag{Key: "yor_trace", Value: "abc"},
+			&tags.Tag{Key: "env", Value: "dev"},
+		}, parsedBlocks[2].GetNewTags())
+	})
+
+	t.Run("static tags are written to the provider default tags", func(t *testing.T) {
+		rootDir := copyDefaultTagsTestHelper(t)
+		p := &TerraformParser{}
+		p.Init(rootDir, map[string]string{"use-default-tags": "true"})
+		staticTags := []tags.ITag{tags.Init("team", "platform"), tags.Init("owner", "infra")}
+		p.SetStaticTags(staticTags)
+		filePath := filepath.Join(rootDir, "main.tf")
+		parsedBlocks, err := p.ParseFile(filePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, block := range parsedBlocks {
+			block.AddNewTags(append([]tags.ITag{&tags.Tag{Key: "yor_trace", Value: "abc"}}, staticTags...))
+		}
+		err = p.WriteFile(filePath, parsedBlocks, filePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.Close()
+
+		for _, fileName := range []string{"main.tf", "providers.tf"} {
+			expected, _ := os.ReadFile(filepath.Join("../../../tests/terraform/resources/default_tags/expected", fileName))
+			actual, _ := os.ReadFile(filepath.Join(rootDir, fileName))
+			assert.Equal(t, string(expected), string(actual))
+		}
+	})
+
+	t.Run("default tags of providers in terraform JSON files", func(t *testing.T) {
+		rootDir := "../../../tests/terraform/resources/default_tags_json"
+		p := &TerraformParser{}
+		p.Init(rootDir, map[string]string{"use-default-tags": "true"})
+		p.SetStaticTags([]tags.ITag{tags.Init("owner", "infra")})
+		parsedBlocks, err := p.ParseFile(filepath.Join(rootDir, "main.tf.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 2, len(parsedBlocks))
+		// the static tags are not written to providers in JSON files, so they are not part of their default tags
+		assert.Equal(t, []tags.ITag{tags.Init("env", "dev"), tags.Init("team", "platform")}, parsedBlocks[0].(*TerraformBlock).ProviderDefaultTags)
+		assert.Empty(t, parsedBlocks[1].(*TerraformBlock).ProviderDefaultTags)
+	})
+}
+
 func TestExtractProviderFromModuleSrc(t *testing.T) {
 	tests := []struct {
 		name   string
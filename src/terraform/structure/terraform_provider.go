@@ -0,0 +1,303 @@
+package structure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common"
+	"github.com/bridgecrewio/yor/src/common/logger"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+)
+
+const ProviderBlockType = "provider"
+
+// ProviderToDefaultTagsBlock maps the providers which apply tags to all of their resources to the block of the
+// provider configuration which holds these tags
+var ProviderToDefaultTagsBlock = map[string]string{"aws": "default_tags"}
+
+// providerConfig is a provider block of a module, which resources use by the provider name, or by the provider name
+// and the alias of the configuration in their provider meta-argument, e.g. aws.west
+type providerConfig struct {
+	filePath    string
+	name        string
+	alias       string
+	defaultTags []tags.ITag
+	// used is set for configurations of written resources, which get the static tags in their default tags
+	used bool
+}
+
+func (c *providerConfig) isJSON() bool {
+	return strings.HasSuffix(c.filePath, common.TfJSONFileType.Extension)
+}
+
+func (c *providerConfig) getKey() string {
+	if c.alias == "" {
+		return c.name
+	}
+	return c.name + "." + c.alias
+}
+
+// SetStaticTags sets the tags which are the same for all of the resources, and are written to the default tags of the
+// provider configurations rather than to each resource when running with use-default-tags
+func (p *TerraformParser) SetStaticTags(staticTags []tags.ITag) {
+	p.staticTags = staticTags
+}
+
+// getProviderConfig returns the provider configuration with the given key declared in the directory, or nil if the
+// directory does not declare it
+func (p *TerraformParser) getProviderConfig(dir string, key string) *providerConfig {
+	p.providerConfigsLock.Lock()
+	defer p.providerConfigsLock.Unlock()
+	configs, ok := p.providerConfigsByDir[dir]
+	if !ok {
+		configs = p.readProviderConfigs(dir)
+		p.providerConfigsByDir[dir] = configs
+	}
+	return configs[key]
+}
+
+func (p *TerraformParser) readProviderConfigs(dir string) map[string]*providerConfig {
+	configs := make(map[string]*providerConfig)
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return configs
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		filePath := filepath.Join(dir, file.Name())
+		isJSON := strings.HasSuffix(file.Name(), common.TfJSONFileType.Extension)
+		if !isJSON && !strings.HasSuffix(file.Name(), common.TfFileType.Extension) {
+			continue
+		}
+		// #nosec G304
+		src, err := os.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+		if isJSON {
+			for _, config := range p.readJSONProviderConfigs(src, filePath) {
+				configs[config.getKey()] = config
+			}
+			continue
+		}
+		hclFile, diagnostics := hclwrite.ParseConfig(src, filePath, hcl.InitialPos)
+		if diagnostics.HasErrors() {
+			continue
+		}
+		for _, block := range hclFile.Body().Blocks() {
+			if block.Type() != ProviderBlockType || len(block.Labels()) != 1 {
+				continue
+			}
+			defaultTagsBlockType, ok := ProviderToDefaultTagsBlock[block.Labels()[0]]
+			if !ok {
+				continue
+			}
+			config := &providerConfig{
+				filePath: filePath,
+				name:     block.Labels()[0],
+				alias:    getBodyAttributeValue(block.Body(), "alias"),
+			}
+			if defaultTagsBlock := block.Body().FirstMatchingBlock(defaultTagsBlockType, nil); defaultTagsBlock != nil {
+				config.defaultTags = p.getDefaultTags(defaultTagsBlock)
+			}
+			configs[config.getKey()] = config
+		}
+	}
+	return configs
+}
+
+// readJSONProviderConfigs returns the provider configurations of a file in the terraform JSON syntax, in which the
+// configurations of a provider are an object or a list of objects, e.g. {"provider": {"aws": [{...}, {"alias": ...}]}}
+func (p *TerraformParser) readJSONProviderConfigs(src []byte, filePath string) []*providerConfig {
+	var configs []*providerConfig
+	file, diagnostics := hcljson.Parse(src, filePath)
+	if diagnostics.HasErrors() {
+		return configs
+	}
+	content, _, diagnostics := file.Body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: ProviderBlockType, LabelNames: []string{"name"}}},
+	})
+	if diagnostics.HasErrors() {
+		return configs
+	}
+	for _, block := range content.Blocks {
+		defaultTagsBlockType, ok := ProviderToDefaultTagsBlock[block.Labels[0]]
+		if !ok {
+			continue
+		}
+		providerContent, _, diagnostics := block.Body.PartialContent(&hcl.BodySchema{
+			Attributes: []hcl.AttributeSchema{{Name: "alias"}},
+			Blocks:     []hcl.BlockHeaderSchema{{Type: defaultTagsBlockType}},
+		})
+		if diagnostics.HasErrors() {
+			continue
+		}
+		config := &providerConfig{
+			filePath:    filePath,
+			name:        block.Labels[0],
+			alias:       getJSONAttributeString(src, providerContent.Attributes["alias"]),
+			defaultTags: make([]tags.ITag, 0),
+		}
+		for _, defaultTagsBlock := range providerContent.Blocks {
+			attributes, _ := defaultTagsBlock.Body.JustAttributes()
+			if attribute, ok := attributes["tags"]; ok {
+				config.defaultTags = p.getJSONAttributeTags(src, attribute)
+			}
+		}
+		configs = append(configs, config)
+	}
+	return configs
+}
+
+func (p *TerraformParser) getDefaultTags(defaultTagsBlock *hclwrite.Block) []tags.ITag {
+	defaultTags := make([]tags.ITag, 0)
+	tagsAttribute := defaultTagsBlock.Body().GetAttribute("tags")
+	if tagsAttribute == nil {
+		return defaultTags
+	}
+	parsedTags := p.parseTagAttribute(tagsAttribute.Expr().BuildTokens(hclwrite.Tokens{}))
+	keys := make([]string, 0, len(parsedTags))
+	for key := range parsedTags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		defaultTags = append(defaultTags, tags.Init(key, parsedTags[key]))
+	}
+	return defaultTags
+}
+
+// getProviderDefaultTags returns the tags the provider configuration applies to its resources, including the static
+// tags which are written to the configuration when running with use-default-tags. The static tags are not written to
+// configurations in the terraform JSON syntax, so their resources get the static tags themselves.
+func (p *TerraformParser) getProviderDefaultTags(config *providerConfig) []tags.ITag {
+	if !p.useDefaultTags || len(p.staticTags) == 0 || config.isJSON() {
+		return config.defaultTags
+	}
+	defaultTags := make([]tags.ITag, 0, len(config.defaultTags)+len(p.staticTags))
+	for _, tag := range config.defaultTags {
+		if !isTagKeyIn(tag.GetKey(), p.staticTags) {
+			defaultTags = append(defaultTags, tag)
+		}
+	}
+	return append(defaultTags, p.staticTags...)
+}
+
+func isTagKeyIn(key string, tagsList []tags.ITag) bool {
+	for _, tag := range tagsList {
+		if tag.GetKey() == key {
+			return true
+		}
+	}
+	return false
+}
+
+// getResourceProviderKey returns the provider configuration of the resource, from its provider meta-argument or from
+// the provider of its type
+func getResourceProviderKey(providerArgument string, resourceType string) string {
+	if providerArgument != "" {
+		return providerArgument
+	}
+	return getProviderFromResourceType(resourceType)
+}
+
+// setProviderConfig sets the provider configuration of a resource and the tags it applies to the resource
+func (p *TerraformParser) setProviderConfig(terraformBlock *TerraformBlock, filePath string, providerArgument string) {
+	config := p.getProviderConfig(filepath.Dir(filePath), getResourceProviderKey(providerArgument, terraformBlock.GetResourceType()))
+	if config == nil {
+		return
+	}
+	terraformBlock.providerConfig = config
+	terraformBlock.ProviderDefaultTags = p.getProviderDefaultTags(config)
+}
+
+// markProviderConfigsUsed marks the provider configurations of the written resources, which should get the static tags
+func (p *TerraformParser) markProviderConfigsUsed(blocks []structure.IBlock) {
+	if !p.useDefaultTags {
+		return
+	}
+	p.providerConfigsLock.Lock()
+	defer p.providerConfigsLock.Unlock()
+	for _, block := range blocks {
+		if terraformBlock, ok := block.(*TerraformBlock); ok && terraformBlock.providerConfig != nil && !terraformBlock.providerConfig.isJSON() && terraformBlock.IsBlockTaggable() {
+			terraformBlock.providerConfig.used = true
+		}
+	}
+}
+
+// writeProvidersDefaultTags writes the static tags to the default tags of the used provider configurations
+func (p *TerraformParser) writeProvidersDefaultTags() {
+	configsByFile := make(map[string][]*providerConfig)
+	for _, configs := range p.providerConfigsByDir {
+		for _, config := range configs {
+			if config.used {
+				configsByFile[config.filePath] = append(configsByFile[config.filePath], config)
+			}
+		}
+	}
+	for filePath, configs := range configsByFile {
+		if err := p.writeFileDefaultTags(filePath, configs); err != nil {
+			logger.Warning(fmt.Sprintf("Failed writing default tags to file %s, because %v", filePath, err))
+		}
+	}
+}
+
+func (p *TerraformParser) writeFileDefaultTags(filePath string, configs []*providerConfig) error {
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	hclFile, diagnostics := hclwrite.ParseConfig(src, filePath, hcl.InitialPos)
+	if diagnostics.HasErrors() {
+		return fmt.Errorf("failed to parse hcl file %s because of errors %s", filePath, diagnostics.Errs())
+	}
+	modified := false
+	for _, block := range hclFile.Body().Blocks() {
+		if block.Type() != ProviderBlockType || len(block.Labels()) != 1 {
+			continue
+		}
+		for _, config := range configs {
+			if config.name != block.Labels()[0] || config.alias != getBodyAttributeValue(block.Body(), "alias") {
+				continue
+			}
+			defaultTagsBlock := &TerraformBlock{
+				Block: structure.Block{
+					FilePath:          filePath,
+					ExitingTags:       config.defaultTags,
+					IsTaggable:        true,
+					TagsAttributeName: "tags",
+				},
+				HclSyntaxBlock: &hclsyntax.Block{Type: ProviderBlockType, Labels: []string{config.getKey()}},
+			}
+			defaultTagsBlock.AddNewTags(p.staticTags)
+			diff := defaultTagsBlock.CalculateTagsDiff()
+			if len(diff.Added) == 0 && len(diff.Updated) == 0 {
+				continue
+			}
+			defaultTagsBlockType := ProviderToDefaultTagsBlock[config.name]
+			rawDefaultTagsBlock := block.Body().FirstMatchingBlock(defaultTagsBlockType, nil)
+			if rawDefaultTagsBlock == nil {
+				block.Body().AppendNewline()
+				rawDefaultTagsBlock = block.Body().AppendNewBlock(defaultTagsBlockType, nil)
+			}
+			p.modifyBlockTags(rawDefaultTagsBlock, defaultTagsBlock)
+			modified = true
+			logger.Info(fmt.Sprintf("Writing the static tags to the default tags of provider %s in %s", config.getKey(), filePath))
+		}
+	}
+	if !modified {
+		return nil
+	}
+	return os.WriteFile(filePath, hclwrite.Format(hclFile.Bytes()), 0600)
+}
@@ -0,0 +1,123 @@
+package structure
+
+import (
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const CountMetaArgument = "count"
+const ForEachMetaArgument = "for_each"
+
+// metaArgumentReferences maps the meta-arguments of the blocks with multiple instances to the object which templates
+// use to refer to each instance, e.g. "${each.key}" or "${count.index}"
+var metaArgumentReferences = map[string]string{ForEachMetaArgument: EachBlockType, CountMetaArgument: CountMetaArgument}
+
+// IsTemplateValue checks if the tag value is an HCL template with interpolations or directives, such as
+// "${each.key}", which is written as a template expression rather than a quoted string
+func IsTemplateValue(value string) bool {
+	if !strings.Contains(value, "${") && !strings.Contains(value, "%{") {
+		return false
+	}
+	expr, diagnostics := hclsyntax.ParseTemplate([]byte(value), "", hcl.InitialPos)
+	if diagnostics.HasErrors() {
+		return false
+	}
+	// templates without interpolations, e.g. escaped ones such as "$${each.key}", evaluate without a context
+	_, diagnostics = expr.Value(nil)
+	return diagnostics.HasErrors()
+}
+
+// isTemplateTag checks if the tag is configured by a user and its value is a template, the computed values are literals
+// even if they look like templates, e.g. a git author "${path.cwd}"
+func isTemplateTag(tag tags.ITag) bool {
+	return tags.IsTemplateTag(tag) && IsTemplateValue(tag.GetValue())
+}
+
+// parseQuotedValue returns the value of a quoted string expression. Templates with interpolations are returned as written,
+// e.g. "${each.key}", and escaped templates are unescaped, e.g. "$${each.key}" is read as ${each.key}
+func parseQuotedValue(expression string) (string, bool) {
+	expr, diagnostics := hclsyntax.ParseExpression([]byte(expression), "", hcl.InitialPos)
+	if diagnostics.HasErrors() {
+		return "", false
+	}
+	switch expr.(type) {
+	case *hclsyntax.TemplateExpr, *hclsyntax.TemplateWrapExpr:
+	default:
+		return "", false
+	}
+	value, diagnostics := expr.Value(nil)
+	if diagnostics.HasErrors() {
+		return expression[1 : len(expression)-1], true
+	}
+	if value.Type() != cty.String || value.IsNull() {
+		return "", false
+	}
+	return value.AsString(), true
+}
+
+// getTemplateRoots returns the names of the objects the template value refers to, e.g. each for "${each.key}"
+func getTemplateRoots(value string) []string {
+	expr, diagnostics := hclsyntax.ParseTemplate([]byte(value), "", hcl.InitialPos)
+	if diagnostics.HasErrors() {
+		return nil
+	}
+	var roots []string
+	for _, traversal := range expr.Variables() {
+		roots = append(roots, traversal.RootName())
+	}
+	return roots
+}
+
+// isTemplateValid checks if the template value can be written to a block with the given meta-argument, templates which
+// refer to each or count are only valid in blocks with for_each or count, respectively
+func isTemplateValid(value string, metaArgument string) bool {
+	for _, root := range getTemplateRoots(value) {
+		for blockMetaArgument, reference := range metaArgumentReferences {
+			if root == reference && blockMetaArgument != metaArgument {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// getMetaArgument returns the meta-argument which creates multiple instances of the block, if it has one
+func getMetaArgument(hasAttribute func(name string) bool) string {
+	for _, metaArgument := range []string{ForEachMetaArgument, CountMetaArgument} {
+		if hasAttribute(metaArgument) {
+			return metaArgument
+		}
+	}
+	return ""
+}
+
+// renderTemplateTokens replaces the quoted strings of the template tags, which hclwrite escapes, e.g. "$${each.key}",
+// with the templates themselves. The values of the other tags stay escaped.
+func renderTemplateTokens(tokens hclwrite.Tokens, tagsToRender []tags.ITag) hclwrite.Tokens {
+	escapedTemplates := make(map[string]string)
+	for _, tag := range tagsToRender {
+		if !isTemplateTag(tag) {
+			continue
+		}
+		for _, token := range hclwrite.TokensForValue(cty.StringVal(tag.GetValue())) {
+			if token.Type == hclsyntax.TokenQuotedLit {
+				// the template is written as configured, as the escaping of quotes inside interpolations is invalid
+				escapedTemplates[string(token.Bytes)] = tag.GetValue()
+			}
+		}
+	}
+	if len(escapedTemplates) == 0 {
+		return tokens
+	}
+	for _, token := range tokens {
+		if template, ok := escapedTemplates[string(token.Bytes)]; ok && token.Type == hclsyntax.TokenQuotedLit {
+			token.Bytes = []byte(template)
+		}
+	}
+	return tokens
+}
@@ -1,5 +1,8 @@
 package structure
 
+// TfTaggableResourceTypes are the resource types known to accept tags. The list is maintained by hand, and running go
+// generate ./src/terraform/structure with YOR_PROVIDER_SCHEMAS replaces it with the list generated from provider schema
+// snapshots, which then carries the generated code header.
 var TfTaggableResourceTypes = []string{
 	"aws_accessanalyzer_analyzer",
 	"aws_acm_certificate",
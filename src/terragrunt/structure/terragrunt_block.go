@@ -0,0 +1,25 @@
+package structure
+
+import (
+	"github.com/bridgecrewio/yor/src/common/structure"
+)
+
+type TerragruntBlock struct {
+	structure.Block
+}
+
+func (b *TerragruntBlock) GetResourceID() string {
+	return b.Name
+}
+
+func (b *TerragruntBlock) GetResourceName() string {
+	return b.Name
+}
+
+func (b *TerragruntBlock) GetTagsLines() structure.Lines {
+	return b.TagLines
+}
+
+func (b *TerragruntBlock) GetSeparator() string {
+	return "="
+}
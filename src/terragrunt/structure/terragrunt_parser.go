@@ -0,0 +1,247 @@
+package structure
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bridgecrewio/yor/src/common"
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	tfStructure "github.com/bridgecrewio/yor/src/terraform/structure"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const InputsAttributeName = "inputs"
+const DefaultTagsKey = "tags"
+const TerragruntResourceType = "terragrunt"
+const terragruntFileName = "terragrunt.hcl"
+
+// TagsKeyArg is the option of the key of the inputs which holds the tags, e.g. common_tags for inputs.common_tags
+const TagsKeyArg = "terragrunt-tags-key"
+
+type TerragruntParser struct {
+	rootDir              string
+	tagsKey              string
+	tfParser             *tfStructure.TerraformParser
+	skippedByCommentList []string
+}
+
+func (p *TerragruntParser) Name() string {
+	return "Terragrunt"
+}
+
+func (p *TerragruntParser) Init(rootDir string, args map[string]string) {
+	p.rootDir = rootDir
+	p.tagsKey = DefaultTagsKey
+	if tagsKey := args[TagsKeyArg]; tagsKey != "" {
+		p.tagsKey = tagsKey
+	}
+	// the tags are parsed and written by the terraform parser, with the same options as terraform configurations
+	p.tfParser = &tfStructure.TerraformParser{}
+	p.tfParser.Init(rootDir, args)
+}
+
+func (p *TerragruntParser) Close() {
+	p.tfParser.Close()
+}
+
+func (p *TerragruntParser) GetSkippedDirs() []string {
+	return []string{".terragrunt-cache"}
+}
+
+func (p *TerragruntParser) GetSupportedFileExtensions() []string {
+	return []string{common.HclFileType.Extension}
+}
+
+func (p *TerragruntParser) GetSkipResourcesByComment() []string {
+	return p.skippedByCommentList
+}
+
+func (p *TerragruntParser) ValidFile(filePath string) bool {
+	return filepath.Base(filePath) == terragruntFileName
+}
+
+// ParseFile returns a single block for the terragrunt.hcl file, which spans the whole file so its tags are based on the
+// blame of the file. Its tags are the tags key of the inputs, the file is not taggable if its inputs are not written
+// as an object, e.g. inputs = merge(local.inputs, {...}).
+func (p *TerragruntParser) ParseFile(filePath string) ([]structure.IBlock, error) {
+	// #nosec G304
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s because %s", filePath, err)
+	}
+	hclSyntaxFile, diagnostics := hclsyntax.ParseConfig(src, filePath, hcl.InitialPos)
+	if diagnostics != nil && diagnostics.HasErrors() {
+		return nil, fmt.Errorf("failed to parse hcl file %s because of errors %s", filePath, diagnostics.Errs())
+	}
+	inputsAttribute, inputsObject, tagsItem := p.findTags(hclSyntaxFile)
+
+	var existingTags []tags.ITag
+	tagsLines := structure.Lines{Start: -1, End: -1}
+	if tagsItem != nil {
+		tagsBlock, err := p.parseTagsBlock(getRangeBytes(src, tagsItem.ValueExpr.Range()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the %s input of %s because %s", p.tagsKey, filePath, err)
+		}
+		parsedTags := p.tfParser.ParseTagsAttribute(tagsBlock.Body().GetAttribute(p.tagsKey).Expr().BuildTokens(hclwrite.Tokens{}))
+		keys := make([]string, 0, len(parsedTags))
+		for key := range parsedTags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			existingTags = append(existingTags, &tags.Tag{Key: key, Value: parsedTags[key]})
+		}
+		tagsLines = structure.Lines{Start: tagsItem.KeyExpr.Range().Start.Line, End: tagsItem.ValueExpr.Range().End.Line}
+	}
+
+	lines := strings.Split(strings.TrimRight(string(src), "\n"), "\n")
+	name := p.getName(filePath)
+	if inputsAttribute != nil {
+		if line := inputsAttribute.SrcRange.Start.Line; line > 1 && strings.ToUpper(strings.TrimSpace(lines[line-2])) == "#YOR:SKIP" {
+			p.skippedByCommentList = append(p.skippedByCommentList, name)
+		}
+	}
+	terragruntBlock := &TerragruntBlock{
+		Block: structure.Block{
+			FilePath:          filePath,
+			ExitingTags:       existingTags,
+			RawBlock:          inputsAttribute,
+			IsTaggable:        inputsAttribute == nil || inputsObject != nil,
+			TagsAttributeName: p.tagsKey,
+			Lines:             structure.Lines{Start: 1, End: len(lines)},
+			TagLines:          tagsLines,
+			Name:              name,
+			Type:              TerragruntResourceType,
+		},
+	}
+	return []structure.IBlock{terragruntBlock}, nil
+}
+
+// getName returns the directory of the file relative to the root directory, which identifies the terragrunt module
+func (p *TerragruntParser) getName(filePath string) string {
+	dir := filepath.Dir(filePath)
+	if relDir, err := filepath.Rel(p.rootDir, dir); err == nil && relDir != "." && !strings.HasPrefix(relDir, "..") {
+		return filepath.ToSlash(relDir)
+	}
+	absDir, _ := filepath.Abs(dir)
+	return filepath.Base(absDir)
+}
+
+// findTags returns the inputs attribute of the file, its object if it is written as an object and the item of the
+// object which holds the tags
+func (p *TerragruntParser) findTags(hclSyntaxFile *hcl.File) (*hclsyntax.Attribute, *hclsyntax.ObjectConsExpr, *hclsyntax.ObjectConsItem) {
+	inputsAttribute, ok := hclSyntaxFile.Body.(*hclsyntax.Body).Attributes[InputsAttributeName]
+	if !ok {
+		return nil, nil, nil
+	}
+	inputsObject, ok := inputsAttribute.Expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return inputsAttribute, nil, nil
+	}
+	for i, item := range inputsObject.Items {
+		key, diagnostics := item.KeyExpr.Value(nil)
+		if diagnostics.HasErrors() || !key.IsKnown() || key.IsNull() || key.Type() != cty.String {
+			continue
+		}
+		if key.AsString() == p.tagsKey {
+			return inputsAttribute, inputsObject, &inputsObject.Items[i]
+		}
+	}
+	return inputsAttribute, inputsObject, nil
+}
+
+// parseTagsBlock returns a block with the tags attribute of the given value, which the terraform parser edits as the
+// tags attribute of a resource
+func (p *TerragruntParser) parseTagsBlock(tagsSrc []byte) (*hclwrite.Block, error) {
+	blockSrc := fmt.Sprintf("%s {\n}\n", InputsAttributeName)
+	if tagsSrc != nil {
+		blockSrc = fmt.Sprintf("%s {\n%s = %s\n}\n", InputsAttributeName, p.tagsKey, tagsSrc)
+	}
+	hclFile, diagnostics := hclwrite.ParseConfig([]byte(blockSrc), "", hcl.InitialPos)
+	if diagnostics != nil && diagnostics.HasErrors() {
+		return nil, fmt.Errorf("%s", diagnostics.Errs())
+	}
+	return hclFile.Body().Blocks()[0], nil
+}
+
+func getRangeBytes(src []byte, r hcl.Range) []byte {
+	return src[r.Start.Byte:r.End.Byte]
+}
+
+func (p *TerragruntParser) WriteFile(readFilePath string, blocks []structure.IBlock, writeFilePath string) error {
+	// #nosec G304
+	src, err := os.ReadFile(readFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s because %s", readFilePath, err)
+	}
+	hclSyntaxFile, diagnostics := hclsyntax.ParseConfig(src, readFilePath, hcl.InitialPos)
+	if diagnostics != nil && diagnostics.HasErrors() {
+		return fmt.Errorf("failed to parse hcl file %s because of errors %s", readFilePath, diagnostics.Errs())
+	}
+	_, inputsObject, tagsItem := p.findTags(hclSyntaxFile)
+	for _, block := range blocks {
+		terragruntBlock, ok := block.(*TerragruntBlock)
+		if !ok || !terragruntBlock.IsBlockTaggable() {
+			continue
+		}
+		var tagsSrc []byte
+		if tagsItem != nil {
+			tagsSrc = getRangeBytes(src, tagsItem.ValueExpr.Range())
+		}
+		tagsBlock, err := p.parseTagsBlock(tagsSrc)
+		if err != nil {
+			return fmt.Errorf("failed to parse the %s input of %s because %s", p.tagsKey, readFilePath, err)
+		}
+		p.tfParser.ModifyTagsAttribute(tagsBlock, &tfStructure.TerraformBlock{
+			Block:          terragruntBlock.Block,
+			HclSyntaxBlock: &hclsyntax.Block{Type: InputsAttributeName, Labels: []string{terragruntBlock.GetResourceID()}},
+		})
+		tagsAttribute := tagsBlock.Body().GetAttribute(p.tagsKey)
+		if tagsAttribute == nil {
+			continue
+		}
+		newTagsSrc := bytes.TrimSpace(tagsAttribute.Expr().BuildTokens(hclwrite.Tokens{}).Bytes())
+		switch {
+		case tagsItem != nil:
+			src = spliceBytes(src, tagsItem.ValueExpr.Range().Start.Byte, tagsItem.ValueExpr.Range().End.Byte, newTagsSrc)
+		case inputsObject != nil:
+			// the tags are added as the last item of the inputs, before their closing brace
+			closingBraceIndex := inputsObject.SrcRange.End.Byte - 1
+			src = spliceBytes(src, closingBraceIndex, closingBraceIndex, []byte(fmt.Sprintf("\n%s = %s\n", p.tagsKey, newTagsSrc)))
+		default:
+			if len(src) > 0 && !bytes.HasSuffix(src, []byte("\n")) {
+				src = append(src, '\n')
+			}
+			src = append(src, []byte(fmt.Sprintf("\n%s = {\n%s = %s\n}\n", InputsAttributeName, p.tagsKey, newTagsSrc))...)
+		}
+	}
+
+	hclFile, diagnostics := hclwrite.ParseConfig(src, readFilePath, hcl.InitialPos)
+	if diagnostics != nil && diagnostics.HasErrors() {
+		return fmt.Errorf("editing file %v resulted in malformed terragrunt configuration, please open a github issue with the relevant details", readFilePath)
+	}
+	// #nosec G304
+	f, err := os.OpenFile(writeFilePath, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = hclFile.WriteTo(f); err != nil {
+		return fmt.Errorf("failed to write HCL file %s, %s", readFilePath, err.Error())
+	}
+	return f.Close()
+}
+
+func spliceBytes(src []byte, start int, end int, replacement []byte) []byte {
+	result := make([]byte, 0, len(src)-(end-start)+len(replacement))
+	result = append(result, src[:start]...)
+	result = append(result, replacement...)
+	return append(result, src[end:]...)
+}
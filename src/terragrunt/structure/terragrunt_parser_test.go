@@ -0,0 +1,101 @@
+package structure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bridgecrewio/yor/src/common/structure"
+	"github.com/bridgecrewio/yor/src/common/tagging/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+const liveDir = "../../../tests/terragrunt/resources/live"
+
+func TestTerragruntParser_ValidFile(t *testing.T) {
+	p := TerragruntParser{}
+	p.Init(liveDir, nil)
+	assert.True(t, p.ValidFile(filepath.Join(liveDir, "prod", "vpc", "terragrunt.hcl")))
+	assert.False(t, p.ValidFile(filepath.Join(liveDir, "prod", "vpc", "expected.hcl")))
+}
+
+func TestTerragruntParser_ParseFile(t *testing.T) {
+	p := TerragruntParser{}
+	p.Init(liveDir, nil)
+	blocks, err := p.ParseFile(filepath.Join(liveDir, "prod", "vpc", "terragrunt.hcl"))
+	if err != nil {
+		t.Errorf("ParseFile() error = %v", err)
+		return
+	}
+	assert.Len(t, blocks, 1)
+	vpcBlock := blocks[0].(*TerragruntBlock)
+	assert.Equal(t, "prod/vpc", vpcBlock.GetResourceID())
+	assert.Equal(t, TerragruntResourceType, vpcBlock.GetResourceType())
+	assert.True(t, vpcBlock.IsBlockTaggable())
+	assert.Equal(t, structure.Lines{Start: 1, End: 17}, vpcBlock.GetLines())
+	assert.Equal(t, structure.Lines{Start: 13, End: 16}, vpcBlock.GetTagsLines())
+	assert.Equal(t, []tags.ITag{
+		&tags.Tag{Key: "env", Value: "prod"},
+		&tags.Tag{Key: "team", Value: "network"},
+	}, vpcBlock.GetExistingTags())
+
+	blocks, err = p.ParseFile(filepath.Join(liveDir, "prod", "eks", "terragrunt.hcl"))
+	if err != nil {
+		t.Errorf("ParseFile() error = %v", err)
+		return
+	}
+	assert.False(t, blocks[0].IsBlockTaggable())
+}
+
+func TestTerragruntParser_WriteFile(t *testing.T) {
+	newTags := func() []tags.ITag {
+		return []tags.ITag{
+			&tags.Tag{Key: "env", Value: "production"},
+			&tags.Tag{Key: "yor_trace", Value: "3c1c2e4f-5a6b-4c7d-8e9f-0a1b2c3d4e5f"},
+			&tags.Tag{Key: "git_file", Value: "live/prod/terragrunt.hcl"},
+		}
+	}
+	tests := []struct {
+		name    string
+		dir     string
+		tagsKey string
+	}{
+		{name: "update the tags of the inputs", dir: "vpc", tagsKey: ""},
+		{name: "add the tags to the inputs by the configured key", dir: "rds", tagsKey: "common_tags"},
+		{name: "add the inputs", dir: "s3", tagsKey: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := TerragruntParser{}
+			p.Init(liveDir, map[string]string{TagsKeyArg: tt.tagsKey})
+			filePath := filepath.Join(liveDir, "prod", tt.dir, "terragrunt.hcl")
+			blocks, err := p.ParseFile(filePath)
+			if err != nil {
+				t.Errorf("ParseFile() error = %v", err)
+				return
+			}
+			blocks[0].AddNewTags(newTags())
+			f, _ := os.CreateTemp(filepath.Dir(filePath), "terragrunt.*.hcl")
+			defer func() { _ = os.Remove(f.Name()) }()
+			if err = p.WriteFile(filePath, blocks, f.Name()); err != nil {
+				t.Errorf("WriteFile() error = %v", err)
+				return
+			}
+
+			expected, _ := os.ReadFile(filepath.Join(filepath.Dir(filePath), "expected.hcl"))
+			actual, _ := os.ReadFile(f.Name())
+			assert.Equal(t, string(expected), string(actual))
+
+			// tagging the tagged file again changes nothing
+			taggedBlocks, err := p.ParseFile(f.Name())
+			if err != nil {
+				t.Errorf("ParseFile() error = %v", err)
+				return
+			}
+			taggedBlocks[0].AddNewTags(newTags())
+			diff := taggedBlocks[0].CalculateTagsDiff()
+			assert.Empty(t, diff.Added)
+			assert.Empty(t, diff.Updated)
+		})
+	}
+}
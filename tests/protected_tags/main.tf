@@ -0,0 +1,15 @@
+resource "aws_s3_bucket" "data" {
+  bucket = "data"
+  tags = {
+    cost_center = "1234"
+    billing_id  = "data-billing"
+    owner       = "data-team"
+  }
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+  tags = {
+    env = "dev"
+  }
+}
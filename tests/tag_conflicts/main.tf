@@ -0,0 +1,7 @@
+resource "aws_s3_bucket" "data" {
+  bucket = "data"
+  tags = {
+    env   = "dev"
+    owner = "data-team"
+  }
+}
@@ -0,0 +1,31 @@
+variable "common_tags" {
+  type    = map(string)
+  default = {}
+}
+
+resource "aws_s3_bucket" "data" {
+  bucket = "data"
+  tags = {
+    env               = "prod"
+    source_repository = "yor"
+    "yor_trace"       = "4c4a8d61-4f4e-4d6b-8d05-1c2a6f7c1e0a"
+  }
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+  tags = merge(var.common_tags, {
+    "source_repository" = "yor"
+  })
+}
+
+resource "aws_autoscaling_group" "workers" {
+  max_size = 2
+  min_size = 1
+
+  tag {
+    key                 = "source_repository"
+    value               = "yor"
+    propagate_at_launch = true
+  }
+}
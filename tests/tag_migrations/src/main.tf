@@ -0,0 +1,37 @@
+variable "common_tags" {
+  type    = map(string)
+  default = {}
+}
+
+resource "aws_s3_bucket" "data" {
+  bucket = "data"
+  tags = {
+    env           = "prod"
+    git_repo      = "yor"
+    git_modifiers = "alice/bob"
+    "yor_trace"   = "4c4a8d61-4f4e-4d6b-8d05-1c2a6f7c1e0a"
+  }
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+  tags = merge(var.common_tags, {
+    "git_repo" = "yor", git_modifiers = "alice"
+  })
+}
+
+resource "aws_autoscaling_group" "workers" {
+  max_size = 2
+  min_size = 1
+
+  tag {
+    key                 = "git_repo"
+    value               = "yor"
+    propagate_at_launch = true
+  }
+  tag {
+    key                 = "git_modifiers"
+    value               = "alice"
+    propagate_at_launch = true
+  }
+}
@@ -0,0 +1,10 @@
+variable "tags" {
+  type    = map(string)
+  default = {}
+}
+
+resource "kubernetes_namespace" "namespace" {
+  metadata {
+    name = "namespace"
+  }
+}
@@ -0,0 +1,19 @@
+variable "tags" {
+  type    = map(string)
+  default = {}
+}
+
+variable "labels" {
+  type    = map(string)
+  default = {}
+}
+
+locals {
+  bucket_labels = merge(var.labels, { component = "storage" })
+}
+
+resource "google_storage_bucket" "bucket" {
+  name     = "bucket"
+  location = "EU"
+  labels   = local.bucket_labels
+}
@@ -0,0 +1,50 @@
+resource "aws_autoscaling_group" "web" {
+  name     = "web"
+  max_size = 3
+  min_size = 1
+
+  tag {
+    key                 = "Name"
+    value               = "web"
+    propagate_at_launch = true
+  }
+
+  tag {
+    key                 = "env"
+    value               = "prod"
+    propagate_at_launch = false
+  }
+
+  dynamic "tag" {
+    for_each = var.extra_tags
+    content {
+      key                 = tag.key
+      value               = tag.value
+      propagate_at_launch = true
+    }
+  }
+
+  tag {
+    key                 = "yor_trace"
+    value               = "abc"
+    propagate_at_launch = false
+  }
+}
+
+resource "aws_autoscaling_group" "workers" {
+  name     = "workers"
+  max_size = 10
+  min_size = 0
+
+  tag {
+    key                 = "yor_trace"
+    value               = "abc"
+    propagate_at_launch = false
+  }
+
+  tag {
+    key                 = "env"
+    value               = "prod"
+    propagate_at_launch = false
+  }
+}
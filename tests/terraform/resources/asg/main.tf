@@ -0,0 +1,32 @@
+resource "aws_autoscaling_group" "web" {
+  name     = "web"
+  max_size = 3
+  min_size = 1
+
+  tag {
+    key                 = "Name"
+    value               = "web"
+    propagate_at_launch = true
+  }
+
+  tag {
+    key                 = "env"
+    value               = "dev"
+    propagate_at_launch = false
+  }
+
+  dynamic "tag" {
+    for_each = var.extra_tags
+    content {
+      key                 = tag.key
+      value               = tag.value
+      propagate_at_launch = true
+    }
+  }
+}
+
+resource "aws_autoscaling_group" "workers" {
+  name     = "workers"
+  max_size = 10
+  min_size = 0
+}
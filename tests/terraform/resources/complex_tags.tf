@@ -68,7 +68,7 @@ resource "aws_instance" "instance_null_tags" {
 }
 
 resource "aws_autoscaling_group" "autoscaling_group_tagged" {
-  // This resource should not be tagged
+  // This resource is tagged with tag blocks
   tag {
     key = "Name"
     propagate_at_launch = false
@@ -79,7 +79,7 @@ resource "aws_autoscaling_group" "autoscaling_group_tagged" {
 }
 
 resource "aws_autoscaling_group" "autoscaling_group" {
-  // This resource should not be tagged as well
+  // This resource is tagged with tag blocks as well
   max_size = 0
   min_size = 0
 }
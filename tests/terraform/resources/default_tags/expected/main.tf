@@ -0,0 +1,23 @@
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+  tags = {
+    yor_trace = "abc"
+  }
+}
+
+resource "aws_s3_bucket" "west" {
+  provider = aws.west
+  bucket   = "west"
+  tags = {
+    yor_trace = "abc"
+  }
+}
+
+resource "aws_instance" "web" {
+  ami           = "ami-123456"
+  instance_type = "t3.micro"
+  tags = {
+    env       = "prod"
+    yor_trace = "abc"
+  }
+}
@@ -0,0 +1,23 @@
+provider "aws" {
+  region = "us-east-1"
+
+  default_tags {
+    tags = {
+      env   = "dev"
+      team  = "platform"
+      owner = "infra"
+    }
+  }
+}
+
+provider "aws" {
+  alias  = "west"
+  region = "us-west-2"
+
+  default_tags {
+    tags = {
+      owner = "infra"
+      team  = "platform"
+    }
+  }
+}
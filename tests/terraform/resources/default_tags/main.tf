@@ -0,0 +1,16 @@
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket" "west" {
+  provider = aws.west
+  bucket   = "west"
+}
+
+resource "aws_instance" "web" {
+  ami           = "ami-123456"
+  instance_type = "t3.micro"
+  tags = {
+    env = "prod"
+  }
+}
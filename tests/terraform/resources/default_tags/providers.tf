@@ -0,0 +1,15 @@
+provider "aws" {
+  region = "us-east-1"
+
+  default_tags {
+    tags = {
+      env  = "dev"
+      team = "platform"
+    }
+  }
+}
+
+provider "aws" {
+  alias  = "west"
+  region = "us-west-2"
+}
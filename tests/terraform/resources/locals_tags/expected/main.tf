@@ -0,0 +1,33 @@
+locals {
+  env         = "prod"
+  env_tags    = { env = local.env }
+  common_tags = merge(var.default_tags, local.env_tags, { team = "platform" })
+}
+
+resource "aws_s3_bucket" "locals" {
+  bucket = "locals"
+  tags = merge(local.common_tags, {
+    env       = "dev"
+    yor_trace = "trace-id"
+  })
+}
+
+resource "aws_s3_bucket" "merged" {
+  bucket = "merged"
+  tags = merge(local.env_tags, data.aws_default_tags.current.tags, {
+    Name = "merged"
+    }, {
+    env       = "dev"
+    team      = "platform"
+    yor_trace = "trace-id"
+  })
+}
+
+resource "aws_s3_bucket" "unknown" {
+  bucket = "unknown"
+  tags = merge(var.extra_tags, {
+    env       = "dev"
+    team      = "platform"
+    yor_trace = "trace-id"
+  })
+}
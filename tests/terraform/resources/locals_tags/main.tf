@@ -0,0 +1,22 @@
+locals {
+  env         = "prod"
+  env_tags    = { env = local.env }
+  common_tags = merge(var.default_tags, local.env_tags, { team = "platform" })
+}
+
+resource "aws_s3_bucket" "locals" {
+  bucket = "locals"
+  tags   = local.common_tags
+}
+
+resource "aws_s3_bucket" "merged" {
+  bucket = "merged"
+  tags = merge(local.env_tags, data.aws_default_tags.current.tags, {
+    Name = "merged"
+  })
+}
+
+resource "aws_s3_bucket" "unknown" {
+  bucket = "unknown"
+  tags   = var.extra_tags
+}
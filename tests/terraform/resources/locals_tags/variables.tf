@@ -0,0 +1,10 @@
+variable "default_tags" {
+  type = map(string)
+  default = {
+    owner = "bridgecrew"
+  }
+}
+
+variable "extra_tags" {
+  type = map(string)
+}
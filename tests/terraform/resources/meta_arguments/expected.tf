@@ -0,0 +1,34 @@
+resource "aws_s3_bucket" "buckets" {
+  for_each = toset(["logs", "data"])
+  bucket   = each.key
+  tags = {
+    env                  = "dev"
+    git_last_modified_by = "$${path.cwd}\"@example.com"
+    git_modifiers        = "$${each.key}"
+    owner                = "${lookup(var.owners, "web")}"
+    team                 = "${each.key}-team"
+    yor_name             = "buckets-${each.key}"
+  }
+}
+
+resource "aws_instance" "web" {
+  count         = 2
+  ami           = "ami-123456"
+  instance_type = "t3.micro"
+  tags = {
+    git_last_modified_by = "$${path.cwd}\"@example.com"
+    git_modifiers        = "$${each.key}"
+    owner                = "${lookup(var.owners, "web")}"
+    yor_name             = "web-${count.index}"
+  }
+}
+
+resource "aws_sqs_queue" "queue" {
+  name = "queue"
+  tags = {
+    git_last_modified_by = "$${path.cwd}\"@example.com"
+    git_modifiers        = "$${each.key}"
+    owner                = "${lookup(var.owners, "web")}"
+    yor_name             = "queue"
+  }
+}
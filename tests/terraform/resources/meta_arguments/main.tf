@@ -0,0 +1,17 @@
+resource "aws_s3_bucket" "buckets" {
+  for_each = toset(["logs", "data"])
+  bucket   = each.key
+  tags = {
+    env = "dev"
+  }
+}
+
+resource "aws_instance" "web" {
+  count         = 2
+  ami           = "ami-123456"
+  instance_type = "t3.micro"
+}
+
+resource "aws_sqs_queue" "queue" {
+  name = "queue"
+}
@@ -0,0 +1,30 @@
+module "tagged" {
+  source = "terraform-aws-modules/vpc/aws"
+  tags = {
+    Name = "vpc"
+  }
+}
+
+module "labels" {
+  source = "git::https://example.com/terraform-google-labels.git"
+}
+
+module "no_tags" {
+  source = "git::https://example.com/terraform-aws-no-tags.git"
+}
+
+module "k8s" {
+  source = "git::https://example.com/terraform-kubernetes-namespace.git"
+}
+
+module "missing" {
+  source = "git::https://example.com/terraform-aws-missing.git"
+}
+
+module "helm" {
+  source = "terraform-module/release/helm"
+}
+
+module "local" {
+  source = "./modules/local"
+}
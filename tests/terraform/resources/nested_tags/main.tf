@@ -0,0 +1,70 @@
+resource "aws_instance" "web" {
+  ami           = "ami-123456"
+  instance_type = "t3.micro"
+
+  root_block_device {
+    volume_size = 20
+  }
+
+  dynamic "ebs_block_device" {
+    for_each = var.volumes
+    content {
+      device_name = ebs_block_device.value.device_name
+      volume_size = ebs_block_device.value.size
+      tags = {
+        Name = ebs_block_device.key
+      }
+    }
+  }
+
+  tags = {
+    Name = "web"
+  }
+}
+
+resource "aws_instance" "volume_tags" {
+  ami           = "ami-123456"
+  instance_type = "t3.micro"
+  volume_tags = {
+    Name = "volume"
+  }
+
+  root_block_device {
+    volume_size = 20
+  }
+}
+
+resource "aws_launch_template" "template" {
+  name = "template"
+
+  tag_specifications {
+    resource_type = "instance"
+    tags = {
+      Name = "instance"
+    }
+  }
+
+  tag_specifications {
+    resource_type = "volume"
+  }
+}
+
+resource "azurerm_kubernetes_cluster" "cluster" {
+  name                = "cluster"
+  location            = "westeurope"
+  resource_group_name = "rg"
+  dns_prefix          = "cluster"
+
+  default_node_pool {
+    name       = "default"
+    node_count = 1
+    vm_size    = "Standard_D2_v2"
+    tags = {
+      pool = "default"
+    }
+  }
+
+  identity {
+    type = "SystemAssigned"
+  }
+}
@@ -0,0 +1,24 @@
+resource "aws_s3_bucket" "bucket" {
+  bucket = "bucket"
+}
+
+resource "aws_s3_bucket_policy" "policy" {
+  bucket = aws_s3_bucket.bucket.id
+  policy = "{}"
+}
+
+resource "aws_sqs_queue" "queue" {
+  name = "queue"
+}
+
+resource "google_container_cluster" "cluster" {
+  name = "cluster"
+}
+
+resource "digitalocean_droplet" "web" {
+  name = "web"
+}
+
+resource "digitalocean_ssh_key" "key" {
+  name = "key"
+}
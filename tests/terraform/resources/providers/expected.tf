@@ -0,0 +1,47 @@
+resource "digitalocean_droplet" "web" {
+  image  = "ubuntu-22-04-x64"
+  name   = "web"
+  region = "nyc3"
+  size   = "s-1vcpu-1gb"
+  tags   = ["env:prod", "web", "yor_trace:abc", "git_file:src_main_tf"]
+}
+
+resource "linode_instance" "web" {
+  label = "web"
+  type  = "g6-nanode-1"
+  tags  = concat(var.tags, ["team:web"], ["yor_trace:abc", "git_file:src/main.tf", "env:prod"])
+}
+
+resource "ibm_is_vpc" "vpc" {
+  name = "vpc"
+  tags = concat(var.tags, ["yor_trace:abc", "git_file:src_main.tf", "env:prod"])
+}
+
+resource "huaweicloud_vpc" "vpc" {
+  name = "vpc"
+  cidr = "10.0.0.0/16"
+  tags = {
+    env       = "prod"
+    git_file  = "src_main.tf"
+    yor_trace = "abc"
+  }
+}
+
+resource "exoscale_compute_instance" "web" {
+  name = "web"
+  labels = {
+    env       = "prod"
+    git_file  = "src/main.tf"
+    yor_trace = "abc"
+  }
+}
+
+resource "mycloud_server" "web" {
+  name   = "web"
+  labels = ["env=prod", "yor_trace=abc", "git_file=src_main_tf"]
+}
+
+resource "vsphere_virtual_machine" "vm" {
+  name = "vm"
+  tags = [vsphere_tag.env.id]
+}
@@ -0,0 +1,43 @@
+resource "digitalocean_droplet" "web" {
+  image  = "ubuntu-22-04-x64"
+  name   = "web"
+  region = "nyc3"
+  size   = "s-1vcpu-1gb"
+  tags   = ["env:dev", "web"]
+}
+
+resource "linode_instance" "web" {
+  label = "web"
+  type  = "g6-nanode-1"
+  tags  = concat(var.tags, ["team:web"])
+}
+
+resource "ibm_is_vpc" "vpc" {
+  name = "vpc"
+  tags = var.tags
+}
+
+resource "huaweicloud_vpc" "vpc" {
+  name = "vpc"
+  cidr = "10.0.0.0/16"
+  tags = {
+    env = "dev"
+  }
+}
+
+resource "exoscale_compute_instance" "web" {
+  name = "web"
+  labels = {
+    env = "dev"
+  }
+}
+
+resource "mycloud_server" "web" {
+  name   = "web"
+  labels = ["env=dev"]
+}
+
+resource "vsphere_virtual_machine" "vm" {
+  name = "vm"
+  tags = [vsphere_tag.env.id]
+}
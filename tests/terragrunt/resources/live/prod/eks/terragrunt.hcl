@@ -0,0 +1,7 @@
+locals {
+  common = read_terragrunt_config(find_in_parent_folders("common.hcl"))
+}
+
+inputs = merge(local.common.inputs, {
+  cluster_name = "prod-eks"
+})
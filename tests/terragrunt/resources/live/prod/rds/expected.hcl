@@ -0,0 +1,12 @@
+terraform {
+  source = "../../../modules/rds"
+}
+
+inputs = {
+  identifier = "prod-db"
+  common_tags = {
+    env       = "production"
+    git_file  = "live/prod/terragrunt.hcl"
+    yor_trace = "3c1c2e4f-5a6b-4c7d-8e9f-0a1b2c3d4e5f"
+  }
+}
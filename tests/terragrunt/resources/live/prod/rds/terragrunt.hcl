@@ -0,0 +1,10 @@
+terraform {
+  source = "../../../modules/rds"
+}
+
+inputs = {
+  identifier = "prod-db"
+  common_tags = {
+    env = "prod"
+  }
+}
@@ -0,0 +1,15 @@
+locals {
+  common = read_terragrunt_config(find_in_parent_folders("common.hcl"))
+}
+
+terraform {
+  source = "../../../modules/s3"
+}
+
+inputs = {
+  tags = {
+    env       = "production"
+    git_file  = "live/prod/terragrunt.hcl"
+    yor_trace = "3c1c2e4f-5a6b-4c7d-8e9f-0a1b2c3d4e5f"
+  }
+}
@@ -0,0 +1,19 @@
+include "root" {
+  path = find_in_parent_folders()
+}
+
+terraform {
+  source = "tfr:///terraform-aws-modules/vpc/aws?version=5.0.0"
+}
+
+inputs = {
+  name = "prod-vpc"
+  cidr = "10.0.0.0/16"
+
+  tags = {
+    env       = "production"
+    team      = "network"
+    git_file  = "live/prod/terragrunt.hcl"
+    yor_trace = "3c1c2e4f-5a6b-4c7d-8e9f-0a1b2c3d4e5f"
+  }
+}
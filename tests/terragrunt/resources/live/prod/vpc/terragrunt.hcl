@@ -0,0 +1,17 @@
+include "root" {
+  path = find_in_parent_folders()
+}
+
+terraform {
+  source = "tfr:///terraform-aws-modules/vpc/aws?version=5.0.0"
+}
+
+inputs = {
+  name = "prod-vpc"
+  cidr = "10.0.0.0/16"
+
+  tags = {
+    env  = "prod"
+    team = "network"
+  }
+}